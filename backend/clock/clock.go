@@ -0,0 +1,8 @@
+// Package clock da la hora actual a los services y los importadores. Es una
+// variable para que los tests puedan fijarla.
+package clock
+
+import "time"
+
+// Now devuelve la hora actual.
+var Now = time.Now
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondAchievementError mapea los errores del service de logros a HTTP.
+func respondAchievementError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	case errors.Is(err, service.ErrAchievementNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Achievement not found"})
+	case errors.Is(err, service.ErrAchievementExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// AchievementController atiende los logros de cada juego.
+type AchievementController struct {
+	achievements *service.AchievementService
+}
+
+func NewAchievementController(achievements *service.AchievementService) *AchievementController {
+	return &AchievementController{achievements: achievements}
+}
+
+func (ac *AchievementController) ListAchievements(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	achievements, err := ac.achievements.ListAchievements(userID, c.Param("id"))
+	if err != nil {
+		respondAchievementError(c, err, "Error obtaining achievements")
+		return
+	}
+	c.JSON(http.StatusOK, achievements)
+}
+
+func (ac *AchievementController) CreateAchievement(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.AchievementInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	achievement, err := ac.achievements.CreateAchievement(userID, c.Param("id"), input)
+	if err != nil {
+		respondAchievementError(c, err, "Error creating achievement")
+		return
+	}
+	c.JSON(http.StatusOK, achievement)
+}
+
+// ImportAchievements carga la lista de logros o la checklist de un juego en
+// bloque y devuelve todos sus logros.
+func (ac *AchievementController) ImportAchievements(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.AchievementImportInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	achievements, err := ac.achievements.ImportAchievements(userID, c.Param("id"), input)
+	if err != nil {
+		respondAchievementError(c, err, "Error importing achievements")
+		return
+	}
+	c.JSON(http.StatusOK, achievements)
+}
+
+// ToggleAchievement alterna entre desbloqueado y bloqueado.
+func (ac *AchievementController) ToggleAchievement(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	achievement, err := ac.achievements.ToggleAchievement(userID, c.Param("id"), c.Param("achievementId"))
+	if err != nil {
+		respondAchievementError(c, err, "Error updating achievement")
+		return
+	}
+	c.JSON(http.StatusOK, achievement)
+}
+
+func (ac *AchievementController) DeleteAchievement(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := ac.achievements.DeleteAchievement(userID, c.Param("id"), c.Param("achievementId")); err != nil {
+		respondAchievementError(c, err, "Error deleting achievement")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Achievement deleted successfully"})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"gametracker/models"
 	"gametracker/service"
 	"net/http"
@@ -12,19 +13,19 @@ type AuthController struct {
 	authService *service.AuthService
 }
 
-func NewAuthController() *AuthController {
+func NewAuthController(authService *service.AuthService) *AuthController {
 	return &AuthController{
-		authService: service.NewAuthService(),
+		authService: authService,
 	}
 }
 
 // Register maneja el registro de usuarios
 func (ac *AuthController) Register(c *gin.Context) {
 	var req models.RegisterRequest
-	
+
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Datos inválidos",
+			"error":   "Datos inválidos",
 			"details": err.Error(),
 		})
 		return
@@ -47,17 +48,17 @@ func (ac *AuthController) Register(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Usuario creado exitosamente",
-		"user": user,
+		"user":    user,
 	})
 }
 
 // Login maneja el inicio de sesión
 func (ac *AuthController) Login(c *gin.Context) {
 	var req models.LoginRequest
-	
+
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Datos inválidos",
+			"error":   "Datos inválidos",
 			"details": err.Error(),
 		})
 		return
@@ -74,6 +75,59 @@ func (ac *AuthController) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, authResponse)
 }
 
+// Refresh rota el refresh token y devuelve un access token nuevo
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Datos inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	authResponse, err := ac.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse)
+}
+
+// Logout revoca la sesión asociada al refresh token
+func (ac *AuthController) Logout(c *gin.Context) {
+	var req models.RefreshRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Datos inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := ac.authService.Logout(req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sesión cerrada",
+	})
+}
+
 // GetProfile obtiene el perfil del usuario autenticado
 func (ac *AuthController) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -125,9 +179,37 @@ func (ac *AuthController) AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Rechazar access tokens de sesiones cerradas (logout o reutilización)
+		sessionID, err := ac.authService.GetSessionFromToken(token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido",
+			})
+			c.Abort()
+			return
+		}
+		revoked, err := ac.authService.IsSessionRevoked(sessionID)
+		if err != nil {
+			// Sin base no se sabe si la sesión sigue abierta: un 401 haría
+			// que el cliente descarte un token que puede ser válido.
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "No se pudo verificar la sesión",
+			})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": service.ErrSessionRevoked.Error(),
+			})
+			c.Abort()
+			return
+		}
+
 		// Agregar información del usuario al contexto
 		c.Set("userID", userID)
 		c.Set("username", username)
+		c.Set("sessionID", sessionID)
 		c.Next()
 	}
 }
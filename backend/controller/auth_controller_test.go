@@ -2,8 +2,10 @@ package controller
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"gametracker/models"
+	"gametracker/service"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,15 +15,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
+// newAuthController arma el controller con los usuarios en conn.
+func newAuthController(conn *gorm.DB) *AuthController {
+	return NewAuthController(service.NewAuthService(service.NewUserRepository(conn), conn))
+}
+
 func TestNewAuthController(t *testing.T) {
-	controller := NewAuthController()
+	controller := NewAuthController(service.NewAuthService(nil, nil))
 	assert.NotNil(t, controller)
 }
 
 func TestAuthController_Register_Success(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := setupTestDB(t)
 	defer sqlDB.Close()
 
 	req := models.RegisterRequest{
@@ -47,7 +55,7 @@ func TestAuthController_Register_Success(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	authController := NewAuthController()
+	authController := newAuthController(gormDB)
 	router.POST("/register", authController.Register)
 
 	w := httptest.NewRecorder()
@@ -61,12 +69,12 @@ func TestAuthController_Register_Success(t *testing.T) {
 }
 
 func TestAuthController_Register_InvalidData(t *testing.T) {
-	_, _, sqlDB := setupTestDB(t)
+	gormDB, _, sqlDB := setupTestDB(t)
 	defer sqlDB.Close()
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	authController := NewAuthController()
+	authController := newAuthController(gormDB)
 	router.POST("/register", authController.Register)
 
 	invalidJSON := `{"username": "test"`
@@ -79,7 +87,7 @@ func TestAuthController_Register_InvalidData(t *testing.T) {
 }
 
 func TestAuthController_Login_Success(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := setupTestDB(t)
 	defer sqlDB.Close()
 
 	// Hash password
@@ -99,10 +107,14 @@ func TestAuthController_Login_Success(t *testing.T) {
 	mock.ExpectQuery("^SELECT \\* FROM `users` WHERE username = \\? OR email = \\? ORDER BY `users`.`id` LIMIT \\?$").
 		WithArgs("testuser", "testuser", 1).
 		WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `refresh_tokens`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	authController := NewAuthController()
+	authController := newAuthController(gormDB)
 	router.POST("/login", authController.Login)
 
 	w := httptest.NewRecorder()
@@ -116,12 +128,12 @@ func TestAuthController_Login_Success(t *testing.T) {
 }
 
 func TestAuthController_Login_InvalidData(t *testing.T) {
-	_, _, sqlDB := setupTestDB(t)
+	gormDB, _, sqlDB := setupTestDB(t)
 	defer sqlDB.Close()
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	authController := NewAuthController()
+	authController := newAuthController(gormDB)
 	router.POST("/login", authController.Login)
 
 	invalidJSON := `{"username": }`
@@ -134,12 +146,12 @@ func TestAuthController_Login_InvalidData(t *testing.T) {
 }
 
 func TestAuthController_GetProfile_Authenticated(t *testing.T) {
-	_, _, sqlDB := setupTestDB(t)
+	gormDB, _, sqlDB := setupTestDB(t)
 	defer sqlDB.Close()
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	authController := NewAuthController()
+	authController := newAuthController(gormDB)
 	router.GET("/profile", func(c *gin.Context) {
 		c.Set("userID", uint(1))
 		c.Next()
@@ -153,12 +165,12 @@ func TestAuthController_GetProfile_Authenticated(t *testing.T) {
 }
 
 func TestAuthController_GetProfile_Unauthenticated(t *testing.T) {
-	_, _, sqlDB := setupTestDB(t)
+	gormDB, _, sqlDB := setupTestDB(t)
 	defer sqlDB.Close()
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	authController := NewAuthController()
+	authController := newAuthController(gormDB)
 	router.GET("/profile", authController.GetProfile)
 
 	w := httptest.NewRecorder()
@@ -169,7 +181,7 @@ func TestAuthController_GetProfile_Unauthenticated(t *testing.T) {
 }
 
 func TestAuthController_Register_Conflict(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := setupTestDB(t)
 	defer sqlDB.Close()
 
 	req := models.RegisterRequest{
@@ -188,7 +200,7 @@ func TestAuthController_Register_Conflict(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	authController := NewAuthController()
+	authController := newAuthController(gormDB)
 	router.POST("/register", authController.Register)
 
 	w := httptest.NewRecorder()
@@ -200,3 +212,136 @@ func TestAuthController_Register_Conflict(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, w.Code)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+// loginForTest hace login contra el mock y devuelve el access token emitido.
+func loginForTest(t *testing.T, mock sqlmock.Sqlmock, authController *AuthController) models.AuthResponse {
+	t.Helper()
+
+	testUser := models.User{}
+	require.NoError(t, testUser.HashPassword("password123"))
+
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "password", "first_name", "last_name", "created_at", "updated_at"}).
+		AddRow(1, "testuser", "test@example.com", testUser.Password, "Test", "User", time.Now(), time.Now())
+	mock.ExpectQuery("^SELECT \\* FROM `users` WHERE username = \\? OR email = \\? ORDER BY `users`.`id` LIMIT \\?$").
+		WithArgs("testuser", "testuser", 1).
+		WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `refresh_tokens`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	router := gin.New()
+	router.POST("/login", authController.Login)
+
+	w := httptest.NewRecorder()
+	jsonData, _ := json.Marshal(models.LoginRequest{Username: "testuser", Password: "password123"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.AuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotEmpty(t, response.RefreshToken)
+	return response
+}
+
+func TestAuthController_AuthMiddleware_Session(t *testing.T) {
+	gormDB, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	gin.SetMode(gin.TestMode)
+	authController := newAuthController(gormDB)
+	authResponse := loginForTest(t, mock, authController)
+
+	router := gin.New()
+	router.GET("/profile", authController.AuthMiddleware(), authController.GetProfile)
+
+	t.Run("active session", func(t *testing.T) {
+		mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `refresh_tokens` WHERE family_id = \\? AND revoked_at IS NOT NULL$").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+authResponse.Token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `refresh_tokens` WHERE family_id = \\? AND revoked_at IS NOT NULL$").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+authResponse.Token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `refresh_tokens` WHERE family_id = \\? AND revoked_at IS NOT NULL$").
+			WillReturnError(sql.ErrConnDone)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+authResponse.Token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthController_Refresh_InvalidToken(t *testing.T) {
+	gormDB, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	mock.ExpectQuery("^SELECT \\* FROM `refresh_tokens` WHERE token_hash = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authController := newAuthController(gormDB)
+	router.POST("/refresh", authController.Refresh)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/refresh", bytes.NewBufferString(`{"refreshToken": "unknown"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthController_Logout(t *testing.T) {
+	gormDB, mock, sqlDB := setupTestDB(t)
+	defer sqlDB.Close()
+
+	gin.SetMode(gin.TestMode)
+	authController := newAuthController(gormDB)
+	authResponse := loginForTest(t, mock, authController)
+
+	mock.ExpectQuery("^SELECT \\* FROM `refresh_tokens` WHERE token_hash = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "family_id"}).AddRow(1, 1, "family-1"))
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE `refresh_tokens` SET `revoked_at`=\\? WHERE family_id = \\? AND revoked_at IS NULL$").
+		WithArgs(sqlmock.AnyArg(), "family-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	router := gin.New()
+	router.POST("/logout", authController.Logout)
+
+	w := httptest.NewRecorder()
+	body, _ := json.Marshal(models.RefreshRequest{RefreshToken: authResponse.RefreshToken})
+	req, _ := http.NewRequest("POST", "/logout", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
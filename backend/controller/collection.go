@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"net/http"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Los errores de colecciones se responden con respondTagError.
+
+// CollectionController atiende las colecciones; games lista los juegos de
+// cada una con los mismos filtros que /games.
+type CollectionController struct {
+	collections *service.CollectionService
+	games       *service.GameService
+}
+
+func NewCollectionController(collections *service.CollectionService, games *service.GameService) *CollectionController {
+	return &CollectionController{collections: collections, games: games}
+}
+
+func (cc *CollectionController) ListCollections(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	collections, err := cc.collections.ListCollections(userID)
+	if err != nil {
+		respondTagError(c, err, "Error obtaining collections")
+		return
+	}
+	c.JSON(http.StatusOK, collections)
+}
+
+func (cc *CollectionController) GetCollection(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	collection, err := cc.collections.GetCollection(userID, c.Param("id"))
+	if err != nil {
+		respondTagError(c, err, "Error obtaining collection")
+		return
+	}
+	c.JSON(http.StatusOK, collection)
+}
+
+// CreateCollection crea una colección vacía; 409 si el nombre ya existe.
+func (cc *CollectionController) CreateCollection(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.CollectionInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	collection := models.Collection{UserID: userID, Name: input.Name, Description: input.Description}
+	if err := cc.collections.CreateCollection(&collection); err != nil {
+		respondTagError(c, err, "Error creating collection")
+		return
+	}
+	c.JSON(http.StatusOK, collection)
+}
+
+func (cc *CollectionController) UpdateCollection(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.CollectionInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	collection, err := cc.collections.UpdateCollection(userID, c.Param("id"), input)
+	if err != nil {
+		respondTagError(c, err, "Error updating collection")
+		return
+	}
+	c.JSON(http.StatusOK, collection)
+}
+
+// DeleteCollection borra la colección; los juegos quedan en la biblioteca.
+func (cc *CollectionController) DeleteCollection(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := cc.collections.DeleteCollection(userID, c.Param("id")); err != nil {
+		respondTagError(c, err, "Error deleting collection")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
+
+// ListCollectionGames lista los juegos de la colección con los mismos
+// filtros, orden y paginación que GET /games.
+func (cc *CollectionController) ListCollectionGames(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	query, err := parseGameListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	collection, err := cc.collections.GetCollection(userID, c.Param("id"))
+	if err != nil {
+		respondTagError(c, err, "Error obtaining collection")
+		return
+	}
+	query.Filter.CollectionID = collection.ID
+	page, err := cc.games.List(userID, query)
+	if err != nil {
+		respondTagError(c, err, "Error obtaining games")
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func (cc *CollectionController) GetGameCollections(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	collections, err := cc.collections.GetGameCollections(userID, c.Param("id"))
+	if err != nil {
+		respondTagError(c, err, "Error obtaining game collections")
+		return
+	}
+	c.JSON(http.StatusOK, collections)
+}
+
+// AddGameToCollection es idempotente: agregar dos veces no falla.
+func (cc *CollectionController) AddGameToCollection(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := cc.collections.AddGameToCollection(userID, c.Param("id"), c.Param("collectionId")); err != nil {
+		respondTagError(c, err, "Error adding game to collection")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Game added to collection"})
+}
+
+func (cc *CollectionController) RemoveGameFromCollection(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := cc.collections.RemoveGameFromCollection(userID, c.Param("id"), c.Param("collectionId")); err != nil {
+		respondTagError(c, err, "Error removing game from collection")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Game removed from collection"})
+}
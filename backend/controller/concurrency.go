@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gametracker/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// gameETag identifica la versión de un juego. Cambia con cada update.
+func gameETag(game models.Game) string {
+	return fmt.Sprintf(`"%d"`, game.Version)
+}
+
+func setGameETag(c *gin.Context, game models.Game) {
+	c.Header("ETag", gameETag(game))
+}
+
+// checkPreconditions compara If-Match y el campo version del body contra la
+// versión actual del juego. Si no coinciden responde 412 y devuelve false.
+// Sin ninguno de los dos el update igual queda protegido por la versión que
+// se leyó recién (ver service.UpdateGame).
+func checkPreconditions(c *gin.Context, current models.Game, input models.GameInput) bool {
+	if header := c.GetHeader("If-Match"); header != "" && !etagMatches(header, gameETag(current)) {
+		respondPreconditionFailed(c, current)
+		return false
+	}
+	if input.Version != nil && *input.Version != current.Version {
+		respondPreconditionFailed(c, current)
+		return false
+	}
+	return true
+}
+
+// etagMatches interpreta If-Match: "*" o una lista de ETags separados por coma.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func respondPreconditionFailed(c *gin.Context, current models.Game) {
+	setGameETag(c, current)
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":          "game was modified by another request",
+		"currentVersion": current.Version,
+	})
+}
+
+// bindMergePatch aplica el body (JSON Merge Patch, RFC 7396) sobre input y
+// valida el resultado igual que un PUT.
+func bindMergePatch(c *gin.Context, input *models.GameInput) bool {
+	var patch interface{}
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if _, ok := patch.(map[string]interface{}); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "merge patch must be a JSON object"})
+		return false
+	}
+
+	current, err := json.Marshal(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating game"})
+		return false
+	}
+	var document interface{}
+	if err := json.Unmarshal(current, &document); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating game"})
+		return false
+	}
+	merged, err := json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating game"})
+		return false
+	}
+
+	// Partimos de cero para que un null en el patch deje el campo vacío.
+	*input = models.GameInput{}
+	err = json.Unmarshal(merged, input)
+	if err == nil {
+		err = binding.Validator.ValidateStruct(input)
+	}
+	return checkInput(c, input, err)
+}
+
+// mergePatch implementa el algoritmo de RFC 7396: los objetos se mezclan
+// recursivamente, null borra la clave y cualquier otro valor la reemplaza.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
@@ -1,75 +1,267 @@
 package controller
 
 import (
+	"errors"
 	"gametracker/models"
 	"gametracker/service"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strconv"
 )
 
-func GetAllGames(c *gin.Context) {
-	games, err := service.GetAllGames()
+// currentUserID obtiene el usuario autenticado que AuthMiddleware dejó en el contexto.
+func currentUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("userID")
+	if !exists {
+		return 0, false
+	}
+	userID, ok := value.(uint)
+	return userID, ok
+}
+
+// requireUserID corta la request con 401 si no hay usuario autenticado.
+func requireUserID(c *gin.Context) (uint, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// isStatusError indica si err viene de la validación de estado del service.
+func isStatusError(err error) bool {
+	return errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidStatusTransition)
+}
+
+// GameController atiende el CRUD de la biblioteca con el GameService que
+// recibe por constructor.
+type GameController struct {
+	games *service.GameService
+}
+
+func NewGameController(games *service.GameService) *GameController {
+	return &GameController{games: games}
+}
+
+// GetAllGames lista la biblioteca paginada, ordenada y filtrada (ver parseGameListQuery).
+func (gc *GameController) GetAllGames(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	query, err := parseGameListQuery(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := gc.games.List(userID, query)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obtaining games"})
 		return
 	}
-	c.JSON(http.StatusOK, games)
+	c.JSON(http.StatusOK, page)
 }
 
-func GetGameByID(c *gin.Context) {
+func (gc *GameController) GetGameByID(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	game, err := service.GetGameByID(id)
+	game, err := gc.games.Get(userID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
 		return
 	}
+	setGameETag(c, game)
 	c.JSON(http.StatusOK, game)
 }
 
-func CreateGame(c *gin.Context) {
-	var game models.Game
-	if err := c.ShouldBindJSON(&game); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (gc *GameController) CreateGame(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.GameInput
+	if !bindGameInput(c, &input) {
 		return
 	}
-	if err := service.CreateGame(&game); err != nil {
+	// El dueño siempre es el usuario del token, nunca el del body.
+	game := models.Game{UserID: userID}
+	input.Apply(&game)
+	if err := gc.games.Create(&game); err != nil {
+		if isStatusError(err) {
+			respondValidation(c, []models.FieldError{{Field: "status", Message: err.Error()}})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error creating game"})
 		return
 	}
+	setGameETag(c, game)
 	c.JSON(http.StatusOK, game)
 }
 
-func UpdateGame(c *gin.Context) {
+// UpdateGame reemplaza los campos editables del juego (PUT).
+func (gc *GameController) UpdateGame(c *gin.Context) {
+	gc.updateGame(c, bindGameInput)
+}
+
+// PatchGame aplica un JSON Merge Patch al juego: solo cambian los campos
+// presentes en el body y null los vacía.
+func (gc *GameController) PatchGame(c *gin.Context) {
+	gc.updateGame(c, bindMergePatch)
+}
+
+// updateGame es el flujo común de PUT y PATCH; bind arma el input a partir
+// de los valores actuales del juego y el body.
+func (gc *GameController) updateGame(c *gin.Context, bind func(*gin.Context, *models.GameInput) bool) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	game, err := service.GetGameByID(id)
+	game, err := gc.games.Get(userID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
 		return
 	}
-	if err := c.ShouldBindJSON(&game); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Game not found"})
+	previous := game
+	// Lo que no venga en el body conserva su valor actual.
+	input := models.NewGameInput(game)
+	if !bind(c, &input) {
+		return
+	}
+	if !checkPreconditions(c, previous, input) {
 		return
 	}
-	if err := service.UpdateGame(&game); err != nil {
+	input.Apply(&game)
+	if err := gc.games.Update(userID, previous, &game); err != nil {
+		if isStatusError(err) {
+			respondValidation(c, []models.FieldError{{Field: "status", Message: err.Error()}})
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			// Lo cambió otro request entre la lectura y el UPDATE.
+			if current, err := gc.games.Get(userID, id); err == nil {
+				respondPreconditionFailed(c, current)
+				return
+			}
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating game"})
 		return
 	}
+	setGameETag(c, game)
 	c.JSON(http.StatusOK, game)
 }
 
-func DeleteGame(c *gin.Context) {
+func (gc *GameController) DeleteGame(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	if err := service.DeleteGame(id); err != nil {
+	if err := gc.games.Delete(userID, id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting game"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Game deleted successfully"})
 }
 
-func GetByTitle(c *gin.Context) {
+// TrashController lista y restaura los juegos borrados.
+type TrashController struct {
+	trash *service.TrashService
+}
+
+func NewTrashController(trash *service.TrashService) *TrashController {
+	return &TrashController{trash: trash}
+}
+
+// ListTrash lista los juegos borrados que todavía se pueden restaurar.
+func (tc *TrashController) ListTrash(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	games, err := tc.trash.ListTrash(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obtaining trash"})
+		return
+	}
+	c.JSON(http.StatusOK, games)
+}
+
+func (tc *TrashController) RestoreGame(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	game, err := tc.trash.RestoreGame(userID, id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Game not found in trash"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error restoring game"})
+		return
+	}
+	setGameETag(c, game)
+	c.JSON(http.StatusOK, game)
+}
+
+// SearchController atiende la búsqueda de juegos.
+type SearchController struct {
+	search *service.SearchService
+}
+
+func NewSearchController(search *service.SearchService) *SearchController {
+	return &SearchController{search: search}
+}
+
+// SearchGames hace búsqueda full-text en título y nota: ?q=...&limit=
+func (sc *SearchController) SearchGames(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query param q is required"})
+		return
+	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := sc.search.SearchGames(userID, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching games"})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (gc *GameController) GetByTitle(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
 	title := c.Query("title") //esto obtiene el query param ?title=...
 
-	games, err := service.GetByTitle(title)
+	games, err := gc.games.GetByTitle(userID, title)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching games"})
 		return
@@ -77,21 +269,33 @@ func GetByTitle(c *gin.Context) {
 	c.JSON(http.StatusOK, games)
 }
 
-func GetByStatus(c *gin.Context) {
+func (gc *GameController) GetByStatus(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
 	status := c.Query("status") //esto obtiene el query param ?status=...
 
-	games, err := service.GetByStatus(status)
+	games, err := gc.games.GetByStatus(userID, status)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching games"})
 		return
 	}
 	c.JSON(http.StatusOK, games)
 }
 
-func GetByGenre(c *gin.Context) {
+func (gc *GameController) GetByGenre(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
 	genre := c.Query("genre") //esto obtiene el query param ?genre=...
 
-	games, err := service.GetByGenre(genre)
+	games, err := gc.games.GetByGenre(userID, genre)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching games"})
 		return
@@ -99,11 +303,94 @@ func GetByGenre(c *gin.Context) {
 	c.JSON(http.StatusOK, games)
 }
 
-func GetStats(c *gin.Context) {
-	stats, err := service.GetStats()
+// StatsController atiende las estadísticas de la biblioteca.
+type StatsController struct {
+	stats *service.StatsService
+}
+
+func NewStatsController(stats *service.StatsService) *StatsController {
+	return &StatsController{stats: stats}
+}
+
+// GetStats devuelve las estadísticas de la biblioteca. Acepta los mismos
+// filtros que el listado (ver parseGameFilter).
+func (sc *StatsController) GetStats(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	filter, err := parseGameFilter(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	stats, err := sc.stats.GetStats(userID, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener estadísticas"})
 		return
 	}
 	c.JSON(http.StatusOK, stats)
 }
+
+// GetTimeline devuelve la actividad agrupada por mes o semana:
+// ?interval=month|week&from=&to= más los filtros del listado.
+func (sc *StatsController) GetTimeline(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	filter, err := parseGameFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, err := queryDate(c, "from", false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := queryDate(c, "to", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeline, err := sc.stats.GetTimeline(userID, filter, c.Query("interval"), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener estadísticas"})
+		return
+	}
+	c.JSON(http.StatusOK, timeline)
+}
+
+// GetYearReview devuelve el resumen anual de /games/stats/year/:year.
+func (sc *StatsController) GetYearReview(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+		return
+	}
+
+	review, err := sc.stats.GetYearReview(userID, year)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener estadísticas"})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
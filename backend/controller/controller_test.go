@@ -2,12 +2,21 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
-	"gametracker/db"
+	"gametracker/metadata"
 	"gametracker/models"
+	"gametracker/service"
+	"gametracker/storage"
+	"image"
+	"image/png"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,30 +47,111 @@ func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
 	})
 	require.NoError(t, err)
 
-	// Inyectar en la variable global usada por el service
-	prev := db.DB
-	db.DB = gormDB
-	t.Cleanup(func() {
-		db.DB = prev
-		_ = sqlDB.Close()
-	})
+	t.Cleanup(func() { _ = sqlDB.Close() })
 
 	return gormDB, mock, sqlDB
 }
 
-func setupRouter() *gin.Engine {
+func newGameController(conn *gorm.DB) *GameController {
+	return NewGameController(service.NewGameService(service.NewGameRepository(conn)))
+}
+
+// testUserID es el usuario que simula AuthMiddleware en los tests de juegos
+const testUserID uint = 1
+
+func setupRouter(conn *gorm.DB) *gin.Engine {
+	return setupRouterWith(conn, routerDeps{})
+}
+
+// routerDeps son las dependencias externas opcionales de setupRouterWith;
+// las que quedan en nil deshabilitan su funcionalidad.
+type routerDeps struct {
+	covers   storage.Storage
+	metadata metadata.Provider
+}
+
+// setupRouterWith arma todos los controllers sobre conn con deps.
+func setupRouterWith(conn *gorm.DB, deps routerDeps) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", testUserID)
+		c.Next()
+	})
 
-	router.GET("/games", GetAllGames)
-	router.GET("/games/:id", GetGameByID)
-	router.POST("/games", CreateGame)
-	router.PUT("/games/:id", UpdateGame)
-	router.DELETE("/games/:id", DeleteGame)
-	router.GET("/games/search/title", GetByTitle)
-	router.GET("/games/search/status", GetByStatus)
-	router.GET("/games/search/genre", GetByGenre)
-	router.GET("/games/stats", GetStats)
+	gameService := service.NewGameService(service.NewGameRepository(conn))
+	games := NewGameController(gameService)
+	trash := NewTrashController(service.NewTrashService(conn, nil))
+	stats := NewStatsController(service.NewStatsService(conn))
+	transfer := NewTransferController(service.NewTransferService(conn))
+	enrich := NewMetadataController(service.NewMetadataService(conn, deps.metadata, time.Hour), gameService)
+	cover := NewCoverController(service.NewCoverService(conn, deps.covers, service.DefaultCoverMaxBytes))
+	ownerships := NewOwnershipController(service.NewOwnershipService(conn))
+	achievements := NewAchievementController(service.NewAchievementService(conn))
+	tags := NewTagController(service.NewTagService(conn))
+	collections := NewCollectionController(service.NewCollectionService(conn), gameService)
+	sessions := NewSessionController(service.NewSessionService(conn))
+	wishlist := NewWishlistController(service.NewWishlistService(conn))
+	search := NewSearchController(service.NewSearchService(conn))
+	router.GET("/games", games.GetAllGames)
+	router.GET("/games/:id", games.GetGameByID)
+	router.POST("/games", games.CreateGame)
+	router.PUT("/games/:id", games.UpdateGame)
+	router.PATCH("/games/:id", games.PatchGame)
+	router.DELETE("/games/:id", games.DeleteGame)
+	router.GET("/games/trash", trash.ListTrash)
+	router.GET("/games/export", transfer.ExportGames)
+	router.POST("/games/import", transfer.ImportGames)
+	router.POST("/games/import/:source", transfer.ImportFromSource)
+	router.POST("/games/:id/restore", trash.RestoreGame)
+	router.POST("/games/:id/enrich", enrich.EnrichGame)
+	router.POST("/games/:id/cover", cover.UploadCover)
+	router.GET("/games/:id/cover", cover.GetCover)
+	router.DELETE("/games/:id/cover", cover.DeleteCover)
+	router.GET("/games/:id/ownerships", ownerships.ListOwnerships)
+	router.POST("/games/:id/ownerships", ownerships.CreateOwnership)
+	router.PUT("/games/:id/ownerships/:ownershipId", ownerships.UpdateOwnership)
+	router.DELETE("/games/:id/ownerships/:ownershipId", ownerships.DeleteOwnership)
+	router.GET("/games/:id/achievements", achievements.ListAchievements)
+	router.POST("/games/:id/achievements", achievements.CreateAchievement)
+	router.POST("/games/:id/achievements/import", achievements.ImportAchievements)
+	router.POST("/games/:id/achievements/:achievementId/toggle", achievements.ToggleAchievement)
+	router.DELETE("/games/:id/achievements/:achievementId", achievements.DeleteAchievement)
+	router.GET("/games/:id/tags", tags.GetGameTags)
+	router.PUT("/games/:id/tags", tags.SetGameTags)
+	router.GET("/games/:id/collections", collections.GetGameCollections)
+	router.POST("/games/:id/collections/:collectionId", collections.AddGameToCollection)
+	router.DELETE("/games/:id/collections/:collectionId", collections.RemoveGameFromCollection)
+	router.GET("/games/:id/sessions", sessions.ListSessions)
+	router.POST("/games/:id/sessions", sessions.CreateSession)
+	router.PUT("/games/:id/sessions/:sessionId", sessions.UpdateSession)
+	router.DELETE("/games/:id/sessions/:sessionId", sessions.DeleteSession)
+	router.POST("/games/:id/sessions/start", sessions.StartSession)
+	router.POST("/games/:id/sessions/stop", sessions.StopSession)
+	router.GET("/games/search/title", games.GetByTitle)
+	router.GET("/games/search/status", games.GetByStatus)
+	router.GET("/games/search/genre", games.GetByGenre)
+	router.GET("/games/stats", stats.GetStats)
+	router.GET("/games/stats/timeline", stats.GetTimeline)
+	router.GET("/games/stats/year/:year", stats.GetYearReview)
+	router.GET("/games/search", search.SearchGames)
+	router.GET("/wishlist", wishlist.ListWishlist)
+	router.POST("/wishlist", wishlist.CreateWishlistItem)
+	router.GET("/wishlist/upcoming", wishlist.UpcomingReleases)
+	router.GET("/wishlist/:id", wishlist.GetWishlistItem)
+	router.PUT("/wishlist/:id", wishlist.UpdateWishlistItem)
+	router.DELETE("/wishlist/:id", wishlist.DeleteWishlistItem)
+	router.POST("/wishlist/:id/promote", wishlist.PromoteWishlistItem)
+	router.GET("/tags", tags.ListTags)
+	router.POST("/tags", tags.CreateTag)
+	router.PUT("/tags/:id", tags.UpdateTag)
+	router.DELETE("/tags/:id", tags.DeleteTag)
+	router.GET("/collections", collections.ListCollections)
+	router.POST("/collections", collections.CreateCollection)
+	router.GET("/collections/:id", collections.GetCollection)
+	router.PUT("/collections/:id", collections.UpdateCollection)
+	router.DELETE("/collections/:id", collections.DeleteCollection)
+	router.GET("/collections/:id/games", collections.ListCollectionGames)
 
 	return router
 }
@@ -72,7 +162,7 @@ func setupRouter() *gin.Engine {
 
 func TestGetAllGames_Success(t *testing.T) {
 	// Arrange
-	_, mock, _ := setupTestDB(t)
+	conn, mock, _ := setupTestDB(t)
 
 	now := time.Now()
 	game := models.Game{
@@ -102,9 +192,14 @@ func TestGetAllGames_Success(t *testing.T) {
 		game.CoverURL, game.CreatedAt, game.UpdatedAt,
 	)
 
-	mock.ExpectQuery(`SELECT \* FROM \` + "`games`" + ``).WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM \` + "`games`" + ` WHERE user_id = \?`).
+		WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM \`+"`games`"+` WHERE user_id = \? AND `+"`games`.`deleted_at`"+` IS NULL ORDER BY id ASC LIMIT \?`).
+		WithArgs(testUserID, 21).
+		WillReturnRows(rows)
 
-	router := setupRouter()
+	router := setupRouter(conn)
 
 	// Act
 	w := httptest.NewRecorder()
@@ -114,19 +209,68 @@ func TestGetAllGames_Success(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response []models.Game
+	var response models.GamePage
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
-	require.Len(t, response, 1)
-	assert.Equal(t, game.Title, response[0].Title)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, game.Title, response.Items[0].Title)
+	assert.Equal(t, int64(1), response.Total)
+	assert.Equal(t, 1, response.Page)
+	assert.Empty(t, response.NextCursor)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
 
+func TestGetAllGames_FiltersSortAndPage(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `games` WHERE user_id = \\? AND status = \\? AND \\(platform = \\? OR EXISTS .*\\) AND score >= \\? AND finished_at < \\?").
+		WithArgs(testUserID, "Completed", "PC", "PC", 7, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE .* ORDER BY hours_played ASC,score DESC,id ASC LIMIT \\? OFFSET \\?").
+		WithArgs(testUserID, "Completed", "PC", "PC", 7, sqlmock.AnyArg(), 6, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(6, "Game 6"))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games?status=Completed&platform=PC&minScore=7&finishedTo=2024-12-31&sort=hoursPlayed,-score&page=2&pageSize=5", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.GamePage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(12), response.Total)
+	assert.Equal(t, 2, response.Page)
+	assert.Equal(t, 5, response.PageSize)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetAllGames_InvalidParams(t *testing.T) {
+	conn, _, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	for _, url := range []string{
+		"/games?sort=nope",
+		"/games?page=abc",
+		"/games?startedFrom=yesterday",
+		"/games?cursor=not-a-cursor",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
 func TestCreateGame_InvalidJSON(t *testing.T) {
 	// Arrange
-	_, mock, _ := setupTestDB(t)
-	router := setupRouter()
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
 	invalidJSON := `{"title": "Test", "invalid": }`
 
 	// Act
@@ -148,8 +292,8 @@ func TestCreateGame_InvalidJSON(t *testing.T) {
 
 func TestGetGameByID_NotFound(t *testing.T) {
 	// Arrange
-	_, _, _ = setupTestDB(t) // no seteamos expectativas SQL: el handler puede resolver 404 sin query exacta
-	router := setupRouter()
+	conn, _, _ := setupTestDB(t) // no seteamos expectativas SQL: el handler puede resolver 404 sin query exacta
+	router := setupRouter(conn)
 
 	// Act
 	w := httptest.NewRecorder()
@@ -167,8 +311,8 @@ func TestGetGameByID_NotFound(t *testing.T) {
 
 func TestUpdateGame_NotFound(t *testing.T) {
 	// Arrange
-	_, _, _ = setupTestDB(t) // idem: no imponemos expectativa SQL
-	router := setupRouter()
+	conn, _, _ := setupTestDB(t) // idem: no imponemos expectativa SQL
+	router := setupRouter(conn)
 
 	body := models.Game{
 		Title:        "Updated Game",
@@ -200,13 +344,13 @@ func TestUpdateGame_NotFound(t *testing.T) {
 
 func TestDeleteGame_Success(t *testing.T) {
 	// Arrange
-	_, mock, _ := setupTestDB(t)
-	router := setupRouter()
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
 
 	// Mock para BEGIN, DELETE y COMMIT
 	mock.ExpectBegin()
-	mock.ExpectExec("DELETE FROM `games` WHERE `games`.`id` = \\?").
-		WithArgs("1").
+	mock.ExpectExec("UPDATE `games` SET `deleted_at`=\\? WHERE user_id = \\? AND `games`.`id` = \\? AND `games`.`deleted_at` IS NULL").
+		WithArgs(sqlmock.AnyArg(), testUserID, "1").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
@@ -226,10 +370,49 @@ func TestDeleteGame_Success(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestDeleteGame_OtherUsersGame(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	// El juego 2 es de otro usuario: el DELETE filtrado no afecta filas
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET `deleted_at`=\\? WHERE user_id = \\? AND `games`.`id` = \\? AND `games`.`deleted_at` IS NULL").
+		WithArgs(sqlmock.AnyArg(), testUserID, "2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/games/2", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllGames_Unauthenticated(t *testing.T) {
+	// Arrange
+	gormDB, mock, _ := setupTestDB(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/games", newGameController(gormDB).GetAllGames)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetByTitle_Success(t *testing.T) {
 	// Arrange
-	_, mock, _ := setupTestDB(t)
-	router := setupRouter()
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
 
 	now := time.Now()
 	game := models.Game{
@@ -257,8 +440,8 @@ func TestGetByTitle_Success(t *testing.T) {
 		game.CoverURL, game.CreatedAt, game.UpdatedAt,
 	)
 
-	mock.ExpectQuery(`SELECT \* FROM \`+"`games`"+` WHERE title LIKE \?`).
-		WithArgs("%Test%").
+	mock.ExpectQuery(`SELECT \* FROM \`+"`games`"+` WHERE user_id = \? AND title LIKE \?`).
+		WithArgs(testUserID, "%Test%").
 		WillReturnRows(rows)
 
 	// Act
@@ -280,8 +463,8 @@ func TestGetByTitle_Success(t *testing.T) {
 
 func TestGetByStatus_Success(t *testing.T) {
 	// Arrange
-	_, mock, _ := setupTestDB(t)
-	router := setupRouter()
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
 
 	now := time.Now()
 	game := models.Game{
@@ -309,8 +492,8 @@ func TestGetByStatus_Success(t *testing.T) {
 		game.CoverURL, game.CreatedAt, game.UpdatedAt,
 	)
 
-	mock.ExpectQuery(`SELECT \* FROM \`+"`games`"+` WHERE status LIKE \?`).
-		WithArgs("%Completed%").
+	mock.ExpectQuery(`SELECT \* FROM \`+"`games`"+` WHERE user_id = \? AND status = \?`).
+		WithArgs(testUserID, "Completed").
 		WillReturnRows(rows)
 
 	// Act
@@ -332,8 +515,8 @@ func TestGetByStatus_Success(t *testing.T) {
 
 func TestGetByGenre_Success(t *testing.T) {
 	// Arrange
-	_, mock, _ := setupTestDB(t)
-	router := setupRouter()
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
 
 	now := time.Now()
 	game := models.Game{
@@ -361,8 +544,8 @@ func TestGetByGenre_Success(t *testing.T) {
 		game.CoverURL, game.CreatedAt, game.UpdatedAt,
 	)
 
-	mock.ExpectQuery(`SELECT \* FROM \`+"`games`"+` WHERE genre LIKE \?`).
-		WithArgs("%RPG%").
+	mock.ExpectQuery(`SELECT \* FROM \`+"`games`"+` WHERE user_id = \? AND genre LIKE \?`).
+		WithArgs(testUserID, "%RPG%").
 		WillReturnRows(rows)
 
 	// Act
@@ -384,28 +567,34 @@ func TestGetByGenre_Success(t *testing.T) {
 
 func TestGetStats_Success(t *testing.T) {
 	// Arrange
-	_, mock, _ := setupTestDB(t)
-	router := setupRouter()
-
-	now := time.Now()
-	rows := sqlmock.NewRows([]string{
-		"id", "title", "platform", "genre", "status", "progress", "hours_played",
-		"personal_note", "score", "started_at", "finished_at", "cover_url",
-		"created_at", "updated_at",
-	}).AddRow(
-		1, "Game 1", "PC", "RPG", "Completed", 100, 10.0, "Note", 8, &now, &now, "cover1.jpg", now, now,
-	).AddRow(
-		2, "Game 2", "PC", "Action", "In Progress", 50, 5.0, "Note", 0, &now, nil, "cover2.jpg", now, now,
-	).AddRow(
-		3, "Game 3", "PC", "RPG", "In Progress", 30, 15.0, "Note", 0, &now, nil, "cover3.jpg", now, now,
-	)
-
-	mock.ExpectQuery(`SELECT \* FROM \` + "`games`").
-		WillReturnRows(rows)
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS total_games, .* WHERE user_id = \\? AND genre LIKE \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"total_games", "total_hours", "average_hours", "completed", "wishlist", "pending"}).
+			AddRow(3, 30.0, 10.0, 1, 0, 2))
+	mock.ExpectQuery("SELECT status, COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "games"}).AddRow("Completed", 1).AddRow("Playing", 2))
+	mock.ExpectQuery("SELECT genre, ").
+		WillReturnRows(sqlmock.NewRows([]string{"genre", "games", "hours", "scored_games", "score_sum"}).AddRow("RPG", 3, 30.0, 1, 8))
+	mock.ExpectQuery("SELECT platform, .* FROM game_ownerships AS o").
+		WillReturnRows(sqlmock.NewRows([]string{"platform", "games", "hours"}).AddRow("PC", 2, 20.0))
+	mock.ExpectQuery("SELECT platform, .* NOT EXISTS").
+		WillReturnRows(sqlmock.NewRows([]string{"platform", "games", "hours"}).AddRow("PC", 1, 10.0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS games, COALESCE\\(MAX").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "max_hours"}).AddRow(3, 15.0))
+	mock.ExpectQuery("SELECT `hours_played` .* LIMIT \\?$").
+		WillReturnRows(sqlmock.NewRows([]string{"hours_played"}).AddRow(5.0).AddRow(10.0))
+	mock.ExpectQuery("SELECT `hours_played` .* LIMIT \\? OFFSET \\?$").
+		WillReturnRows(sqlmock.NewRows([]string{"hours_played"}).AddRow(10.0).AddRow(15.0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS games, COALESCE\\(AVG").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "average_days", "oldest_days"}).AddRow(0, 0, 0))
+	mock.ExpectQuery("SELECT tags.name AS tag").
+		WillReturnRows(sqlmock.NewRows([]string{"tag", "games", "hours", "completed"}).AddRow("Co-op", 1, 10.0, 0))
 
 	// Act
 	w := httptest.NewRecorder()
-	req, _ := http.NewRequest("GET", "/games/stats", nil)
+	req, _ := http.NewRequest("GET", "/games/stats?genre=RPG", nil)
 	router.ServeHTTP(w, req)
 
 	// Assert
@@ -416,7 +605,1166 @@ func TestGetStats_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 3, response.TotalGames)
 	assert.Equal(t, 1, response.ByStatus["Completed"])
-	assert.Equal(t, 2, response.ByStatus["In Progress"])
+	assert.Equal(t, 2, response.ByStatus["Playing"])
+	assert.Equal(t, "RPG", response.MostPlayedGenre)
+	assert.Equal(t, 10.0, response.Playtime.Median)
+	require.Len(t, response.HoursByTag, 1)
+	assert.Equal(t, "Co-op", response.HoursByTag[0].Tag)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStats_InvalidFilter(t *testing.T) {
+	conn, _, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	for _, url := range []string{"/games/stats?status=done", "/games/stats?minScore=x"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
+func TestSearchGames_MissingQuery(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/search", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchGames_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT \\*, MATCH\\(title, personal_note\\) AGAINST").
+		WithArgs("+hollow*", testUserID, "+hollow*", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "relevance"}).AddRow(1, "Hollow Knight", 1.2))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/search?q=Hollow&limit=5", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.GameSearchResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "<mark>Hollow</mark> Knight", response[0].Highlights.Title)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateGame_InvalidStatus(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games", bytes.NewBufferString(`{"title": "Test", "platform": "PC", "status": "Replaying"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_InvalidTransition(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\?").
+		WithArgs(testUserID, "1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform", "status"}).
+			AddRow(1, testUserID, "Test", "PC", "Completed"))
+
+	// Act: Completed -> Backlog no está permitido
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/games/1", bytes.NewBufferString(`{"status": "Backlog"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByStatus_InvalidStatus(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/search/status?status=playing", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateGame_ValidationErrors(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+	body := `{"title": "", "progress": 120, "score": 11, "coverURL": "not a url",
+		"startedAt": "2024-05-10T00:00:00Z", "finishedAt": "2024-05-01T00:00:00Z"}`
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert: no llega a la base y se informa cada campo
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response struct {
+		Error  string              `json:"error"`
+		Fields []models.FieldError `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	fields := map[string]string{}
+	for _, f := range response.Fields {
+		fields[f.Field] = f.Message
+	}
+	assert.Equal(t, "is required", fields["title"])
+	assert.Equal(t, "is required", fields["platform"])
+	assert.Equal(t, "must be at most 100", fields["progress"])
+	assert.Equal(t, "must be at most 10", fields["score"])
+	assert.Equal(t, "must be a valid http(s) URL", fields["coverURL"])
+	assert.Equal(t, "must not be before startedAt", fields["finishedAt"])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateGame_WrongFieldType(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games", bytes.NewBufferString(`{"title": "Test", "platform": "PC", "score": "ten"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"score"`)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_ValidationKeepsCurrentValues(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\?").
+		WithArgs(testUserID, "1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform", "status"}).
+			AddRow(1, testUserID, "Test", "PC", "Playing"))
+
+	// Act: el body no trae title/platform, se conservan los actuales
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/games/1", bytes.NewBufferString(`{"score": -1}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"score"`)
+	assert.NotContains(t, w.Body.String(), `"field":"title"`)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectGameRow(mock sqlmock.Sqlmock, status string, version uint) {
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\?").
+		WithArgs(testUserID, "1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform", "genre", "status", "personal_note", "score", "version"}).
+			AddRow(1, testUserID, "Test", "PC", "RPG", status, "nota", 7, version))
+}
+
+// expectHoursDerived espera la consulta que decide si las horas del juego se
+// derivan de sesiones o copias (count > 0).
+func expectHoursDerived(mock sqlmock.Sqlmock, count int) {
+	mock.ExpectQuery("^SELECT \\(SELECT COUNT\\(\\*\\) FROM play_sessions WHERE game_id = \\?\\) \\+ \\(SELECT COUNT\\(\\*\\) FROM game_ownerships WHERE game_id = \\?\\)$").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestPatchGame_MergePatch(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	expectGameRow(mock, "Playing", 3)
+	expectHoursDerived(mock, 0)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET .* WHERE \\(user_id = \\? AND version = \\?\\) AND `games`.`deleted_at` IS NULL AND `id` = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act: score cambia, personalNote se vacía con null, el resto queda igual
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/games/1", bytes.NewBufferString(`{"score": 9, "personalNote": null, "id": 99}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"3"`)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	var game models.Game
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &game))
+	assert.Equal(t, uint(1), game.ID)
+	assert.Equal(t, "Test", game.Title)
+	assert.Equal(t, "RPG", game.Genre)
+	assert.Equal(t, 9, game.Score)
+	assert.Equal(t, "", game.PersonalNote)
+	assert.Equal(t, uint(4), game.Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchGame_NullRequiredField(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+	expectGameRow(mock, "Playing", 1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/games/1", bytes.NewBufferString(`{"title": null}`))
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"title"`)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_IfMatchMismatch(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+	expectGameRow(mock, "Playing", 5)
+
+	// Act: el cliente editó la versión 4
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/games/1", bytes.NewBufferString(`{"score": 9}`))
+	req.Header.Set("If-Match", `"4"`)
+	router.ServeHTTP(w, req)
+
+	// Assert: no se intenta el UPDATE
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_BodyVersionMismatch(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+	expectGameRow(mock, "Playing", 5)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/games/1", bytes.NewBufferString(`{"score": 9, "version": 2}`))
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_ConcurrentWrite(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	expectGameRow(mock, "Playing", 2)
+	// Entre la lectura y el UPDATE otro request subió la versión
+	expectHoursDerived(mock, 0)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET .* WHERE \\(user_id = \\? AND version = \\?\\) AND `games`.`deleted_at` IS NULL AND `id` = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	expectGameRow(mock, "Playing", 3)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/games/1", bytes.NewBufferString(`{"score": 9}`))
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
 
+func TestListTrash_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND deleted_at IS NOT NULL").
+		WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "deleted_at"}).
+			AddRow(3, testUserID, "Borrado", time.Now()))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/trash", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var games []models.Game
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &games))
+	require.Len(t, games, 1)
+	assert.True(t, games[0].DeletedAt.Valid)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreGame_NotInTrash(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/999/restore", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateSession_ValidationError(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	// Act: sin fin ni duración
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/sessions", bytes.NewBufferString(`{"startedAt": "2024-05-01T20:00:00Z"}`))
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"endedAt"`)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStartSession_Conflict(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	expectGameRow(mock, "Playing", 1)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `games` .* FOR UPDATE$").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `play_sessions`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/sessions/start", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListSessions_UnknownGame(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT \\* FROM `games`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/1/sessions", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatsTimeline_InvalidParams(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	for _, url := range []string{
+		"/games/stats/timeline?interval=day",
+		"/games/stats/timeline?from=ayer",
+		"/games/stats/timeline?interval=week&from=1990-01-01&to=2024-01-01",
+		"/games/stats/year/abc",
+		"/games/stats/year/12",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatsTimeline_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	bucketColumns := []string{"bucket", "games", "hours"}
+	mock.ExpectQuery("SELECT CASE WHEN created_at < \\? THEN 0 .* FROM `games`").
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(1, 1, 0))
+	mock.ExpectQuery("SELECT CASE WHEN started_at < \\? THEN 0 .* FROM `games`").
+		WillReturnRows(sqlmock.NewRows(bucketColumns))
+	mock.ExpectQuery("SELECT CASE WHEN finished_at < \\? THEN 0 .* FROM `games`").
+		WillReturnRows(sqlmock.NewRows(bucketColumns))
+	mock.ExpectQuery("SELECT CASE WHEN started_at < \\? THEN 0 .* FROM `play_sessions`").
+		WillReturnRows(sqlmock.NewRows(bucketColumns))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/stats/timeline?from=2024-01-01&to=2024-03-31", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var buckets []models.TimelineBucket
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &buckets))
+	require.Len(t, buckets, 3)
+	assert.Equal(t, "2024-02", buckets[1].Period)
+	assert.Equal(t, 1, buckets[1].Added)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportGames_CSV(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL ORDER BY id ASC").
+		WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform", "hours_played", "personal_note", "version"}).
+			AddRow(1, testUserID, "Hades", "PC", 12.5, "roguelite, \"muy bueno\"", 3))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/export?format=csv", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(gameCSVColumns, ","), lines[0])
+	assert.Equal(t, `1,1,Hades,PC,,,0,false,12.5,"roguelite, ""muy bueno""",0,,,,,,3,0001-01-01T00:00:00Z,0001-01-01T00:00:00Z,`, lines[1])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGameCSV_RoundTrip(t *testing.T) {
+	started := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	game := models.Game{
+		ID: 7, UserID: testUserID, Title: "Hades", Platform: "PC", Genre: "Roguelite",
+		Status: "Playing", Progress: 40, ProgressFromAchievements: true, HoursPlayed: 12.5,
+		PersonalNote: "muy bueno, \"de verdad\"", Score: 9, StartedAt: &started,
+		CoverURL: "https://example.com/hades.jpg", Developer: "Supergiant", Version: 3,
+	}
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	require.NoError(t, cw.Write(gameCSVColumns))
+	require.NoError(t, cw.Write(gameCSVRecord(game)))
+	cw.Flush()
+
+	records, err := parseImportCSV(&buf)
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].Errors)
+	assert.Equal(t, models.NewGameInput(game), records[0].Input)
+}
+
+func TestExportGames_JSONEmpty(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT \\* FROM `games`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/export", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportGames_CSVDryRun(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	body := "title,platform,progress,startedAt,id\n" +
+		"Hades,PC,40,2024-05-01,99\n" +
+		"Celeste,Switch,mucho,,\n" +
+		",PC,0,,\n" +
+		"Solo titulo\n"
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^SAVEPOINT sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE \\(user_id = \\? AND title = \\? AND \\(platform = \\? OR EXISTS").
+		WithArgs(testUserID, "Hades", "PC", "PC", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO `games`").WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectRollback()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/import?dryRun=true", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "text/csv")
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report models.ImportReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 3, report.Errored)
+	require.Len(t, report.Rows, 4)
+	assert.Equal(t, 2, report.Rows[0].Line)
+	assert.Equal(t, []models.FieldError{{Field: "progress", Message: "must be an integer"}}, report.Rows[1].Errors)
+	assert.Equal(t, "title", report.Rows[2].Errors[0].Field)
+	assert.Equal(t, 5, report.Rows[3].Line)
+	assert.Equal(t, "row", report.Rows[3].Errors[0].Field)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportGames_JSONRowErrors(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	// Ninguna fila es válida: no se consulta la base
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/import", bytes.NewBufferString(`[{"title":"Hades","platform":"PC","score":"diez"},{"title":"Celeste","platform":"Switch","status":"Done"}]`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report models.ImportReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 2, report.Errored)
+	assert.Equal(t, "score", report.Rows[0].Errors[0].Field)
+	assert.Equal(t, "status", report.Rows[1].Errors[0].Field)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportGames_InvalidFile(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	for _, tc := range []struct{ url, contentType, body string }{
+		{"/games/import", "text/plain", "title,platform\n"},
+		{"/games/import?format=xml", "", "<games/>"},
+		{"/games/import?onDuplicate=replace", "text/csv", "title,platform\n"},
+		{"/games/import", "text/csv", "name,console\nHades,PC\n"},
+		{"/games/import", "application/json", `{"title":"Hades"}`},
+		{"/games/import", "application/json", `[{"title":"Hades"`},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tc.url, bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, tc.url+" "+tc.body)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportFromSource_Backloggd(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+	fixture, err := os.ReadFile("../importers/testdata/backloggd.csv")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	for i := 0; i < 2; i++ {
+		mock.ExpectExec("^SAVEPOINT sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT \\* FROM `games` WHERE \\(user_id = \\? AND title = \\? AND \\(platform = \\? OR EXISTS").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectExec("INSERT INTO `games`").WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+	}
+	// Breath of the Wild está en Wishlist: va a la lista de deseos.
+	mock.ExpectExec("^SAVEPOINT sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE \\(user_id = \\? AND title = \\? AND \\(platform = \\? OR EXISTS").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT \\* FROM `wishlist_items` WHERE user_id = \\? AND title = \\? AND platform = \\?").
+		WithArgs(testUserID, "Breath of the Wild", "Nintendo Switch", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO `wishlist_items`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/import/backloggd?dryRun=1", bytes.NewReader(fixture))
+	req.Header.Set("Content-Type", "text/csv")
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report models.ImportReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 1, report.Wishlisted)
+	assert.Equal(t, 1, report.Errored)
+	assert.Equal(t, "Minecraft", report.Rows[3].Title)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportFromSource_UnknownSource(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/import/epic", bytes.NewBufferString("{}"))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown import source")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrichGame_ProviderDisabled(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	expectGameRow(mock, "Playing", 1)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/enrich", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrichGame_WithExternalID(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	fake := metadata.NewFakeProvider(models.GameMetadata{
+		ExternalID: "42", Title: "Test Game", Genres: []string{"Platformer"}, CoverURL: "https://example.com/42.jpg",
+	})
+	router := setupRouterWith(conn, routerDeps{metadata: fake})
+
+	expectGameRow(mock, "Playing", 4)
+	mock.ExpectQuery("SELECT \\* FROM `metadata_caches`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `metadata_caches`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	expectHoursDerived(mock, 0)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/enrich", bytes.NewBufferString(`{"externalId": "42"}`))
+	req.Header.Set("If-Match", `"4"`)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	var result models.GameEnrichment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	// El juego ya tenía género: solo se completa la portada
+	assert.Equal(t, []string{"coverURL"}, result.Filled)
+	assert.Equal(t, "RPG", result.Game.Genre)
+	assert.Equal(t, "https://example.com/42.jpg", result.Game.CoverURL)
+	assert.Equal(t, 0, fake.SearchCalls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// setupCoverRouter es setupRouter con las portadas en un directorio temporal.
+func setupCoverRouter(t *testing.T, conn *gorm.DB) (*gin.Engine, *storage.Local) {
+	t.Helper()
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	return setupRouterWith(conn, routerDeps{covers: local}), local
+}
+
+func TestUploadCover_Multipart(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router, _ := setupCoverRouter(t, conn)
+
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 300, 450))))
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	// El nombre y el Content-Type del cliente no importan: se mira el contenido
+	part, err := form.CreateFormFile("cover", "cover.jpg")
+	require.NoError(t, err)
+	_, _ = part.Write(img.Bytes())
+	require.NoError(t, form.Close())
+
+	expectGameRow(mock, "Playing", 1)
+	mock.ExpectQuery("SELECT \\* FROM `game_covers`").WillReturnRows(sqlmock.NewRows([]string{"game_id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `game_covers`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `games` SET `cover_url`=").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/cover", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var cover models.GameCover
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cover))
+	assert.Equal(t, "image/png", cover.ContentType)
+	assert.Equal(t, 300, cover.Width)
+	assert.Contains(t, cover.URLs["medium"], "size=medium&v="+cover.Hash)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadCover_MissingFile(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router, _ := setupCoverRouter(t, conn)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/cover", bytes.NewBufferString("nope"))
+	req.Header.Set("Content-Type", "image/png")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectCoverRow(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT \\* FROM `game_covers` WHERE game_id = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"game_id", "hash", "content_type", "width", "height", "size", "updated_at"}).
+			AddRow(1, "0123456789abcdef", "image/png", 300, 450, 4, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestGetCover_ServesThumbnailWithCacheHeaders(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router, local := setupCoverRouter(t, conn)
+	require.NoError(t, local.Put(context.Background(), "covers/1/0123456789abcdef/small.jpg", strings.NewReader("jpeg"), "image/jpeg"))
+
+	expectGameRow(mock, "Playing", 1)
+	expectCoverRow(mock)
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/1/cover?size=small&v=0123456789abcdef", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "jpeg", w.Body.String())
+	assert.Equal(t, "image/jpeg", w.Header().Get("Content-Type"))
+	assert.Equal(t, `"0123456789abcdef-small"`, w.Header().Get("ETag"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "immutable")
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", w.Header().Get("Last-Modified"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCover_NotModified(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router, _ := setupCoverRouter(t, conn)
+
+	expectGameRow(mock, "Playing", 1)
+	expectCoverRow(mock)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/1/cover", nil)
+	req.Header.Set("If-None-Match", `"0123456789abcdef-original"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, "private, max-age=3600", w.Header().Get("Cache-Control"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCover_InvalidSize(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/1/cover?size=huge", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCover_NoCover(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	expectGameRow(mock, "Playing", 1)
+	mock.ExpectQuery("SELECT \\* FROM `game_covers`").WillReturnRows(sqlmock.NewRows([]string{"game_id"}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games/1/cover", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateWishlistItem_Validation(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/wishlist", bytes.NewBufferString(`{"targetPrice": -5, "priority": 9}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"field":"title"`)
+	assert.Contains(t, body, `"field":"targetPrice"`)
+	assert.Contains(t, body, `"field":"priority"`)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateWishlistItem_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `wishlist_items`").WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	// Act
+	w := httptest.NewRecorder()
+	body := `{"title": "Silksong", "platform": "Switch", "targetPrice": 19.99, "releaseDate": "2025-09-04T00:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/wishlist", bytes.NewBufferString(body))
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var item models.WishlistItem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+	assert.Equal(t, uint(3), item.ID)
+	assert.Equal(t, testUserID, item.UserID)
+	assert.Equal(t, models.DefaultWishlistPriority, item.Priority)
+	require.NotNil(t, item.TargetPrice)
+	assert.Equal(t, 19.99, *item.TargetPrice)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpcomingReleases_InvalidDays(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	for _, days := range []string{"abc", "-1", "1000"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/wishlist/upcoming?days="+days, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, days)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteWishlistItem_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `wishlist_items` WHERE user_id = \\? AND `wishlist_items`.`id` = \\?").
+		WithArgs(testUserID, "7", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform"}).AddRow(7, testUserID, "Silksong", "Switch"))
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE \\(user_id = \\? AND title = \\?").
+		WithArgs(testUserID, "Silksong", "PC", "PC", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO `games`").WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec("DELETE FROM `wishlist_items`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/wishlist/7/promote", bytes.NewBufferString(`{"platform": "PC", "status": "Playing"}`))
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	var game models.Game
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &game))
+	assert.Equal(t, uint(12), game.ID)
+	assert.Equal(t, "PC", game.Platform)
+	assert.Equal(t, models.StatusPlaying, game.Status)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteWishlistItem_AlreadyInLibrary(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `wishlist_items` WHERE user_id = \\? AND `wishlist_items`.`id` = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform"}).AddRow(7, testUserID, "Silksong", "Switch"))
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE \\(user_id = \\? AND title = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform"}).AddRow(4, testUserID, "Silksong", "Switch"))
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/wishlist/7/promote", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error": "game already in library", "gameId": 4}`, w.Body.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteWishlistItem_InvalidStatus(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	for _, body := range []string{`{"status": "Owned"}`, `{"status": "Wishlist"}`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/wishlist/7/promote", bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetGameTags_Validation(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	w := httptest.NewRecorder()
+	body := `{"tags": ["RPG", "` + strings.Repeat("x", 51) + `"]}`
+	req, _ := http.NewRequest("PUT", "/games/1/tags", bytes.NewBufferString(body))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "must be at most 50 characters")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTag_Conflict(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `tags`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tags", bytes.NewBufferString(`{"name": "Co-op"}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllGames_TagFilter(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `games` WHERE user_id = \\? AND \\(EXISTS .* tags.name = \\?\\)\\) AND \\(EXISTS .* tags.name = \\?\\)\\)").
+		WithArgs(testUserID, "RPG", "Co-op").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("SELECT \\* FROM `games`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/games?tag=RPG&tag=Co-op&tag=", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListCollectionGames_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT collections.\\*, .* FROM `collections` WHERE user_id = \\? AND `collections`.`id` = \\?").
+		WithArgs(testUserID, "4", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "games"}).AddRow(4, testUserID, "Verano 2026", 1))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `games` WHERE user_id = \\? AND \\(EXISTS \\(SELECT 1 FROM collection_games .*collection_games.collection_id = \\?\\)\\)").
+		WithArgs(testUserID, uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT \\* FROM `games`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "Test"))
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/collections/4/games", nil)
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page models.GamePage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, int64(1), page.Total)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListCollectionGames_NotFound(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	mock.ExpectQuery("SELECT collections.\\*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/collections/4/games", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateOwnership_InvalidFormat(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/ownerships", bytes.NewBufferString(`{"platform": "Switch", "format": "cartridge"}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "must be physical or digital")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateOwnership_NotFound(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	expectGameRow(mock, "Playing", 1)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `game_ownerships` WHERE game_id = \\? AND `game_ownerships`.`id` = \\?").
+		WithArgs(uint(1), "7", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/games/1/ownerships/7", bytes.NewBufferString(`{"platform": "PC", "hoursPlayed": 4}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Ownership record not found")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportAchievements_DuplicateNames(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	w := httptest.NewRecorder()
+	body := `{"achievements": [{"name": "Jefe final"}, {"name": "jefe final ", "unlocked": true}]}`
+	req, _ := http.NewRequest("POST", "/games/1/achievements/import", bytes.NewBufferString(body))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "duplicate name")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportAchievements_MissingName(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/achievements/import", bytes.NewBufferString(`{"achievements": [{"description": "x"}]}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAchievement_BlankName(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	for _, tc := range []struct{ url, body string }{
+		{"/games/1/achievements", `{"name": "   "}`},
+		{"/games/1/achievements/import", `{"achievements": [{"name": "Jefe final"}, {"name": " "}]}`},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tc.url, bytes.NewBufferString(tc.body))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, tc.url)
+		assert.JSONEq(t, `{"error": "validation failed", "fields": [{"field": "name", "message": "is required"}]}`, w.Body.String())
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestToggleAchievement_NotFound(t *testing.T) {
+	conn, mock, _ := setupTestDB(t)
+	router := setupRouter(conn)
+
+	expectGameRow(mock, "Playing", 1)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `achievements` WHERE game_id = \\? AND `achievements`.`id` = \\?").
+		WithArgs(uint(1), "9", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/games/1/achievements/9/toggle", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Achievement not found")
 	require.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Margen para los encabezados multipart además de la imagen.
+const multipartOverhead = 64 << 10
+
+// Las URLs con ?v=<hash> apuntan a un contenido que no cambia; sin hash la
+// portada puede cambiar y se revalida con el ETag.
+const (
+	coverCacheImmutable = "private, max-age=31536000, immutable"
+	coverCacheDefault   = "private, max-age=3600"
+)
+
+func respondCoverError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	case errors.Is(err, service.ErrCoverNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cover not found"})
+	case errors.Is(err, service.ErrCoverTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnsupportedImage):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCoversDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// CoverController sube, sirve y borra las portadas.
+type CoverController struct {
+	covers *service.CoverService
+}
+
+func NewCoverController(covers *service.CoverService) *CoverController {
+	return &CoverController{covers: covers}
+}
+
+// UploadCover recibe la portada en el campo multipart "cover" y genera las
+// miniaturas. Reemplaza la portada anterior si había.
+func (cc *CoverController) UploadCover(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, cc.covers.MaxBytes()+multipartOverhead)
+	header, err := c.FormFile("cover")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("cover too large (max %d bytes)", cc.covers.MaxBytes())})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing cover file"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing cover file"})
+		return
+	}
+	defer file.Close()
+
+	cover, err := cc.covers.SaveCover(c.Request.Context(), userID, c.Param("id"), file)
+	if err != nil {
+		respondCoverError(c, err, "Error saving cover")
+		return
+	}
+	c.JSON(http.StatusOK, cover)
+}
+
+// GetCover sirve la portada: ?size=small|medium|large (default original).
+// Responde 304 si el If-None-Match coincide.
+func (cc *CoverController) GetCover(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	size := c.DefaultQuery("size", models.CoverOriginal)
+	if !models.IsValidCoverSize(size) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "size must be original, small, medium or large"})
+		return
+	}
+	cover, err := cc.covers.GetCover(userID, c.Param("id"))
+	if err != nil {
+		respondCoverError(c, err, "Error obtaining cover")
+		return
+	}
+
+	etag := fmt.Sprintf(`"%s-%s"`, cover.Hash, size)
+	c.Header("ETag", etag)
+	if v := c.Query("v"); v != "" && v == cover.Hash {
+		c.Header("Cache-Control", coverCacheImmutable)
+	} else {
+		c.Header("Cache-Control", coverCacheDefault)
+	}
+	if match := c.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	body, obj, err := cc.covers.OpenCover(c.Request.Context(), cover, size)
+	if err != nil {
+		respondCoverError(c, err, "Error obtaining cover")
+		return
+	}
+	defer body.Close()
+	c.DataFromReader(http.StatusOK, obj.Size, obj.ContentType, body, map[string]string{
+		"Last-Modified":          cover.UpdatedAt.UTC().Format(http.TimeFormat),
+		"X-Content-Type-Options": "nosniff",
+		"Content-Disposition":    "inline; filename=cover-" + strconv.FormatUint(uint64(cover.GameID), 10) + "-" + size,
+	})
+}
+
+func (cc *CoverController) DeleteCover(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := cc.covers.DeleteCover(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondCoverError(c, err, "Error deleting cover")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Cover deleted successfully"})
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"fmt"
+	"gametracker/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const dateOnlyLayout = "2006-01-02"
+
+// parseGameListQuery arma el GameListQuery a partir de los query params:
+// ?page=&pageSize=&cursor=&sort= más los filtros de parseGameFilter.
+func parseGameListQuery(c *gin.Context) (models.GameListQuery, error) {
+	q := models.GameListQuery{
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+
+	var err error
+	if q.Filter, err = parseGameFilter(c); err != nil {
+		return q, err
+	}
+	if q.Page, err = queryInt(c, "page"); err != nil {
+		return q, err
+	}
+	if q.PageSize, err = queryInt(c, "pageSize"); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// parseGameFilter lee los filtros comunes al listado y a las estadísticas:
+// ?title=&status=&genre=&platform=&minScore=&maxScore=
+// &startedFrom=&startedTo=&finishedFrom=&finishedTo=&tag=
+//
+// tag se puede repetir (?tag=RPG&tag=Co-op): el juego tiene que tener todas.
+//
+// Las fechas aceptan YYYY-MM-DD o RFC3339. Con fecha sola, los límites
+// "To" incluyen el día completo.
+func parseGameFilter(c *gin.Context) (models.GameFilter, error) {
+	f := models.GameFilter{
+		Title:    c.Query("title"),
+		Status:   c.Query("status"),
+		Genre:    c.Query("genre"),
+		Platform: c.Query("platform"),
+	}
+	for _, tag := range c.QueryArray("tag") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			f.Tags = append(f.Tags, tag)
+		}
+	}
+
+	var err error
+	if f.MinScore, err = queryIntPtr(c, "minScore"); err != nil {
+		return f, err
+	}
+	if f.MaxScore, err = queryIntPtr(c, "maxScore"); err != nil {
+		return f, err
+	}
+	if f.StartedFrom, err = queryDate(c, "startedFrom", false); err != nil {
+		return f, err
+	}
+	if f.StartedTo, err = queryDate(c, "startedTo", true); err != nil {
+		return f, err
+	}
+	if f.FinishedFrom, err = queryDate(c, "finishedFrom", false); err != nil {
+		return f, err
+	}
+	if f.FinishedTo, err = queryDate(c, "finishedTo", true); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+func queryInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, raw)
+	}
+	return value, nil
+}
+
+func queryIntPtr(c *gin.Context, key string) (*int, error) {
+	if c.Query(key) == "" {
+		return nil, nil
+	}
+	value, err := queryInt(c, key)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func queryDate(c *gin.Context, key string, endOfRange bool) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(dateOnlyLayout, raw, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %q (use YYYY-MM-DD or RFC3339)", key, raw)
+	}
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
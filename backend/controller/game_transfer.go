@@ -0,0 +1,509 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gametracker/importers"
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+
+	// Límites de una importación: el archivo entero se decodifica antes de
+	// escribir para poder reportar todas las filas en una sola transacción.
+	MaxImportBytes = 10 << 20
+	MaxImportRows  = 5000
+
+	// Cada cuántos juegos se hace flush del export.
+	exportFlushEvery = 100
+)
+
+// gameCSVColumns son las columnas del CSV exportado: todos los campos de
+// models.Game con su nombre JSON. Al importar se usan las editables
+// (las de GameInput) y el resto se ignora.
+var gameCSVColumns = []string{
+	"id", "userId", "title", "platform", "genre", "status", "progress",
+	"progressFromAchievements", "hoursPlayed", "personalNote", "score",
+	"startedAt", "finishedAt", "coverURL", "releaseDate", "developer",
+	"version", "createdAt", "updatedAt", "deletedAt",
+}
+
+// TransferController exporta e importa la biblioteca.
+type TransferController struct {
+	transfer *service.TransferService
+}
+
+func NewTransferController(transfer *service.TransferService) *TransferController {
+	return &TransferController{transfer: transfer}
+}
+
+// ExportGames descarga la biblioteca en ?format=json (default) o csv. La
+// respuesta se escribe a medida que se leen los juegos.
+func (tc *TransferController) ExportGames(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", formatJSON)
+	if format != formatCSV && format != formatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	filename := fmt.Sprintf("games-%s.%s", time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	var err error
+	if format == formatCSV {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		err = tc.exportCSV(c.Writer, userID)
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		err = tc.exportJSON(c.Writer, userID)
+	}
+	if err != nil {
+		// Con la respuesta empezada ya no se puede cambiar el status: se
+		// corta y el cliente recibe un archivo incompleto.
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error exporting games"})
+			return
+		}
+		log.Printf("export games for user %d: %v", userID, err)
+		c.Abort()
+	}
+}
+
+func (tc *TransferController) exportCSV(w gin.ResponseWriter, userID uint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(gameCSVColumns); err != nil {
+		return err
+	}
+	n := 0
+	err := tc.transfer.ExportGames(userID, func(g models.Game) error {
+		if err := cw.Write(gameCSVRecord(g)); err != nil {
+			return err
+		}
+		if n++; n%exportFlushEvery == 0 {
+			cw.Flush()
+			w.Flush()
+		}
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportJSON escribe un array JSON. El "[" va con el primer juego para que
+// un error en la consulta todavía pueda responder 500.
+func (tc *TransferController) exportJSON(w gin.ResponseWriter, userID uint) error {
+	separator := "["
+	n := 0
+	err := tc.transfer.ExportGames(userID, func(g models.Game) error {
+		data, err := json.Marshal(g)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		separator = ","
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		if n++; n%exportFlushEvery == 0 {
+			w.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		_, err = io.WriteString(w, "[]\n")
+		return err
+	}
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+func gameCSVRecord(g models.Game) []string {
+	var deletedAt *time.Time
+	if g.DeletedAt.Valid {
+		deletedAt = &g.DeletedAt.Time
+	}
+	return []string{
+		strconv.FormatUint(uint64(g.ID), 10),
+		strconv.FormatUint(uint64(g.UserID), 10),
+		g.Title,
+		g.Platform,
+		g.Genre,
+		g.Status,
+		strconv.Itoa(g.Progress),
+		strconv.FormatBool(g.ProgressFromAchievements),
+		strconv.FormatFloat(g.HoursPlayed, 'f', -1, 64),
+		g.PersonalNote,
+		strconv.Itoa(g.Score),
+		csvTime(g.StartedAt),
+		csvTime(g.FinishedAt),
+		g.CoverURL,
+		csvTime(g.ReleaseDate),
+		g.Developer,
+		strconv.FormatUint(uint64(g.Version), 10),
+		csvTime(&g.CreatedAt),
+		csvTime(&g.UpdatedAt),
+		csvTime(deletedAt),
+	}
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ImportGames carga juegos desde un CSV o JSON con el formato del export.
+// El archivo puede venir como body o como campo "file" multipart; el
+// formato sale de ?format=, del Content-Type o de la extensión del archivo.
+//
+//	?dryRun=true        arma el reporte sin guardar
+//	?onDuplicate=update actualiza los juegos existentes (default: skip)
+func (tc *TransferController) ImportGames(c *gin.Context) {
+	tc.importGames(c, func(body io.Reader, format string) ([]models.ImportRecord, error) {
+		switch format {
+		case formatCSV:
+			return parseImportCSV(body)
+		case formatJSON:
+			return parseImportJSON(body)
+		}
+		return nil, errors.New("format must be csv or json")
+	})
+}
+
+// ImportFromSource importa el archivo exportado por otro servicio
+// (/games/import/steam, gog, backloggd o hltb), con las mismas opciones y
+// el mismo reporte que ImportGames.
+func (tc *TransferController) ImportFromSource(c *gin.Context) {
+	tc.importGames(c, func(body io.Reader, _ string) ([]models.ImportRecord, error) {
+		return importers.Parse(c.Param("source"), body)
+	})
+}
+
+// importGames es el flujo común de las importaciones: opciones, archivo,
+// parse con parse, validación de cada fila y guardado.
+func (tc *TransferController) importGames(c *gin.Context, parse func(body io.Reader, format string) ([]models.ImportRecord, error)) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	opts := models.ImportOptions{}
+	if raw := c.Query("dryRun"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dryRun must be a boolean"})
+			return
+		}
+		opts.DryRun = dryRun
+	}
+	switch c.DefaultQuery("onDuplicate", "skip") {
+	case "skip":
+	case "update":
+		opts.UpdateExisting = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "onDuplicate must be skip or update"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxImportBytes)
+	body, format, err := importSource(c)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+	defer body.Close()
+
+	records, err := parse(body, format)
+	if err == nil && len(records) > MaxImportRows {
+		err = fmt.Errorf("too many rows (max %d)", MaxImportRows)
+	}
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+	for i := range records {
+		if len(records[i].Errors) == 0 {
+			records[i].Errors = validateImportInput(records[i].Input)
+		}
+	}
+
+	report, err := tc.transfer.ImportGames(userID, records, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error importing games"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func respondImportError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file too large (max %d bytes)", MaxImportBytes)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// importSource devuelve el archivo a importar y su formato.
+func importSource(c *gin.Context) (io.ReadCloser, string, error) {
+	format := c.Query("format")
+	contentType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if contentType == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", fmt.Errorf("missing file: %w", err)
+		}
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+		file, err := header.Open()
+		return file, format, err
+	}
+	if format == "" {
+		switch contentType {
+		case "text/csv":
+			format = formatCSV
+		case "application/json":
+			format = formatJSON
+		}
+	}
+	return c.Request.Body, format, nil
+}
+
+// parseImportCSV lee un CSV con cabecera. Las columnas se buscan por nombre
+// (sin importar mayúsculas); title y platform son obligatorias.
+func parseImportCSV(r io.Reader) ([]models.ImportRecord, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty CSV")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	// Excel agrega un BOM al principio del archivo.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	columns := make([]string, len(header))
+	present := map[string]bool{}
+	for i, name := range header {
+		for _, known := range gameCSVColumns {
+			if strings.EqualFold(strings.TrimSpace(name), known) {
+				columns[i] = known
+				present[known] = true
+			}
+		}
+	}
+	if !present["title"] || !present["platform"] {
+		return nil, errors.New("CSV must have title and platform columns")
+	}
+
+	records := []models.ImportRecord{}
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if len(records) == MaxImportRows {
+			return nil, fmt.Errorf("too many rows (max %d)", MaxImportRows)
+		}
+		rec := models.ImportRecord{Line: line}
+		if err != nil {
+			if !errors.Is(err, csv.ErrFieldCount) {
+				return nil, fmt.Errorf("invalid CSV: %w", err)
+			}
+			rec.Errors = []models.FieldError{{Field: "row", Message: fmt.Sprintf("has %d fields, expected %d", len(fields), len(header))}}
+			records = append(records, rec)
+			continue
+		}
+		for i, value := range fields {
+			if columns[i] == "" {
+				continue
+			}
+			rec.Mark(columns[i])
+			if fe := setImportField(&rec.Input, columns[i], value); fe != nil {
+				rec.Errors = append(rec.Errors, *fe)
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// setImportField asigna una celda del CSV al campo de GameInput que le
+// corresponde. Las columnas que no son editables se ignoran.
+func setImportField(in *models.GameInput, column, value string) *models.FieldError {
+	var err error
+	var msg string
+	trimmed := strings.TrimSpace(value)
+	switch column {
+	case "title":
+		in.Title = trimmed
+	case "platform":
+		in.Platform = trimmed
+	case "genre":
+		in.Genre = trimmed
+	case "status":
+		in.Status = trimmed
+	case "personalNote":
+		in.PersonalNote = value
+	case "coverURL":
+		in.CoverURL = trimmed
+	case "developer":
+		in.Developer = trimmed
+	case "progress":
+		in.Progress, err = csvInt(trimmed)
+		msg = "must be an integer"
+	case "score":
+		in.Score, err = csvInt(trimmed)
+		msg = "must be an integer"
+	case "progressFromAchievements":
+		if trimmed != "" {
+			in.ProgressFromAchievements, err = strconv.ParseBool(trimmed)
+		}
+		msg = "must be true or false"
+	case "hoursPlayed":
+		if trimmed != "" {
+			in.HoursPlayed, err = strconv.ParseFloat(trimmed, 64)
+		}
+		msg = "must be a number"
+	case "startedAt":
+		in.StartedAt, err = csvDate(trimmed)
+		msg = "must be a date (YYYY-MM-DD or RFC3339)"
+	case "finishedAt":
+		in.FinishedAt, err = csvDate(trimmed)
+		msg = "must be a date (YYYY-MM-DD or RFC3339)"
+	case "releaseDate":
+		in.ReleaseDate, err = csvDate(trimmed)
+		msg = "must be a date (YYYY-MM-DD or RFC3339)"
+	}
+	if err != nil {
+		return &models.FieldError{Field: column, Message: msg}
+	}
+	return nil
+}
+
+func csvInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func csvDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.ParseInLocation(dateOnlyLayout, value, time.Local)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseImportJSON lee un array de juegos como el del export. Un elemento
+// con tipos incorrectos es un error de esa fila; un JSON mal formado
+// invalida el archivo entero.
+func parseImportJSON(r io.Reader) ([]models.ImportRecord, error) {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, errors.New("JSON must be an array of games")
+	}
+
+	records := []models.ImportRecord{}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		if len(records) == MaxImportRows {
+			return nil, fmt.Errorf("too many rows (max %d)", MaxImportRows)
+		}
+		rec := models.ImportRecord{Line: len(records) + 1}
+		rec.Mark(jsonImportFields(raw)...)
+		if err := json.Unmarshal(raw, &rec.Input); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) && typeErr.Field != "" {
+				rec.Errors = []models.FieldError{{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()}}
+			} else {
+				rec.Errors = []models.FieldError{{Field: "row", Message: "must be a JSON object"}}
+			}
+		}
+		records = append(records, rec)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return records, nil
+}
+
+// jsonImportFields devuelve los campos de GameInput que trae el objeto,
+// con su nombre JSON. Como encoding/json, las claves no distinguen
+// mayúsculas.
+func jsonImportFields(raw json.RawMessage) []string {
+	var object map[string]json.RawMessage
+	if json.Unmarshal(raw, &object) != nil {
+		return nil
+	}
+	var fields []string
+	for key := range object {
+		for _, field := range models.GameInputFields {
+			if strings.EqualFold(key, field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// validateImportInput aplica a una fila las mismas validaciones que al body
+// de CreateGame.
+func validateImportInput(in models.GameInput) []models.FieldError {
+	var fields []models.FieldError
+	if err := binding.Validator.ValidateStruct(&in); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			fields = append(fields, fieldErrors(validationErrs)...)
+		} else {
+			fields = append(fields, models.FieldError{Field: "row", Message: err.Error()})
+		}
+	}
+	return append(fields, in.Validate()...)
+}
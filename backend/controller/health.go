@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout acota los chequeos de /readyz para que una base colgada
+// no deje colgado también al orquestador.
+const readinessTimeout = 3 * time.Second
+
+type HealthController struct {
+	health *service.HealthService
+}
+
+func NewHealthController(health *service.HealthService) *HealthController {
+	return &HealthController{health: health}
+}
+
+// Healthz es el chequeo de vida: responde mientras el proceso atienda
+// requests, sin tocar la base.
+func (hc *HealthController) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz responde 503 si la base no responde o le faltan migraciones.
+func (hc *HealthController) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+	readiness := hc.health.Ready(ctx)
+	status := http.StatusOK
+	if !readiness.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, readiness)
+}
+
+// RequireReady es el middleware de las rutas de la API: con la base caída o
+// con migraciones pendientes responde 503 con los mismos chequeos de /readyz
+// en vez de atender la request contra un esquema viejo.
+func (hc *HealthController) RequireReady() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+		readiness := hc.health.Ready(ctx)
+		if !readiness.Ready() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":  "service unavailable",
+				"checks": readiness.Checks,
+			})
+			return
+		}
+		c.Next()
+	}
+}
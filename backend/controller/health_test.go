@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gametracker/db"
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Los chequeos de disponibilidad corren contra SQLite en memoria: necesitan
+// un ping y las migraciones reales.
+func openHealthDB(t *testing.T, migrate bool) *gorm.DB {
+	t.Helper()
+	conn, err := db.Open(db.Config{Driver: db.DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	sqlDB, err := conn.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	if migrate {
+		migrator, err := db.NewMigrator(conn)
+		require.NoError(t, err)
+		_, err = migrator.Up()
+		require.NoError(t, err)
+	}
+	return conn
+}
+
+func getHealth(t *testing.T, conn *gorm.DB, path string) (*httptest.ResponseRecorder, models.Readiness) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	healthController := NewHealthController(service.NewHealthService(conn))
+	r.GET("/healthz", healthController.Healthz)
+	r.GET("/readyz", healthController.Readyz)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var body models.Readiness
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w, body
+}
+
+func TestHealthz_DoesNotTouchDatabase(t *testing.T) {
+	conn := openHealthDB(t, false)
+	sqlDB, _ := conn.DB()
+	sqlDB.Close()
+
+	w, body := getHealth(t, conn, "/healthz")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ReadinessOK, body.Status)
+}
+
+func TestReadyz_OK(t *testing.T) {
+	conn := openHealthDB(t, true)
+
+	w, body := getHealth(t, conn, "/readyz")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]string{"database": "ok", "migrations": "ok"}, body.Checks)
+}
+
+func TestReadyz_PendingMigrations(t *testing.T) {
+	conn := openHealthDB(t, false)
+
+	w, body := getHealth(t, conn, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, models.ReadinessUnavailable, body.Status)
+	assert.Equal(t, "ok", body.Checks["database"])
+	assert.Equal(t, models.ReadinessCheckPending, body.Checks["migrations"])
+}
+
+func TestReadyz_DatabaseDown(t *testing.T) {
+	conn := openHealthDB(t, true)
+	sqlDB, _ := conn.DB()
+	sqlDB.Close()
+
+	w, body := getHealth(t, conn, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, models.ReadinessCheckUnreachable, body.Checks["database"])
+	assert.NotContains(t, body.Checks, "migrations")
+}
+
+// getGamesWithReadiness pide /games con RequireReady delante, como en
+// routes.SetupGameRoutes.
+func getGamesWithReadiness(t *testing.T, conn *gorm.DB) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	healthController := NewHealthController(service.NewHealthService(conn))
+	games := r.Group("/games", healthController.RequireReady(), func(c *gin.Context) {
+		c.Set("userID", testUserID)
+		c.Next()
+	})
+	games.GET("", newGameController(conn).GetAllGames)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/games", nil))
+	return w
+}
+
+func TestRequireReady_PendingMigrations(t *testing.T) {
+	conn := openHealthDB(t, false)
+
+	w := getGamesWithReadiness(t, conn)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var body struct {
+		Checks map[string]string `json:"checks"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, models.ReadinessCheckPending, body.Checks["migrations"])
+}
+
+func TestRequireReady_DatabaseDown(t *testing.T) {
+	conn := openHealthDB(t, true)
+	sqlDB, _ := conn.DB()
+	sqlDB.Close()
+
+	w := getGamesWithReadiness(t, conn)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestRequireReady_OK(t *testing.T) {
+	conn := openHealthDB(t, true)
+
+	w := getGamesWithReadiness(t, conn)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
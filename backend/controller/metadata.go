@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// enrichRequest es el body opcional de EnrichGame: sin externalId se busca
+// el juego por título.
+type enrichRequest struct {
+	ExternalID string `json:"externalId" binding:"max=64"`
+}
+
+// MetadataController enriquece juegos con el proveedor de metadatos.
+type MetadataController struct {
+	metadata *service.MetadataService
+	games    *service.GameService
+}
+
+func NewMetadataController(metadata *service.MetadataService, games *service.GameService) *MetadataController {
+	return &MetadataController{metadata: metadata, games: games}
+}
+
+// EnrichGame completa género, portada, fecha de salida y desarrollador del
+// juego con el proveedor de metadatos. Respeta If-Match como cualquier otra
+// modificación.
+func (mc *MetadataController) EnrichGame(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	game, err := mc.games.Get(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+	var req enrichRequest
+	if c.Request.ContentLength != 0 && !checkInput(c, req, c.ShouldBindJSON(&req)) {
+		return
+	}
+	if !checkPreconditions(c, game, models.GameInput{}) {
+		return
+	}
+
+	result, err := mc.metadata.EnrichGame(c.Request.Context(), userID, game, req.ExternalID)
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrMetadataDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrNoMetadataMatch):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrMetadataUnavailable):
+		c.JSON(http.StatusBadGateway, gin.H{"error": "metadata provider unavailable"})
+		return
+	case errors.Is(err, service.ErrVersionConflict):
+		if current, err := mc.games.Get(userID, id); err == nil {
+			respondPreconditionFailed(c, current)
+			return
+		}
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error enriching game"})
+		return
+	}
+	setGameETag(c, result.Game)
+	c.JSON(http.StatusOK, result)
+}
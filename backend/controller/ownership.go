@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondOwnershipError mapea los errores del service de copias a HTTP.
+func respondOwnershipError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	case errors.Is(err, service.ErrOwnershipNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ownership record not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// OwnershipController atiende las copias de cada juego.
+type OwnershipController struct {
+	ownerships *service.OwnershipService
+}
+
+func NewOwnershipController(ownerships *service.OwnershipService) *OwnershipController {
+	return &OwnershipController{ownerships: ownerships}
+}
+
+// ListOwnerships lista las copias del juego (plataforma, tienda, edición).
+func (oc *OwnershipController) ListOwnerships(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	ownerships, err := oc.ownerships.ListOwnerships(userID, c.Param("id"))
+	if err != nil {
+		respondOwnershipError(c, err, "Error obtaining ownership records")
+		return
+	}
+	c.JSON(http.StatusOK, ownerships)
+}
+
+// CreateOwnership agrega una copia; sus horas se suman a las del juego.
+func (oc *OwnershipController) CreateOwnership(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.OwnershipInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	ownership, err := oc.ownerships.CreateOwnership(userID, c.Param("id"), input)
+	if err != nil {
+		respondOwnershipError(c, err, "Error creating ownership record")
+		return
+	}
+	c.JSON(http.StatusOK, ownership)
+}
+
+func (oc *OwnershipController) UpdateOwnership(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.OwnershipInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	ownership, err := oc.ownerships.UpdateOwnership(userID, c.Param("id"), c.Param("ownershipId"), input)
+	if err != nil {
+		respondOwnershipError(c, err, "Error updating ownership record")
+		return
+	}
+	c.JSON(http.StatusOK, ownership)
+}
+
+func (oc *OwnershipController) DeleteOwnership(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := oc.ownerships.DeleteOwnership(userID, c.Param("id"), c.Param("ownershipId")); err != nil {
+		respondOwnershipError(c, err, "Error deleting ownership record")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ownership record deleted successfully"})
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondSessionError mapea los errores del service de sesiones a HTTP.
+func respondSessionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	case errors.Is(err, service.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Play session not found"})
+	case errors.Is(err, service.ErrOwnershipNotFound):
+		respondValidation(c, []models.FieldError{{Field: "ownershipId", Message: "must be an ownership of this game"}})
+	case errors.Is(err, service.ErrSessionRunning), errors.Is(err, service.ErrNoRunningSession):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// SessionController atiende las sesiones de juego y el cronómetro.
+type SessionController struct {
+	sessions *service.SessionService
+}
+
+func NewSessionController(sessions *service.SessionService) *SessionController {
+	return &SessionController{sessions: sessions}
+}
+
+func (sc *SessionController) ListSessions(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	sessions, err := sc.sessions.ListSessions(userID, c.Param("id"))
+	if err != nil {
+		respondSessionError(c, err, "Error obtaining play sessions")
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// CreateSession carga a mano una sesión terminada (inicio y fin o duración).
+func (sc *SessionController) CreateSession(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.PlaySessionInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	var session models.PlaySession
+	input.Apply(&session)
+	if err := sc.sessions.CreateSession(userID, c.Param("id"), &session); err != nil {
+		respondSessionError(c, err, "Error creating play session")
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+func (sc *SessionController) UpdateSession(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.PlaySessionInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	session, err := sc.sessions.UpdateSession(userID, c.Param("id"), c.Param("sessionId"), input)
+	if err != nil {
+		respondSessionError(c, err, "Error updating play session")
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+func (sc *SessionController) DeleteSession(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := sc.sessions.DeleteSession(userID, c.Param("id"), c.Param("sessionId")); err != nil {
+		respondSessionError(c, err, "Error deleting play session")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Play session deleted successfully"})
+}
+
+// StartSession arranca el cronómetro del juego; 409 si ya hay uno en curso.
+// ?ownershipId= asocia la sesión a una copia del juego.
+func (sc *SessionController) StartSession(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	rawOwnership, err := queryIntPtr(c, "ownershipId")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var ownershipID *uint
+	if rawOwnership != nil {
+		id := uint(*rawOwnership)
+		ownershipID = &id
+	}
+	session, err := sc.sessions.StartSession(userID, c.Param("id"), ownershipID)
+	if err != nil {
+		respondSessionError(c, err, "Error starting play session")
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// StopSession detiene el cronómetro en curso; 409 si no hay ninguno.
+func (sc *SessionController) StopSession(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	session, err := sc.sessions.StopSession(userID, c.Param("id"))
+	if err != nil {
+		respondSessionError(c, err, "Error stopping play session")
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
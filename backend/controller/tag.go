@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondTagError mapea los errores de etiquetas y colecciones a HTTP.
+func respondTagError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+	case errors.Is(err, service.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+	case errors.Is(err, service.ErrCollectionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+	case errors.Is(err, service.ErrTagExists), errors.Is(err, service.ErrCollectionExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// TagController atiende las etiquetas del usuario y las de cada juego.
+type TagController struct {
+	tags *service.TagService
+}
+
+func NewTagController(tags *service.TagService) *TagController {
+	return &TagController{tags: tags}
+}
+
+// ListTags lista las etiquetas del usuario con la cantidad de juegos.
+func (tc *TagController) ListTags(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	tags, err := tc.tags.ListTags(userID)
+	if err != nil {
+		respondTagError(c, err, "Error obtaining tags")
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// CreateTag crea una etiqueta; 409 si ya hay una con ese nombre.
+func (tc *TagController) CreateTag(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.TagInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	tag := models.Tag{UserID: userID, Name: input.Name}
+	if err := tc.tags.CreateTag(&tag); err != nil {
+		respondTagError(c, err, "Error creating tag")
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+func (tc *TagController) UpdateTag(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.TagInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	tag, err := tc.tags.RenameTag(userID, c.Param("id"), input)
+	if err != nil {
+		respondTagError(c, err, "Error updating tag")
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag borra la etiqueta y la quita de todos los juegos.
+func (tc *TagController) DeleteTag(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := tc.tags.DeleteTag(userID, c.Param("id")); err != nil {
+		respondTagError(c, err, "Error deleting tag")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+func (tc *TagController) GetGameTags(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	tags, err := tc.tags.GetGameTags(userID, c.Param("id"))
+	if err != nil {
+		respondTagError(c, err, "Error obtaining game tags")
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// SetGameTags reemplaza las etiquetas del juego ({"tags": ["RPG", "Co-op"]})
+// creando las que no existen. Una lista vacía las quita todas.
+func (tc *TagController) SetGameTags(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.GameTagsInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	tags, err := tc.tags.SetGameTags(userID, c.Param("id"), input.Tags)
+	if err != nil {
+		respondTagError(c, err, "Error saving game tags")
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
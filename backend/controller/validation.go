@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"gametracker/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Los errores de validación usan el nombre JSON del campo ("coverURL"), no el de Go.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// bindGameInput decodifica y valida el body de un juego. Si es inválido ya
+// respondió: 400 si no es JSON, 422 con la lista de campos si no valida.
+func bindGameInput(c *gin.Context, input *models.GameInput) bool {
+	return checkInput(c, input, c.ShouldBindJSON(input))
+}
+
+// validatable es un body con validaciones propias además de los tags.
+type validatable interface {
+	Validate() []models.FieldError
+}
+
+// checkInput responde según el error de decodificación/binding err y, si
+// input es validatable, sus validaciones propias. Devuelve true si input es
+// válido.
+func checkInput(c *gin.Context, input interface{}, err error) bool {
+	var fields []models.FieldError
+	if err != nil {
+		var validationErrs validator.ValidationErrors
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &validationErrs):
+			fields = append(fields, fieldErrors(validationErrs)...)
+		case errors.As(err, &typeErr) && typeErr.Field != "":
+			respondValidation(c, []models.FieldError{{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()}})
+			return false
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+	}
+	if v, ok := input.(validatable); ok {
+		fields = append(fields, v.Validate()...)
+	}
+	if len(fields) > 0 {
+		respondValidation(c, fields)
+		return false
+	}
+	return true
+}
+
+// respondValidation responde 422 con los campos inválidos.
+func respondValidation(c *gin.Context, fields []models.FieldError) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "fields": fields})
+}
+
+func fieldErrors(errs validator.ValidationErrors) []models.FieldError {
+	fields := make([]models.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, models.FieldError{Field: fe.Field(), Message: validationMessage(fe)})
+	}
+	return fields
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	default:
+		return "is invalid (" + fe.Tag() + ")"
+	}
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gametracker/models"
+	"gametracker/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultUpcomingDays es la ventana de GET /wishlist/upcoming sin ?days.
+const DefaultUpcomingDays = 30
+
+// respondWishlistError mapea los errores del service de deseos a HTTP.
+func respondWishlistError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrWishlistItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wishlist item not found"})
+	case errors.Is(err, service.ErrPlatformRequired):
+		respondValidation(c, []models.FieldError{{Field: "platform", Message: "is required"}})
+	case isStatusError(err):
+		respondValidation(c, []models.FieldError{{Field: "status", Message: err.Error()}})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// WishlistController atiende la lista de deseos.
+type WishlistController struct {
+	wishlist *service.WishlistService
+}
+
+func NewWishlistController(wishlist *service.WishlistService) *WishlistController {
+	return &WishlistController{wishlist: wishlist}
+}
+
+// ListWishlist lista los deseos por prioridad y fecha de salida.
+func (wc *WishlistController) ListWishlist(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	items, err := wc.wishlist.ListWishlist(userID)
+	if err != nil {
+		respondWishlistError(c, err, "Error obtaining wishlist")
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// UpcomingReleases lista los deseos que salen en los próximos ?days días
+// (default DefaultUpcomingDays).
+func (wc *WishlistController) UpcomingReleases(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	days := DefaultUpcomingDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > service.MaxUpcomingDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be between 0 and %d", service.MaxUpcomingDays)})
+			return
+		}
+		days = n
+	}
+	items, err := wc.wishlist.UpcomingReleases(userID, days)
+	if err != nil {
+		respondWishlistError(c, err, "Error obtaining upcoming releases")
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+func (wc *WishlistController) GetWishlistItem(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	item, err := wc.wishlist.GetWishlistItem(userID, c.Param("id"))
+	if err != nil {
+		respondWishlistError(c, err, "Error obtaining wishlist item")
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func (wc *WishlistController) CreateWishlistItem(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.WishlistInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	item := models.WishlistItem{UserID: userID}
+	input.Apply(&item)
+	if err := wc.wishlist.CreateWishlistItem(&item); err != nil {
+		respondWishlistError(c, err, "Error creating wishlist item")
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func (wc *WishlistController) UpdateWishlistItem(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.WishlistInput
+	if !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	item, err := wc.wishlist.UpdateWishlistItem(userID, c.Param("id"), input)
+	if err != nil {
+		respondWishlistError(c, err, "Error updating wishlist item")
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func (wc *WishlistController) DeleteWishlistItem(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	if err := wc.wishlist.DeleteWishlistItem(userID, c.Param("id")); err != nil {
+		respondWishlistError(c, err, "Error deleting wishlist item")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Wishlist item deleted successfully"})
+}
+
+// PromoteWishlistItem pasa el deseo a la biblioteca y devuelve el juego
+// creado. El body (platform, status) es opcional. Si el juego ya está en la
+// biblioteca responde 409 con su gameId.
+func (wc *WishlistController) PromoteWishlistItem(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var input models.PromoteInput
+	if c.Request.ContentLength != 0 && !checkInput(c, &input, c.ShouldBindJSON(&input)) {
+		return
+	}
+	game, err := wc.wishlist.PromoteWishlistItem(userID, c.Param("id"), input)
+	if errors.Is(err, service.ErrGameInLibrary) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "gameId": game.ID})
+		return
+	}
+	if err != nil {
+		respondWishlistError(c, err, "Error adding wishlist item to library")
+		return
+	}
+	setGameETag(c, game)
+	c.JSON(http.StatusOK, game)
+}
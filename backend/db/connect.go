@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Backoff es la espera entre intentos de conexión: Initial se duplica en
+// cada intento hasta Max y se le resta al azar hasta una fracción Jitter,
+// así varias instancias que arrancan juntas no reintentan a la vez.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Jitter  float64
+}
+
+// Delay es la espera antes del reintento número attempt (desde 0); r es un
+// número al azar en [0, 1).
+func (b Backoff) Delay(attempt int, r float64) time.Duration {
+	d := b.Initial
+	for i := 0; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d - time.Duration(float64(d)*b.Jitter*r)
+}
+
+// RetryConfig controla cómo se espera a la base. MaxAttempts (0 = sin
+// límite) solo aplica a ConnectDB; Watch reintenta hasta que se apaga el
+// servidor. CheckInterval es cada cuánto Watch verifica una conexión ya
+// establecida.
+type RetryConfig struct {
+	Backoff
+	MaxAttempts   int
+	CheckInterval time.Duration
+}
+
+// ConnectDB abre db.DB y espera a que la base responda. Lo usa el subcomando
+// migrate, que no puede hacer nada sin base; el servidor usa Watch.
+func ConnectDB(ctx context.Context) error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	if DB, err = Open(cfg); err != nil {
+		return err
+	}
+	return Connect(ctx, DB, cfg, cfg.Retry.MaxAttempts)
+}
+
+// Connect reintenta con backoff hasta que la base responde, se cancela ctx o
+// se hacen maxAttempts intentos (0 = sin límite). En MySQL cada intento crea
+// antes la base si no existe.
+func Connect(ctx context.Context, conn *gorm.DB, cfg Config, maxAttempts int) error {
+	for attempt := 0; ; attempt++ {
+		err := connectOnce(ctx, conn, cfg)
+		if err == nil {
+			log.Printf("Conexión a la base de datos establecida (%s): %s", cfg.Driver, cfg.target())
+			return nil
+		}
+		if maxAttempts > 0 && attempt+1 >= maxAttempts {
+			return fmt.Errorf("database not available after %d attempts: %w", attempt+1, err)
+		}
+		wait := cfg.Retry.Delay(attempt, rand.Float64())
+		log.Printf("Intento %d: no se pudo conectar a la base de datos (%s): %v. Reintentando en %v...",
+			attempt+1, cfg.Driver, err, wait.Round(time.Millisecond))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func connectOnce(ctx context.Context, conn *gorm.DB, cfg Config) error {
+	if cfg.Driver == DriverMySQL {
+		if err := createMySQLDatabase(ctx, cfg); err != nil {
+			return err
+		}
+	}
+	return ping(ctx, conn, cfg.Retry.CheckInterval)
+}
+
+// Watch mantiene la conexión del servidor: espera a que la base responda
+// (Connect, sin límite de intentos), llama a onConnect y después la verifica
+// cada cfg.Retry.CheckInterval. Si deja de responder vuelve a esperarla con
+// backoff; mientras tanto database/sql descarta las conexiones rotas y
+// /readyz responde 503. Termina cuando se cancela ctx.
+func Watch(ctx context.Context, conn *gorm.DB, cfg Config, onConnect func()) {
+	for {
+		if err := Connect(ctx, conn, cfg, 0); err != nil {
+			return
+		}
+		if onConnect != nil {
+			onConnect()
+		}
+		if !monitor(ctx, conn, cfg.Retry.CheckInterval) {
+			return
+		}
+	}
+}
+
+// monitor hace ping cada interval. Devuelve true cuando la base deja de
+// responder y false cuando se cancela ctx.
+func monitor(ctx context.Context, conn *gorm.DB, interval time.Duration) bool {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+		if err := ping(ctx, conn, interval); err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+			log.Printf("Se perdió la conexión con la base de datos: %v", err)
+			return true
+		}
+	}
+}
+
+// ping acota cada verificación a timeout para que una base colgada no
+// bloquee el reintento.
+func ping(ctx context.Context, conn *gorm.DB, timeout time.Duration) error {
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return sqlDB.PingContext(ctx)
+}
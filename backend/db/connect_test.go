@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 10 * time.Second, Jitter: 0.5}
+
+	assert.Equal(t, time.Second, b.Delay(0, 0))
+	assert.Equal(t, 8*time.Second, b.Delay(3, 0))
+	// Se corta en Max aunque los intentos sigan.
+	assert.Equal(t, 10*time.Second, b.Delay(4, 0))
+	assert.Equal(t, 10*time.Second, b.Delay(1000, 0))
+	// El jitter resta hasta la mitad de la espera.
+	assert.Equal(t, 2*time.Second, b.Delay(2, 1))
+	assert.Equal(t, 3*time.Second, b.Delay(2, 0.5))
+}
+
+func TestLoadConfig_PoolAndRetry(t *testing.T) {
+	t.Setenv("DB_DRIVER", "mysql")
+	t.Setenv("DB_MAX_OPEN_CONNS", "50")
+	t.Setenv("DB_MAX_IDLE_CONNS", "5")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+	t.Setenv("DB_CONNECT_BACKOFF_INITIAL", "500ms")
+	t.Setenv("DB_CONNECT_BACKOFF_MAX", "1m")
+	t.Setenv("DB_CONNECT_JITTER", "0")
+	t.Setenv("DB_CONNECT_MAX_ATTEMPTS", "0")
+
+	cfg, err := LoadConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, PoolConfig{MaxOpenConns: 50, MaxIdleConns: 5, ConnMaxLifetime: time.Hour}, cfg.Pool)
+	assert.Equal(t, Backoff{Initial: 500 * time.Millisecond, Max: time.Minute}, cfg.Retry.Backoff)
+	assert.Zero(t, cfg.Retry.MaxAttempts)
+	assert.Equal(t, 15*time.Second, cfg.Retry.CheckInterval)
+}
+
+func TestLoadConfig_InvalidRetry(t *testing.T) {
+	tests := map[string]map[string]string{
+		"max below initial": {"DB_CONNECT_BACKOFF_INITIAL": "10s", "DB_CONNECT_BACKOFF_MAX": "1s"},
+		"jitter above one":  {"DB_CONNECT_JITTER": "1.5"},
+		"negative pool":     {"DB_MAX_OPEN_CONNS": "-1"},
+		"bad duration":      {"DB_HEALTH_CHECK_INTERVAL": "often"},
+	}
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("DB_DRIVER", "mysql")
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+
+			_, err := LoadConfig()
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+// unreachablePostgres apunta a un puerto donde no escucha nadie: Open no
+// falla porque no se conecta, y cada intento de Connect falla enseguida.
+func unreachablePostgres(t *testing.T) Config {
+	t.Helper()
+	return Config{
+		Driver: DriverPostgres, Host: "127.0.0.1", Port: "1", User: "u", Password: "p", Name: "gametracker",
+		Pool:  PoolConfig{MaxOpenConns: 2, MaxIdleConns: 2, ConnMaxLifetime: time.Minute},
+		Retry: RetryConfig{Backoff: Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond}, CheckInterval: time.Second},
+	}
+}
+
+func TestConnect_GivesUpAfterMaxAttempts(t *testing.T) {
+	cfg := unreachablePostgres(t)
+	conn, err := Open(cfg)
+	require.NoError(t, err)
+
+	err = Connect(context.Background(), conn, cfg, 3)
+
+	assert.ErrorContains(t, err, "database not available after 3 attempts")
+}
+
+func TestConnect_StopsWhenContextIsCanceled(t *testing.T) {
+	cfg := unreachablePostgres(t)
+	conn, err := Open(cfg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = Connect(ctx, conn, cfg, 0)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWatch_CallsOnConnectAndStops(t *testing.T) {
+	conn, err := Open(Config{Driver: DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	cfg := Config{Driver: DriverSQLite, Path: ":memory:", Retry: RetryConfig{CheckInterval: time.Millisecond}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	connected := 0
+	go func() {
+		Watch(ctx, conn, cfg, func() {
+			connected++
+			cancel()
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after the context was canceled")
+	}
+	assert.Equal(t, 1, connected)
+}
+
+func TestMonitor_ReportsLostConnection(t *testing.T) {
+	conn, err := Open(Config{Driver: DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	sqlDB, err := conn.DB()
+	require.NoError(t, err)
+	sqlDB.Close()
+
+	assert.True(t, monitor(context.Background(), conn, time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, monitor(ctx, conn, time.Millisecond))
+}
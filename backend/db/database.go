@@ -1,83 +1,222 @@
 package db
 
 import (
-	"gametracker/models"
-	"log"
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-func ConnectDB() {
-	dbHost := getEnv("DB_HOST", "db")
-	dbPort := getEnv("DB_PORT", "3306")
-	dbUser := getEnv("DB_USER", "root")
-	dbPassword := getEnv("DB_PASSWORD", "root")
-	dbName := getEnv("DB_NAME", "gametracker")
+// Motores soportados en DB_DRIVER. Coinciden con Dialector.Name() de gorm,
+// que es lo que usa el Migrator para elegir las migraciones.
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Config es la conexión leída del entorno. Path solo se usa con sqlite; el
+// resto de los datos de conexión y Pool solo con mysql y postgres.
+type Config struct {
+	Driver   string
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	Path     string
+	Pool     PoolConfig
+	Retry    RetryConfig
+}
 
-	baseDSN := dbUser + ":" + dbPassword + "@tcp(" + dbHost + ":" + dbPort + ")/" +
-		"?charset=utf8mb4&parseTime=True&loc=Local"
-	fullDSN := dbUser + ":" + dbPassword + "@tcp(" + dbHost + ":" + dbPort + ")/" + dbName +
-		"?charset=utf8mb4&parseTime=True&loc=Local"
+// PoolConfig son los límites del pool de database/sql.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// dialTimeout acota cada intento de conexión para que un host que no
+// contesta no demore el backoff ni los requests.
+const dialTimeout = 5 * time.Second
+
+// LoadConfig lee DB_DRIVER (por defecto mysql), los DB_* del motor elegido,
+// el tamaño del pool y los reintentos de conexión.
+func LoadConfig() (Config, error) {
+	cfg := Config{
+		Driver:   strings.ToLower(strings.TrimSpace(getEnv("DB_DRIVER", DriverMySQL))),
+		Host:     getEnv("DB_HOST", "db"),
+		User:     getEnv("DB_USER", "root"),
+		Password: getEnv("DB_PASSWORD", "root"),
+		Name:     getEnv("DB_NAME", "gametracker"),
+		Path:     getEnv("DB_PATH", "data/gametracker.db"),
+	}
+	switch cfg.Driver {
+	case DriverMySQL:
+		cfg.Port = getEnv("DB_PORT", "3306")
+	case DriverPostgres:
+		cfg.Port = getEnv("DB_PORT", "5432")
+	case DriverSQLite:
+	default:
+		return cfg, fmt.Errorf("DB_DRIVER %q is not supported (valid: %s, %s, %s)", cfg.Driver, DriverMySQL, DriverPostgres, DriverSQLite)
+	}
 
 	var err error
-	maxRetries := 30
-	retryDelay := 10 * time.Second
-
-	var serverDB *gorm.DB
-	for i := 0; i < maxRetries; i++ {
-		serverDB, err = gorm.Open(mysql.Open(baseDSN), &gorm.Config{})
-		if err == nil {
-			log.Println("Conexión al servidor MySQL establecida (sin DB seleccionada)")
-			break
-		}
-		log.Printf("Intento %d/%d: No se pudo conectar al servidor MySQL: %v", i+1, maxRetries, err)
-		if i < maxRetries-1 {
-			log.Printf("Reintentando en %v...", retryDelay)
-			time.Sleep(retryDelay)
-		}
+	if cfg.Pool.MaxOpenConns, err = intFromEnv("DB_MAX_OPEN_CONNS", 25); err != nil {
+		return cfg, err
+	}
+	if cfg.Pool.MaxIdleConns, err = intFromEnv("DB_MAX_IDLE_CONNS", 25); err != nil {
+		return cfg, err
+	}
+	if cfg.Pool.ConnMaxLifetime, err = durationFromEnv("DB_CONN_MAX_LIFETIME", 30*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.Retry.Initial, err = durationFromEnv("DB_CONNECT_BACKOFF_INITIAL", time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.Retry.Max, err = durationFromEnv("DB_CONNECT_BACKOFF_MAX", 30*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.Retry.Max < cfg.Retry.Initial {
+		return cfg, fmt.Errorf("DB_CONNECT_BACKOFF_MAX (%v) es menor que DB_CONNECT_BACKOFF_INITIAL (%v)", cfg.Retry.Max, cfg.Retry.Initial)
+	}
+	if cfg.Retry.Jitter, err = floatFromEnv("DB_CONNECT_JITTER", 0.2); err != nil {
+		return cfg, err
+	}
+	if cfg.Retry.Jitter > 1 {
+		return cfg, fmt.Errorf("DB_CONNECT_JITTER inválido: debe estar entre 0 y 1")
+	}
+	if cfg.Retry.MaxAttempts, err = intFromEnv("DB_CONNECT_MAX_ATTEMPTS", 10); err != nil {
+		return cfg, err
+	}
+	if cfg.Retry.CheckInterval, err = durationFromEnv("DB_HEALTH_CHECK_INTERVAL", 15*time.Second); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// target describe la base para los logs, sin credenciales.
+func (cfg Config) target() string {
+	if cfg.Driver == DriverSQLite {
+		return cfg.Path
+	}
+	return cfg.Host + ":" + cfg.Port + "/" + cfg.Name
+}
+
+// Open prepara la conexión del motor indicado sin conectarse todavía: con
+// mysql y postgres el pool abre las conexiones a medida que se necesitan, así
+// el servidor puede arrancar con la base caída. Connect y Watch esperan a
+// que responda.
+func Open(cfg Config) (*gorm.DB, error) {
+	var conn *gorm.DB
+	var err error
+	// DisableAutomaticPing: gorm no intenta conectarse al abrir.
+	gormConfig := &gorm.Config{DisableAutomaticPing: true}
+	switch cfg.Driver {
+	case DriverMySQL:
+		// Sin SkipInitializeWithVersion el dialecto consulta la versión del
+		// servidor al abrir; solo la usa para ajustes de MariaDB y MySQL 5.x.
+		conn, err = gorm.Open(mysql.New(mysql.Config{
+			DSN:                       mysqlDSN(cfg, cfg.Name),
+			SkipInitializeWithVersion: true,
+		}), gormConfig)
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable connect_timeout=%d",
+			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, int(dialTimeout.Seconds()))
+		conn, err = gorm.Open(postgres.Open(dsn), gormConfig)
+	case DriverSQLite:
+		return openSQLite(cfg.Path)
+	default:
+		return nil, fmt.Errorf("DB_DRIVER %q is not supported", cfg.Driver)
+	}
+	if err != nil {
+		return nil, err
 	}
+	sqlDB, err := conn.DB()
 	if err != nil {
-		log.Fatal("No se pudo conectar al servidor MySQL después de ", maxRetries, " intentos: ", err)
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.Pool.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Pool.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Pool.ConnMaxLifetime)
+	return conn, nil
+}
+
+func mysqlDSN(cfg Config, database string) string {
+	return cfg.User + ":" + cfg.Password + "@tcp(" + cfg.Host + ":" + cfg.Port + ")/" + database +
+		"?charset=utf8mb4&parseTime=True&loc=Local&timeout=" + dialTimeout.String()
+}
 
-	createDBStmt := "CREATE DATABASE IF NOT EXISTS `" + dbName + "` CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
-	if err := serverDB.Exec(createDBStmt).Error; err != nil {
-		log.Fatal("Error creando la base de datos: ", err)
+// createMySQLDatabase crea la base si no existe, con una conexión aparte al
+// servidor (la de DB apunta a una base que todavía puede no existir).
+func createMySQLDatabase(ctx context.Context, cfg Config) error {
+	serverDB, err := gorm.Open(mysql.Open(mysqlDSN(cfg, "")), &gorm.Config{})
+	if err != nil {
+		return err
 	}
+	createDBStmt := "CREATE DATABASE IF NOT EXISTS `" + cfg.Name + "` CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
+	err = serverDB.WithContext(ctx).Exec(createDBStmt).Error
 	if sqlDB, cerr := serverDB.DB(); cerr == nil {
 		_ = sqlDB.Close()
 	}
+	if err != nil {
+		return fmt.Errorf("create database: %w", err)
+	}
+	return nil
+}
 
-	for i := 0; i < maxRetries; i++ {
-		DB, err = gorm.Open(mysql.Open(fullDSN), &gorm.Config{})
-		if err == nil {
-			log.Println("Conexión a la base de datos establecida exitosamente: ", dbName)
-			break
-		}
-		log.Printf("Intento %d/%d: No se pudo conectar a la DB %q: %v", i+1, maxRetries, dbName, err)
-		if i < maxRetries-1 {
-			log.Printf("Reintentando en %v...", retryDelay)
-			time.Sleep(retryDelay)
+// openSQLite abre (o crea) el archivo de la base; ":memory:" da una base en
+// memoria, que es lo que usan los tests. Las claves foráneas están apagadas
+// por defecto en SQLite y sin ellas no funcionan los ON DELETE CASCADE.
+//
+// SQLite admite un solo escritor a la vez, así que el pool se limita a una
+// conexión: los requests se encolan en vez de fallar con "database is
+// locked", y la base en memoria es la misma para todo el proceso.
+func openSQLite(path string) (*gorm.DB, error) {
+	if path != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
 		}
 	}
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	if path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+	conn, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 	if err != nil {
-		log.Fatal("No se pudo conectar a la DB después de ", maxRetries, " intentos: ", err)
+		return nil, err
 	}
-
-	if sqlDB, cerr := DB.DB(); cerr == nil {
-		sqlDB.SetMaxOpenConns(25)
-		sqlDB.SetMaxIdleConns(25)
-		sqlDB.SetConnMaxLifetime(30 * time.Minute)
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(1)
+	// Con ":memory:" cerrar la única conexión borraría la base.
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+	return conn, nil
+}
 
-	if err := DB.AutoMigrate(&models.Game{}, &models.User{}); err != nil {
-		log.Fatal("Error en migración de modelos: ", err)
+// Close cierra el pool de DB; se usa al apagar el servidor.
+func Close() error {
+	if DB == nil {
+		return nil
 	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
 
 func getEnv(key, defaultValue string) string {
@@ -86,3 +225,39 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func intFromEnv(key string, defaultValue int) (int, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return defaultValue, fmt.Errorf("%s inválido: %s", key, raw)
+	}
+	return n, nil
+}
+
+func floatFromEnv(key string, defaultValue float64) (float64, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f < 0 {
+		return defaultValue, fmt.Errorf("%s inválido: %s", key, raw)
+	}
+	return f, nil
+}
+
+func durationFromEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return defaultValue, fmt.Errorf("%s inválido: %s", key, raw)
+	}
+	return d, nil
+}
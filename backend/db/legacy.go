@@ -0,0 +1,248 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrLegacySchema indica una base creada con AutoMigrate, antes de las
+	// migraciones versionadas, a la que le faltan columnas del esquema
+	// actual. 0001_initial_schema usa CREATE TABLE IF NOT EXISTS y no
+	// modificaría esas tablas, así que no se puede marcar como aplicada
+	// hasta adoptar la base con AdoptLegacy.
+	ErrLegacySchema = errors.New("database was created before versioned migrations and is missing columns")
+	// ErrLegacyOwner indica que AdoptLegacy no pudo elegir a qué usuario
+	// asignar los juegos de una base sin games.user_id.
+	ErrLegacyOwner = errors.New("cannot choose the owner of the existing games")
+)
+
+// schemaModels son los modelos de las tablas de 0001_initial_schema; sirven
+// para comparar una base creada con AutoMigrate con el esquema actual.
+var schemaModels = []interface{}{
+	&models.User{}, &models.Game{}, &models.RefreshToken{}, &models.PlaySession{},
+	&models.MetadataCache{}, &models.GameCover{}, &models.WishlistItem{},
+	&models.GameOwnership{}, &models.Tag{}, &models.GameTag{}, &models.Collection{},
+	&models.CollectionGame{}, &models.Achievement{},
+}
+
+// laterColumns son columnas que agregan migraciones posteriores a
+// 0001_initial_schema; una base de AutoMigrate no tiene por qué tenerlas.
+var laterColumns = map[string]bool{
+	"play_sessions.ownership_id": true,
+	"games.release_date":         true,
+	"games.developer":            true,
+}
+
+// gameOwnerColumn no se puede agregar como las demás columnas: es NOT NULL,
+// sin default y con foreign key.
+const gameOwnerColumn = "games.user_id"
+
+// userIDColumn es el tipo de games.user_id en cada motor, como en
+// 0001_initial_schema.
+var userIDColumn = map[string]string{
+	DriverMySQL:    "bigint unsigned",
+	DriverPostgres: "bigint",
+	DriverSQLite:   "integer",
+}
+
+// legacyColumn es una columna de un modelo que falta en su tabla.
+type legacyColumn struct {
+	model interface{}
+	table string
+	name  string
+}
+
+func (c legacyColumn) String() string {
+	return c.table + "." + c.name
+}
+
+// checkLegacySchema se llama antes de aplicar la primera migración. Una
+// base sin la tabla games es nueva; si la tiene, viene de AutoMigrate y
+// cada tabla existente tiene que tener todas las columnas del modelo.
+func checkLegacySchema(conn *gorm.DB) error {
+	if !conn.Migrator().HasTable(&models.Game{}) {
+		return nil
+	}
+	missing, err := legacyMissingColumns(conn)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		names := make([]string, len(missing))
+		for i, column := range missing {
+			names[i] = column.String()
+		}
+		return fmt.Errorf("%w: %s", ErrLegacySchema, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// legacyMissingColumns devuelve las columnas de los modelos que no están en
+// las tablas que ya existen. Las tablas que no existen las crea la
+// migración completas.
+func legacyMissingColumns(conn *gorm.DB) ([]legacyColumn, error) {
+	var missing []legacyColumn
+	for _, model := range schemaModels {
+		stmt := &gorm.Statement{DB: conn}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		if !conn.Migrator().HasTable(stmt.Schema.Table) {
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration || laterColumns[stmt.Schema.Table+"."+field.DBName] {
+				continue
+			}
+			if !conn.Migrator().HasColumn(model, field.DBName) {
+				missing = append(missing, legacyColumn{model: model, table: stmt.Schema.Table, name: field.DBName})
+			}
+		}
+	}
+	return missing, nil
+}
+
+// AdoptLegacy prepara una base de AutoMigrate para que Up pueda registrar
+// 0001_initial_schema: agrega a las tablas que existen las columnas que les
+// faltan y, si games no tenía user_id, asigna todos los juegos a owner (o,
+// con owner vacío, al único usuario de la base). Devuelve el ID del dueño
+// asignado, o 0 si no hizo falta. En una base nueva, ya migrada o a la que
+// no le falta nada no hace nada. Como en Up, en MySQL el DDL no es
+// transaccional.
+func (m *Migrator) AdoptLegacy(owner string) (uint, error) {
+	pending, err := m.Pending()
+	if err != nil || len(pending) == 0 || len(pending) != len(m.migrations) || !m.conn.Migrator().HasTable(&models.Game{}) {
+		return 0, err
+	}
+	missing, err := legacyMissingColumns(m.conn)
+	if err != nil || len(missing) == 0 {
+		return 0, err
+	}
+
+	var ownerID uint
+	err = m.conn.Transaction(func(tx *gorm.DB) error {
+		addOwner := false
+		for _, column := range missing {
+			if column.String() == gameOwnerColumn {
+				addOwner = true
+				continue
+			}
+			if err := tx.Migrator().AddColumn(column.model, column.name); err != nil {
+				return fmt.Errorf("add %s: %w", column, err)
+			}
+		}
+		if addOwner {
+			var err error
+			if ownerID, err = legacyOwner(tx, owner); err != nil {
+				return err
+			}
+			if err := addGameOwner(tx, ownerID); err != nil {
+				return fmt.Errorf("add %s: %w", gameOwnerColumn, err)
+			}
+		}
+		if tx.Dialector.Name() == DriverMySQL {
+			// En MySQL los índices de 0001 van dentro del CREATE TABLE,
+			// que no corre sobre las tablas que ya existen.
+			return createMissingIndexes(tx)
+		}
+		return nil
+	})
+	return ownerID, err
+}
+
+// legacyOwner busca al usuario username o, si está vacío, al único usuario
+// de la base. Sin juegos no hace falta dueño y devuelve 0.
+func legacyOwner(tx *gorm.DB, username string) (uint, error) {
+	var games int64
+	if err := tx.Table("games").Count(&games).Error; err != nil || games == 0 {
+		return 0, err
+	}
+	if !tx.Migrator().HasTable(&models.User{}) {
+		return 0, fmt.Errorf("%w: there is no users table", ErrLegacyOwner)
+	}
+	query := tx.Table("users")
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	var ids []uint
+	if err := query.Order("id").Limit(2).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	switch {
+	case username != "" && len(ids) == 0:
+		return 0, fmt.Errorf("%w: user %q not found", ErrLegacyOwner, username)
+	case username == "" && len(ids) != 1:
+		return 0, fmt.Errorf("%w: the database does not have exactly one user, pick one by username", ErrLegacyOwner)
+	}
+	return ids[0], nil
+}
+
+// addGameOwner agrega games.user_id con todos los juegos asignados a
+// ownerID y la foreign key a users. La columna se crea nullable para poder
+// completarla; SQLite no permite ponerle NOT NULL después, así que en ese
+// motor lo garantiza la aplicación.
+func addGameOwner(tx *gorm.DB, ownerID uint) error {
+	driver := tx.Dialector.Name()
+	add := "ALTER TABLE games ADD COLUMN user_id " + userIDColumn[driver] + " NULL"
+	if driver == DriverSQLite {
+		// SQLite solo acepta la foreign key dentro del ADD COLUMN.
+		add += " REFERENCES users(id) ON DELETE CASCADE"
+	}
+	statements := []string{add}
+	switch driver {
+	case DriverMySQL:
+		statements = append(statements,
+			"ALTER TABLE games MODIFY user_id bigint unsigned NOT NULL",
+			// Con el índice creado antes, MySQL no agrega otro para la FK.
+			"ALTER TABLE games ADD INDEX idx_games_user_id (user_id)",
+			"ALTER TABLE games ADD CONSTRAINT fk_games_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE")
+	case DriverPostgres:
+		statements = append(statements,
+			"ALTER TABLE games ALTER COLUMN user_id SET NOT NULL",
+			"ALTER TABLE games ADD CONSTRAINT fk_games_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE")
+	}
+
+	if err := tx.Exec(statements[0]).Error; err != nil {
+		return err
+	}
+	if ownerID != 0 {
+		if err := tx.Exec("UPDATE games SET user_id = ?", ownerID).Error; err != nil {
+			return err
+		}
+	}
+	for _, stmt := range statements[1:] {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createMissingIndexes crea los índices de schemaModels que no están en las
+// tablas que ya existen.
+func createMissingIndexes(tx *gorm.DB) error {
+	for _, model := range schemaModels {
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		if !tx.Migrator().HasTable(stmt.Schema.Table) {
+			continue
+		}
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if tx.Migrator().HasIndex(model, idx.Name) {
+				continue
+			}
+			if err := tx.Migrator().CreateIndex(model, idx.Name); err != nil {
+				return fmt.Errorf("create index %s: %w", idx.Name, err)
+			}
+		}
+	}
+	return nil
+}
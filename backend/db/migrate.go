@@ -0,0 +1,308 @@
+package db
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles son los .sql de migrations/<motor>, embebidos en el
+// binario. Cada migración es un par NNNN_nombre.up.sql / NNNN_nombre.down.sql
+// y tiene que existir, con la misma versión y nombre, para los tres motores;
+// las sentencias terminan con ";" al final de la línea.
+//
+//go:embed migrations/mysql/*.sql migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// ErrSchemaBehind indica que hay migraciones sin aplicar en la base.
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus es una migración conocida y, si se aplicó, cuándo.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaMigration es una fila de schema_migrations: una por migración
+// aplicada.
+type schemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// createSchemaMigrations varía solo en el tipo de applied_at.
+func createSchemaMigrations(driver string) string {
+	timestamp := "DATETIME(3)"
+	switch driver {
+	case DriverPostgres:
+		timestamp = "TIMESTAMPTZ"
+	case DriverSQLite:
+		timestamp = "DATETIME"
+	}
+	return "CREATE TABLE IF NOT EXISTS schema_migrations (" +
+		"version BIGINT NOT NULL PRIMARY KEY, " +
+		"name VARCHAR(255) NOT NULL, " +
+		"applied_at " + timestamp + " NOT NULL)"
+}
+
+// Migrator aplica y revierte las migraciones sobre una conexión.
+type Migrator struct {
+	conn       *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator usa las migraciones embebidas en el binario para el motor de
+// conn.
+func NewMigrator(conn *gorm.DB) (*Migrator, error) {
+	dir, err := embeddedMigrations(conn.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(conn, dir)
+}
+
+func embeddedMigrations(driver string) (fs.FS, error) {
+	switch driver {
+	case DriverMySQL, DriverPostgres, DriverSQLite:
+		return fs.Sub(migrationFiles, "migrations/"+driver)
+	}
+	return nil, fmt.Errorf("no migrations for database driver %q", driver)
+}
+
+func newMigrator(conn *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{conn: conn, migrations: migrations}, nil
+}
+
+// loadMigrations lee los pares up/down de fsys ordenados por versión. Una
+// migración sin alguno de sus dos archivos, o una versión repetida, es un
+// error.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		m := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(m[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: m[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up aplica en orden las migraciones pendientes y devuelve las aplicadas.
+// Cada una corre en su propia transacción; en MySQL el DDL no es
+// transaccional, así que una migración que falla a la mitad puede dejar
+// cambios que hay que revisar a mano (en PostgreSQL y SQLite se revierte).
+//
+// En una base sin migraciones aplicadas pero con tablas de AutoMigrate
+// devuelve ErrLegacySchema si les faltan columnas, sin tocar nada.
+func (m *Migrator) Up() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 && len(pending) == len(m.migrations) {
+		if err := checkLegacySchema(m.conn); err != nil {
+			return nil, err
+		}
+	}
+	if len(pending) > 0 {
+		if err := m.conn.Exec(createSchemaMigrations(m.conn.Dialector.Name())).Error; err != nil {
+			return nil, err
+		}
+	}
+	for i, migration := range pending {
+		err := m.conn.Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, migration.Up); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return pending[:i], fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// Down revierte las últimas steps migraciones aplicadas, de la más nueva a
+// la más vieja, y devuelve las revertidas.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[uint]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	var reverted []Migration
+	for i := len(applied) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration, ok := known[applied[i].Version]
+		if !ok {
+			return reverted, fmt.Errorf("migration %d_%s is applied but this binary does not know it", applied[i].Version, applied[i].Name)
+		}
+		err := m.conn.Transaction(func(tx *gorm.DB) error {
+			if err := execStatements(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// Status devuelve todas las migraciones conocidas con su fecha de
+// aplicación (nil si está pendiente).
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[uint]time.Time, len(applied))
+	for _, row := range applied {
+		appliedAt[row.Version] = row.AppliedAt
+	}
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if at, ok := appliedAt[migration.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Pending devuelve las migraciones conocidas que no están aplicadas.
+func (m *Migrator) Pending() ([]Migration, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Check devuelve ErrSchemaBehind si quedan migraciones pendientes. Una base
+// con migraciones más nuevas que el binario se acepta.
+func (m *Migrator) Check() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migration(s), first is %d_%s", ErrSchemaBehind, len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// applied lee las versiones aplicadas sin ejecutar DDL: Check corre en cada
+// /readyz. Sin la tabla schema_migrations no hay nada aplicado; la crea Up.
+func (m *Migrator) applied() ([]schemaMigration, error) {
+	if !m.conn.Migrator().HasTable(&schemaMigration{}) {
+		return nil, nil
+	}
+	var rows []schemaMigration
+	err := m.conn.Order("version ASC").Find(&rows).Error
+	return rows, err
+}
+
+// execStatements ejecuta una por una las sentencias del script: el driver de
+// MySQL no acepta varias en un mismo Exec.
+func execStatements(tx *gorm.DB, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements separa el script en sentencias terminadas en ";" al final
+// de una línea y descarta las líneas de comentario (--).
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmt := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			statements = append(statements, stmt)
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,203 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func setupMigrator(t *testing.T, files fstest.MapFS) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	conn, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	require.NoError(t, err)
+	migrator, err := newMigrator(conn, files)
+	require.NoError(t, err)
+	return migrator, mock
+}
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_initial.up.sql":     {Data: []byte("CREATE TABLE a (id INT);\n")},
+		"0001_initial.down.sql":   {Data: []byte("DROP TABLE a;\n")},
+		"0002_add_b.up.sql":       {Data: []byte("-- columna nueva\nALTER TABLE a\n  ADD COLUMN b INT;\nCREATE INDEX idx_a_b ON a (b);\n")},
+		"0002_add_b.down.sql":     {Data: []byte("ALTER TABLE a DROP COLUMN b;\n")},
+		"README.md":               {Data: []byte("ignorado")},
+		"0003_sin_down.up.sql.bk": {Data: []byte("ignorado")},
+	}
+}
+
+// expectHasTable espera las consultas con las que gorm averigua en MySQL si
+// existe table.
+func expectHasTable(mock sqlmock.Sqlmock, table string, exists bool) {
+	count := 0
+	if exists {
+		count = 1
+	}
+	mock.ExpectQuery("SELECT DATABASE\\(\\)").WillReturnRows(sqlmock.NewRows([]string{"database()"}).AddRow("gametracker"))
+	mock.ExpectQuery("SELECT SCHEMA_NAME from Information_schema.SCHEMATA").WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).AddRow("gametracker"))
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM information_schema.tables").
+		WithArgs("gametracker", table, "BASE TABLE").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+// expectApplied espera la lectura de las versiones aplicadas; sin versiones
+// la tabla schema_migrations todavía no existe.
+func expectApplied(mock sqlmock.Sqlmock, versions ...uint) {
+	expectHasTable(mock, "schema_migrations", len(versions) > 0)
+	if len(versions) == 0 {
+		return
+	}
+	rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+	for _, v := range versions {
+		rows.AddRow(v, "initial", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	}
+	mock.ExpectQuery("SELECT \\* FROM `schema_migrations` ORDER BY version ASC").WillReturnRows(rows)
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	var versions []string
+	for _, driver := range []string{DriverMySQL, DriverPostgres, DriverSQLite} {
+		dir, err := embeddedMigrations(driver)
+		require.NoError(t, err)
+		migrations, err := loadMigrations(dir)
+		require.NoError(t, err, driver)
+		require.NotEmpty(t, migrations, driver)
+		assert.Equal(t, uint(1), migrations[0].Version)
+
+		var names []string
+		for i, m := range migrations {
+			assert.NotEmpty(t, splitStatements(m.Up), "%s %d_%s up", driver, m.Version, m.Name)
+			assert.NotEmpty(t, splitStatements(m.Down), "%s %d_%s down", driver, m.Version, m.Name)
+			if i > 0 {
+				assert.Greater(t, m.Version, migrations[i-1].Version)
+			}
+			names = append(names, fmt.Sprintf("%d_%s", m.Version, m.Name))
+		}
+		// Los tres motores tienen que tener las mismas migraciones.
+		if versions == nil {
+			versions = names
+		}
+		assert.Equal(t, versions, names, driver)
+	}
+
+	_, err := embeddedMigrations("oracle")
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations_MissingDown(t *testing.T) {
+	_, err := loadMigrations(fstest.MapFS{
+		"0001_initial.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+	})
+
+	assert.ErrorContains(t, err, "needs both up and down files")
+}
+
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements("-- comentario\nCREATE TABLE a (\n  id INT\n);\n\nDROP TABLE b;\nSELECT 1")
+
+	assert.Equal(t, []string{"CREATE TABLE a (\n  id INT\n)", "DROP TABLE b", "SELECT 1"}, statements)
+}
+
+func TestUp_AppliesPendingInOrder(t *testing.T) {
+	// Arrange: la 0001 ya está aplicada
+	migrator, mock := setupMigrator(t, testMigrations())
+	expectApplied(mock, 1)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec("^ALTER TABLE a\n  ADD COLUMN b INT$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("^CREATE INDEX idx_a_b ON a \\(b\\)$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO `schema_migrations` \\(`version`,`name`,`applied_at`\\) VALUES \\(\\?,\\?,\\?\\)").
+		WithArgs(uint(2), "add_b", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	// Act
+	applied, err := migrator.Up()
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, "add_b", applied[0].Name)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUp_StopsOnError(t *testing.T) {
+	migrator, mock := setupMigrator(t, testMigrations())
+	expectApplied(mock)
+	expectHasTable(mock, "games", false)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE a").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	applied, err := migrator.Up()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, "migration 1_initial")
+	assert.Empty(t, applied)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown_RevertsLatest(t *testing.T) {
+	migrator, mock := setupMigrator(t, testMigrations())
+	expectApplied(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("^ALTER TABLE a DROP COLUMN b$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM `schema_migrations` WHERE `schema_migrations`.`version` = \\?").
+		WithArgs(uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	reverted, err := migrator.Down(1)
+
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, uint(2), reverted[0].Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatusAndCheck(t *testing.T) {
+	migrator, mock := setupMigrator(t, testMigrations())
+	expectApplied(mock, 1)
+	expectApplied(mock, 1)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+
+	err = migrator.Check()
+	assert.ErrorIs(t, err, ErrSchemaBehind)
+	assert.ErrorContains(t, err, "2_add_b")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheck_UpToDate(t *testing.T) {
+	migrator, mock := setupMigrator(t, testMigrations())
+	expectApplied(mock, 1, 2)
+
+	require.NoError(t, migrator.Check())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheck_DoesNotCreateSchemaMigrations(t *testing.T) {
+	// /readyz llama a Check en cada probe: sin la tabla no se ejecuta DDL
+	migrator, mock := setupMigrator(t, testMigrations())
+	expectApplied(mock)
+
+	err := migrator.Check()
+
+	assert.ErrorIs(t, err, ErrSchemaBehind)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
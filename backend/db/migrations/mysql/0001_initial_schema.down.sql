@@ -0,0 +1,16 @@
+-- ATENCIÓN: revertir el esquema inicial borra TODAS las tablas y sus datos
+-- (usuarios, juegos, sesiones...). `migrate down` se niega a hacerlo sin
+-- --force; hacer un backup antes.
+DROP TABLE IF EXISTS `achievements`;
+DROP TABLE IF EXISTS `collection_games`;
+DROP TABLE IF EXISTS `collections`;
+DROP TABLE IF EXISTS `game_tags`;
+DROP TABLE IF EXISTS `tags`;
+DROP TABLE IF EXISTS `game_ownerships`;
+DROP TABLE IF EXISTS `wishlist_items`;
+DROP TABLE IF EXISTS `game_covers`;
+DROP TABLE IF EXISTS `metadata_caches`;
+DROP TABLE IF EXISTS `play_sessions`;
+DROP TABLE IF EXISTS `refresh_tokens`;
+DROP TABLE IF EXISTS `games`;
+DROP TABLE IF EXISTS `users`;
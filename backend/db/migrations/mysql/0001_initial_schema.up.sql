@@ -0,0 +1,201 @@
+-- Esquema inicial: las tablas que creaba AutoMigrate. IF NOT EXISTS solo
+-- crea las tablas que faltan y no modifica las existentes: antes de aplicar
+-- esta migración en una base de AutoMigrate, Migrator.Up verifica que sus
+-- tablas tengan todas las columnas y, si no, falla con ErrLegacySchema.
+
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `username` varchar(50) NOT NULL,
+    `email` varchar(100) NOT NULL,
+    `password` varchar(255) NOT NULL,
+    `first_name` varchar(50),
+    `last_name` varchar(50),
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_users_username` (`username`),
+    UNIQUE INDEX `idx_users_email` (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `games` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `title` varchar(200) NOT NULL,
+    `platform` varchar(80) NOT NULL,
+    `genre` varchar(80),
+    `status` varchar(32),
+    `progress` bigint,
+    `hours_played` decimal(10,2) DEFAULT 0,
+    `personal_note` text,
+    `score` bigint,
+    `started_at` datetime(3) NULL,
+    `finished_at` datetime(3) NULL,
+    `cover_url` varchar(500),
+    `version` bigint unsigned NOT NULL DEFAULT 1,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    `progress_from_achievements` boolean NOT NULL DEFAULT false,
+    `deleted_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_games_user_id` (`user_id`),
+    INDEX `idx_title_platform` (`title`,`platform`),
+    FULLTEXT INDEX `idx_games_fulltext` (`title`,`personal_note`),
+    INDEX `idx_games_genre` (`genre`),
+    INDEX `idx_games_status` (`status`),
+    INDEX `idx_games_started_at` (`started_at`),
+    INDEX `idx_games_finished_at` (`finished_at`),
+    INDEX `idx_games_deleted_at` (`deleted_at`),
+    CONSTRAINT `fk_games_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `score_between_0_10` CHECK (score >= 0 AND score <= 10),
+    CONSTRAINT `progress_between_0_100` CHECK (progress >= 0 AND progress <= 100)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `refresh_tokens` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `family_id` varchar(64) NOT NULL,
+    `token_hash` char(64) NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `used_at` datetime(3) NULL,
+    `revoked_at` datetime(3) NULL,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_refresh_tokens_user_id` (`user_id`),
+    INDEX `idx_refresh_tokens_family_id` (`family_id`),
+    UNIQUE INDEX `idx_refresh_tokens_token_hash` (`token_hash`),
+    CONSTRAINT `fk_refresh_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `play_sessions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `game_id` bigint unsigned NOT NULL,
+    `started_at` datetime(3) NOT NULL,
+    `ended_at` datetime(3) NULL,
+    `duration_minutes` bigint NOT NULL DEFAULT 0,
+    `note` varchar(500),
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_play_sessions_game_id` (`game_id`),
+    INDEX `idx_play_sessions_started_at` (`started_at`),
+    CONSTRAINT `fk_play_sessions_game` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `metadata_caches` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `provider` varchar(32) NOT NULL,
+    `kind` varchar(16) NOT NULL,
+    `lookup_key` varchar(200) NOT NULL,
+    `payload` mediumtext NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_metadata_cache_lookup` (`provider`,`kind`,`lookup_key`),
+    INDEX `idx_metadata_caches_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `game_covers` (
+    `game_id` bigint unsigned,
+    `hash` char(16) NOT NULL,
+    `content_type` varchar(32) NOT NULL,
+    `width` bigint,
+    `height` bigint,
+    `size` bigint,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`game_id`),
+    CONSTRAINT `fk_game_covers_game` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `wishlist_items` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `title` varchar(200) NOT NULL,
+    `platform` varchar(80),
+    `target_price` decimal(10,2),
+    `release_date` datetime(3) NULL,
+    `priority` bigint NOT NULL DEFAULT 3,
+    `note` varchar(500),
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_wishlist_items_user_id` (`user_id`),
+    INDEX `idx_wishlist_items_release_date` (`release_date`),
+    CONSTRAINT `fk_wishlist_items_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `priority_between_1_5` CHECK (priority >= 1 AND priority <= 5)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `game_ownerships` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `game_id` bigint unsigned NOT NULL,
+    `platform` varchar(80) NOT NULL,
+    `store` varchar(80),
+    `edition` varchar(120),
+    `purchased_at` datetime(3) NULL,
+    `price_paid` decimal(10,2),
+    `format` varchar(16),
+    `hours_played` decimal(10,2) DEFAULT 0,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_game_ownerships_game_id` (`game_id`),
+    CONSTRAINT `fk_game_ownerships_game` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `tags` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `name` varchar(50) NOT NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_tags_user_name` (`user_id`,`name`),
+    CONSTRAINT `fk_tags_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `game_tags` (
+    `game_id` bigint unsigned,
+    `tag_id` bigint unsigned,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`game_id`,`tag_id`),
+    INDEX `idx_game_tags_tag_id` (`tag_id`),
+    CONSTRAINT `fk_game_tags_game` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_game_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `collections` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `description` varchar(500),
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_collections_user_name` (`user_id`,`name`),
+    CONSTRAINT `fk_collections_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `collection_games` (
+    `collection_id` bigint unsigned,
+    `game_id` bigint unsigned,
+    `created_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`collection_id`,`game_id`),
+    INDEX `idx_collection_games_game_id` (`game_id`),
+    CONSTRAINT `fk_collection_games_collection` FOREIGN KEY (`collection_id`) REFERENCES `collections`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_collection_games_game` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `achievements` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `game_id` bigint unsigned NOT NULL,
+    `name` varchar(200) NOT NULL,
+    `description` text,
+    `unlocked` boolean NOT NULL DEFAULT false,
+    `unlocked_at` datetime(3) NULL,
+    `created_at` datetime(3) NOT NULL,
+    `updated_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_achievement_game_name` (`game_id`,`name`),
+    CONSTRAINT `fk_achievements_game` FOREIGN KEY (`game_id`) REFERENCES `games`(`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
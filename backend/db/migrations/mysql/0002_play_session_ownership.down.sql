@@ -0,0 +1,3 @@
+ALTER TABLE `play_sessions` DROP FOREIGN KEY `fk_play_sessions_ownership`;
+ALTER TABLE `play_sessions` DROP INDEX `idx_play_sessions_ownership_id`;
+ALTER TABLE `play_sessions` DROP COLUMN `ownership_id`;
@@ -0,0 +1,7 @@
+-- Una sesión puede indicar en qué copia del juego (GameOwnership) se jugó,
+-- para repartir las horas por plataforma. Si se borra la copia la sesión
+-- queda sin copia.
+
+ALTER TABLE `play_sessions` ADD COLUMN `ownership_id` bigint unsigned NULL;
+ALTER TABLE `play_sessions` ADD INDEX `idx_play_sessions_ownership_id` (`ownership_id`);
+ALTER TABLE `play_sessions` ADD CONSTRAINT `fk_play_sessions_ownership` FOREIGN KEY (`ownership_id`) REFERENCES `game_ownerships`(`id`) ON DELETE SET NULL;
@@ -0,0 +1,202 @@
+-- Esquema inicial para PostgreSQL, equivalente a migrations/mysql. Las
+-- columnas que MySQL compara sin distinguir mayúsculas (utf8mb4_unicode_ci)
+-- son citext para que los "=", LIKE y los índices únicos se comporten igual.
+-- No hay índice FULLTEXT: la búsqueda se resuelve en el service.
+
+CREATE EXTENSION IF NOT EXISTS citext;
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial,
+    username citext NOT NULL,
+    email citext NOT NULL,
+    password varchar(255) NOT NULL,
+    first_name varchar(50),
+    last_name varchar(50),
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS games (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title citext NOT NULL,
+    platform citext NOT NULL,
+    genre citext,
+    status varchar(32),
+    progress bigint,
+    hours_played decimal(10,2) DEFAULT 0,
+    personal_note text,
+    score bigint,
+    started_at timestamptz NULL,
+    finished_at timestamptz NULL,
+    cover_url varchar(500),
+    version bigint NOT NULL DEFAULT 1,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    progress_from_achievements boolean NOT NULL DEFAULT false,
+    deleted_at timestamptz NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_games_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT score_between_0_10 CHECK (score >= 0 AND score <= 10),
+    CONSTRAINT progress_between_0_100 CHECK (progress >= 0 AND progress <= 100)
+);
+CREATE INDEX IF NOT EXISTS idx_games_user_id ON games (user_id);
+CREATE INDEX IF NOT EXISTS idx_title_platform ON games (title, platform);
+CREATE INDEX IF NOT EXISTS idx_games_genre ON games (genre);
+CREATE INDEX IF NOT EXISTS idx_games_status ON games (status);
+CREATE INDEX IF NOT EXISTS idx_games_started_at ON games (started_at);
+CREATE INDEX IF NOT EXISTS idx_games_finished_at ON games (finished_at);
+CREATE INDEX IF NOT EXISTS idx_games_deleted_at ON games (deleted_at);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id bigserial,
+    user_id bigint NOT NULL,
+    family_id varchar(64) NOT NULL,
+    token_hash char(64) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz NULL,
+    revoked_at timestamptz NULL,
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS play_sessions (
+    id bigserial,
+    game_id bigint NOT NULL,
+    started_at timestamptz NOT NULL,
+    ended_at timestamptz NULL,
+    duration_minutes bigint NOT NULL DEFAULT 0,
+    note varchar(500),
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_play_sessions_game FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_play_sessions_game_id ON play_sessions (game_id);
+CREATE INDEX IF NOT EXISTS idx_play_sessions_started_at ON play_sessions (started_at);
+
+CREATE TABLE IF NOT EXISTS metadata_caches (
+    id bigserial,
+    provider varchar(32) NOT NULL,
+    kind varchar(16) NOT NULL,
+    lookup_key varchar(200) NOT NULL,
+    payload text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metadata_cache_lookup ON metadata_caches (provider, kind, lookup_key);
+CREATE INDEX IF NOT EXISTS idx_metadata_caches_expires_at ON metadata_caches (expires_at);
+
+CREATE TABLE IF NOT EXISTS game_covers (
+    game_id bigint,
+    hash char(16) NOT NULL,
+    content_type varchar(32) NOT NULL,
+    width bigint,
+    height bigint,
+    size bigint,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (game_id),
+    CONSTRAINT fk_game_covers_game FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id bigserial,
+    user_id bigint NOT NULL,
+    title varchar(200) NOT NULL,
+    platform varchar(80),
+    target_price decimal(10,2),
+    release_date timestamptz NULL,
+    priority bigint NOT NULL DEFAULT 3,
+    note varchar(500),
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_wishlist_items_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT priority_between_1_5 CHECK (priority >= 1 AND priority <= 5)
+);
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_user_id ON wishlist_items (user_id);
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_release_date ON wishlist_items (release_date);
+
+CREATE TABLE IF NOT EXISTS game_ownerships (
+    id bigserial,
+    game_id bigint NOT NULL,
+    platform varchar(80) NOT NULL,
+    store varchar(80),
+    edition varchar(120),
+    purchased_at timestamptz NULL,
+    price_paid decimal(10,2),
+    format varchar(16),
+    hours_played decimal(10,2) DEFAULT 0,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_game_ownerships_game FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_game_ownerships_game_id ON game_ownerships (game_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id bigserial,
+    user_id bigint NOT NULL,
+    name citext NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_tags_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags (user_id, name);
+
+CREATE TABLE IF NOT EXISTS game_tags (
+    game_id bigint,
+    tag_id bigint,
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (game_id, tag_id),
+    CONSTRAINT fk_game_tags_game FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+    CONSTRAINT fk_game_tags_tag FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_game_tags_tag_id ON game_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id bigserial,
+    user_id bigint NOT NULL,
+    name citext NOT NULL,
+    description varchar(500),
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_collections_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collections_user_name ON collections (user_id, name);
+
+CREATE TABLE IF NOT EXISTS collection_games (
+    collection_id bigint,
+    game_id bigint,
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (collection_id, game_id),
+    CONSTRAINT fk_collection_games_collection FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    CONSTRAINT fk_collection_games_game FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_collection_games_game_id ON collection_games (game_id);
+
+CREATE TABLE IF NOT EXISTS achievements (
+    id bigserial,
+    game_id bigint NOT NULL,
+    name citext NOT NULL,
+    description text,
+    unlocked boolean NOT NULL DEFAULT false,
+    unlocked_at timestamptz NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_achievements_game FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_achievement_game_name ON achievements (game_id, name);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_play_sessions_ownership_id;
+ALTER TABLE play_sessions DROP COLUMN ownership_id;
@@ -0,0 +1,2 @@
+ALTER TABLE games DROP COLUMN developer;
+ALTER TABLE games DROP COLUMN release_date;
@@ -0,0 +1,5 @@
+-- Fecha de salida y desarrollador del juego; los completa el proveedor de
+-- metadatos (POST /games/:id/enrich) o el usuario a mano.
+
+ALTER TABLE games ADD COLUMN release_date timestamptz NULL;
+ALTER TABLE games ADD COLUMN developer varchar(120);
@@ -0,0 +1,7 @@
+-- Una sesión puede indicar en qué copia del juego (GameOwnership) se jugó,
+-- para repartir las horas por plataforma. Si se borra la copia la sesión
+-- queda sin copia.
+
+ALTER TABLE play_sessions ADD COLUMN ownership_id integer NULL
+    CONSTRAINT fk_play_sessions_ownership REFERENCES game_ownerships(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_play_sessions_ownership_id ON play_sessions (ownership_id);
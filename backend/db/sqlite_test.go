@@ -0,0 +1,196 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// openMigratedSQLite abre una base SQLite en memoria con todas las
+// migraciones aplicadas.
+func openMigratedSQLite(t *testing.T) (*gorm.DB, *Migrator) {
+	t.Helper()
+	conn, err := Open(Config{Driver: DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	migrator, err := NewMigrator(conn)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	return conn, migrator
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("DB_DRIVER", "Postgres")
+	t.Setenv("DB_PORT", "")
+
+	cfg, err := LoadConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, DriverPostgres, cfg.Driver)
+	assert.Equal(t, "5432", cfg.Port)
+}
+
+func TestLoadConfig_UnknownDriver(t *testing.T) {
+	t.Setenv("DB_DRIVER", "oracle")
+
+	_, err := LoadConfig()
+
+	assert.ErrorContains(t, err, `"oracle" is not supported`)
+}
+
+func TestSQLiteMigrations_UpAndDown(t *testing.T) {
+	conn, migrator := openMigratedSQLite(t)
+
+	require.NoError(t, migrator.Check())
+	assert.True(t, conn.Migrator().HasTable("games"))
+	assert.True(t, conn.Migrator().HasIndex("achievements", "idx_achievement_game_name"))
+
+	assert.True(t, conn.Migrator().HasColumn("play_sessions", "ownership_id"))
+	assert.True(t, conn.Migrator().HasColumn("games", "release_date"))
+
+	reverted, err := migrator.Down(1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.False(t, conn.Migrator().HasColumn("games", "release_date"))
+	assert.False(t, conn.Migrator().HasColumn("games", "developer"))
+
+	reverted, err = migrator.Down(1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.False(t, conn.Migrator().HasColumn("play_sessions", "ownership_id"))
+
+	reverted, err = migrator.Down(1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.False(t, conn.Migrator().HasTable("games"))
+	assert.ErrorIs(t, migrator.Check(), ErrSchemaBehind)
+}
+
+func TestSQLiteSchema_Constraints(t *testing.T) {
+	conn, _ := openMigratedSQLite(t)
+	now := time.Now()
+	exec := func(sql string, args ...interface{}) error { return conn.Exec(sql, args...).Error }
+
+	require.NoError(t, exec("INSERT INTO users (id, username, email, password, created_at, updated_at) VALUES (1, 'ana', 'ana@example.com', 'x', ?, ?)", now, now))
+	// Los únicos no distinguen mayúsculas, como en MySQL.
+	assert.Error(t, exec("INSERT INTO users (username, email, password, created_at, updated_at) VALUES ('ANA', 'otra@example.com', 'x', ?, ?)", now, now))
+
+	require.NoError(t, exec("INSERT INTO games (id, user_id, title, platform, score, progress, created_at, updated_at) VALUES (1, 1, 'Hades', 'PC', 9, 50, ?, ?)", now, now))
+	assert.ErrorContains(t, exec("INSERT INTO games (user_id, title, platform, score, created_at, updated_at) VALUES (1, 'Celeste', 'PC', 11, ?, ?)", now, now), "score_between_0_10")
+	assert.ErrorContains(t, exec("UPDATE games SET progress = 101 WHERE id = 1"), "progress_between_0_100")
+	assert.ErrorContains(t, exec("INSERT INTO wishlist_items (user_id, title, priority, created_at, updated_at) VALUES (1, 'Silksong', 0, ?, ?)", now, now), "priority_between_1_5")
+	assert.Error(t, exec("INSERT INTO games (user_id, title, platform, created_at, updated_at) VALUES (99, 'Sin dueño', 'PC', ?, ?)", now, now))
+
+	// Borrar el usuario borra sus juegos en cascada.
+	require.NoError(t, exec("DELETE FROM users WHERE id = 1"))
+	var games int64
+	require.NoError(t, conn.Table("games").Count(&games).Error)
+	assert.Zero(t, games)
+}
+
+// openLegacySQLite abre una base SQLite en memoria con las tablas users y
+// games como las creaba AutoMigrate antes de games.user_id, con los usuarios
+// usernames y dos juegos.
+func openLegacySQLite(t *testing.T, usernames ...string) (*gorm.DB, *Migrator) {
+	t.Helper()
+	conn, err := Open(Config{Driver: DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	now := time.Now()
+	require.NoError(t, conn.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, username varchar(50) NOT NULL, email varchar(100) NOT NULL,
+		password varchar(255) NOT NULL, first_name varchar(50), last_name varchar(50), created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)`).Error)
+	require.NoError(t, conn.Exec(`CREATE TABLE games (id INTEGER PRIMARY KEY, title TEXT NOT NULL, platform TEXT NOT NULL,
+		genre TEXT, status TEXT, progress INTEGER, hours_played REAL, personal_note TEXT, score INTEGER,
+		started_at DATETIME, finished_at DATETIME, cover_url TEXT, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)`).Error)
+	for _, username := range usernames {
+		require.NoError(t, conn.Exec("INSERT INTO users (username, email, password, created_at, updated_at) VALUES (?, ?, 'x', ?, ?)",
+			username, username+"@example.com", now, now).Error)
+	}
+	for _, title := range []string{"Hades", "Celeste"} {
+		require.NoError(t, conn.Exec("INSERT INTO games (title, platform, created_at, updated_at) VALUES (?, 'PC', ?, ?)", title, now, now).Error)
+	}
+	migrator, err := NewMigrator(conn)
+	require.NoError(t, err)
+	return conn, migrator
+}
+
+func TestSQLiteMigrations_RefusesLegacySchema(t *testing.T) {
+	conn, migrator := openLegacySQLite(t, "ana")
+
+	applied, err := migrator.Up()
+
+	assert.ErrorIs(t, err, ErrLegacySchema)
+	assert.ErrorContains(t, err, "games.user_id")
+	assert.ErrorContains(t, err, "games.deleted_at")
+	assert.Empty(t, applied)
+	assert.ErrorIs(t, migrator.Check(), ErrSchemaBehind)
+	assert.False(t, conn.Migrator().HasTable("tags"))
+}
+
+func TestSQLiteMigrations_AdoptsLegacySchema(t *testing.T) {
+	conn, migrator := openLegacySQLite(t, "ana")
+
+	owner, err := migrator.AdoptLegacy("")
+	require.NoError(t, err)
+	applied, err := migrator.Up()
+
+	// Assert: se registran todas las migraciones y los juegos quedan del
+	// único usuario, con los defaults de las columnas nuevas
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), owner)
+	assert.Len(t, applied, len(migrator.migrations))
+	require.NoError(t, migrator.Check())
+	var games []struct {
+		UserID  uint
+		Version uint
+	}
+	require.NoError(t, conn.Table("games").Select("user_id, version").Find(&games).Error)
+	assert.Equal(t, []struct {
+		UserID  uint
+		Version uint
+	}{{1, 1}, {1, 1}}, games)
+
+	// La foreign key nueva borra los juegos con su usuario
+	require.NoError(t, conn.Exec("DELETE FROM users WHERE id = 1").Error)
+	var count int64
+	require.NoError(t, conn.Table("games").Count(&count).Error)
+	assert.Zero(t, count)
+
+	// Una vez adoptada no queda nada que hacer
+	owner, err = migrator.AdoptLegacy("")
+	require.NoError(t, err)
+	assert.Zero(t, owner)
+}
+
+func TestSQLiteMigrations_AdoptLegacyOwner(t *testing.T) {
+	conn, migrator := openLegacySQLite(t, "ana", "beto")
+
+	// Con dos usuarios hay que elegir uno, y tiene que existir
+	_, err := migrator.AdoptLegacy("")
+	assert.ErrorIs(t, err, ErrLegacyOwner)
+	_, err = migrator.AdoptLegacy("carla")
+	assert.ErrorIs(t, err, ErrLegacyOwner)
+	assert.False(t, conn.Migrator().HasColumn("games", "user_id"))
+	assert.False(t, conn.Migrator().HasColumn("games", "version"))
+
+	owner, err := migrator.AdoptLegacy("beto")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), owner)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	var owners []uint
+	require.NoError(t, conn.Table("games").Distinct().Pluck("user_id", &owners).Error)
+	assert.Equal(t, []uint{2}, owners)
+}
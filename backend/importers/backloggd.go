@@ -0,0 +1,101 @@
+package importers
+
+import (
+	"io"
+	"strings"
+
+	"gametracker/models"
+)
+
+var backloggdColumns = map[string][]string{
+	"title":    {"game", "game name", "name", "title"},
+	"platform": {"platform", "played on", "platforms"},
+	"status":   {"status"},
+	"rating":   {"rating"},
+	"review":   {"review", "notes"},
+	"hours":    {"time played", "hours", "playtime"},
+	"started":  {"start date", "started", "date started"},
+	"finished": {"finish date", "finished", "date finished", "completion date"},
+}
+
+// Estados de Backloggd. Played es "jugado pero no terminado" y Shelved
+// "dejado para más adelante"; Retired son los juegos sin final que se
+// dejaron de jugar después de aprovecharlos.
+var backloggdStatus = map[string]string{
+	"completed": models.StatusCompleted,
+	"retired":   models.StatusCompleted,
+	"playing":   models.StatusPlaying,
+	"backlog":   models.StatusBacklog,
+	"wishlist":  models.StatusWishlist,
+	"shelved":   models.StatusPaused,
+	"played":    models.StatusPaused,
+	"abandoned": models.StatusDropped,
+}
+
+// backloggdFields son las columnas de Backloggd que, con valor, pisan un
+// campo del juego al actualizar.
+var backloggdFields = map[string]string{
+	"review":   "personalNote",
+	"status":   "status",
+	"rating":   "score",
+	"hours":    "hoursPlayed",
+	"started":  "startedAt",
+	"finished": "finishedAt",
+}
+
+// ParseBackloggd lee el CSV exportado desde Backloggd. Rating va de 0 a 5
+// estrellas (con medias) y la reseña pasa a la nota personal.
+func ParseBackloggd(r io.Reader) ([]models.ImportRecord, error) {
+	table, err := newCSVTable(r, backloggdColumns, "title", "platform")
+	if err != nil {
+		return nil, err
+	}
+	records := []models.ImportRecord{}
+	for {
+		row, line, err := table.next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rec := models.ImportRecord{Line: line}
+		in := &rec.Input
+		in.Title = row.get("title")
+		in.Platform = normalizePlatform(row.get("platform"))
+		in.PersonalNote = row.get("review")
+		if value := row.get("status"); value != "" {
+			status, ok := backloggdStatus[strings.ToLower(value)]
+			if !ok {
+				rec.Errors = append(rec.Errors, models.FieldError{Field: "status", Message: "unknown Backloggd status " + value})
+			}
+			in.Status = status
+		}
+		if in.Score, err = starsToScore(row.get("rating")); err != nil {
+			rec.Errors = append(rec.Errors, models.FieldError{Field: "score", Message: "must be between 0 and 5 stars"})
+		}
+		rec.Errors = append(rec.Errors, parseCommonColumns(row, in)...)
+		rec.Mark("title", "platform")
+		row.provided(&rec, backloggdFields)
+		markWishlist(&rec)
+		records = append(records, rec)
+	}
+}
+
+// parseCommonColumns lee las columnas de horas y fechas que comparten
+// Backloggd y HowLongToBeat.
+func parseCommonColumns(row csvRow, in *models.GameInput) []models.FieldError {
+	var errs []models.FieldError
+	var err error
+	if in.HoursPlayed, err = parseHours(row.get("hours")); err != nil {
+		errs = append(errs, models.FieldError{Field: "hoursPlayed", Message: err.Error()})
+	}
+	if in.StartedAt, err = parseDate(row.get("started")); err != nil {
+		errs = append(errs, models.FieldError{Field: "startedAt", Message: err.Error()})
+	}
+	if in.FinishedAt, err = parseDate(row.get("finished")); err != nil {
+		errs = append(errs, models.FieldError{Field: "finishedAt", Message: err.Error()})
+	}
+	return errs
+}
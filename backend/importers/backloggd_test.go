@@ -0,0 +1,46 @@
+package importers
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackloggd(t *testing.T) {
+	records := parseFixture(t, "backloggd", "backloggd.csv")
+
+	require.Len(t, records, 4)
+	assert.Equal(t, models.ImportRecord{
+		Line: 2,
+		Input: models.GameInput{
+			Title:        "Elden Ring",
+			Platform:     "PlayStation 5",
+			Status:       models.StatusCompleted,
+			Score:        10,
+			PersonalNote: `Obra maestra, "difícil"`,
+			HoursPlayed:  120.5,
+			StartedAt:    date(2022, time.February, 25),
+			FinishedAt:   date(2022, time.May, 10),
+		},
+		Fields: map[string]bool{
+			"title": true, "platform": true, "status": true, "score": true, "personalNote": true,
+			"hoursPlayed": true, "startedAt": true, "finishedAt": true,
+		},
+	}, records[0])
+
+	assert.Equal(t, models.StatusPaused, records[1].Input.Status)
+	assert.Equal(t, 7, records[1].Input.Score)
+	assert.Equal(t, 25.5, records[1].Input.HoursPlayed)
+	// Los deseos van a la lista de deseos, no a la biblioteca.
+	assert.True(t, records[2].Wishlist)
+	assert.False(t, records[1].Wishlist)
+	// Sin reseña ni fechas: un update no borra las que ya tiene el juego.
+	assert.Equal(t, map[string]bool{"title": true, "platform": true, "status": true, "score": true, "hoursPlayed": true}, records[1].Fields)
+
+	require.Len(t, records[3].Errors, 1)
+	assert.Equal(t, "status", records[3].Errors[0].Field)
+}
@@ -0,0 +1,109 @@
+package importers
+
+import (
+	"io"
+	"strings"
+
+	"gametracker/models"
+)
+
+var gogColumns = map[string][]string{
+	"title":    {"title", "name"},
+	"platform": {"platformList", "platforms", "platform", "source"},
+	"genre":    {"genres", "genre"},
+	"minutes":  {"gameMins", "minutes played"},
+	"hours":    {"hours played", "time played"},
+	"rating":   {"myRating", "rating"},
+	"tags":     {"tags"},
+}
+
+// Tags de GOG Galaxy que indican el estado del juego.
+var gogTagStatus = map[string]string{
+	"completed": models.StatusCompleted,
+	"finished":  models.StatusCompleted,
+	"beaten":    models.StatusCompleted,
+	"playing":   models.StatusPlaying,
+	"abandoned": models.StatusDropped,
+	"dropped":   models.StatusDropped,
+	"wishlist":  models.StatusWishlist,
+}
+
+// ParseGOG lee el CSV de la biblioteca de GOG Galaxy 2.0 (el que arma
+// "GOG Galaxy Exporter"). Se usa la primera plataforma de platformList y el
+// primer género; gameMins son minutos y myRating va de 0 a 5 estrellas.
+// El estado sale de los tags del usuario (Completed, Playing, Abandoned...);
+// sin tags es Backlog si no tiene horas y Paused si las tiene.
+func ParseGOG(r io.Reader) ([]models.ImportRecord, error) {
+	table, err := newCSVTable(r, gogColumns, "title")
+	if err != nil {
+		return nil, err
+	}
+	records := []models.ImportRecord{}
+	for {
+		row, line, err := table.next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rec := models.ImportRecord{Line: line}
+		in := &rec.Input
+		in.Title = row.get("title")
+		in.Platform = normalizePlatform(firstListItem(row.get("platform")))
+		if in.Platform == "" {
+			in.Platform = "PC"
+		}
+		in.Genre = firstListItem(row.get("genre"))
+
+		if minutes := row.get("minutes"); minutes != "" {
+			hours, err := parseHours(minutes)
+			if err != nil {
+				rec.Errors = append(rec.Errors, models.FieldError{Field: "hoursPlayed", Message: "must be a number of minutes"})
+			}
+			in.HoursPlayed = models.RoundHours(hours / 60)
+		} else if hours, err := parseHours(row.get("hours")); err != nil {
+			rec.Errors = append(rec.Errors, models.FieldError{Field: "hoursPlayed", Message: err.Error()})
+		} else {
+			in.HoursPlayed = hours
+		}
+		if in.Score, err = starsToScore(row.get("rating")); err != nil {
+			rec.Errors = append(rec.Errors, models.FieldError{Field: "score", Message: "must be between 0 and 5 stars"})
+		}
+
+		var tagged bool
+		in.Status, tagged = gogStatus(row.get("tags"), in.HoursPlayed)
+		rec.Mark("title", "platform")
+		row.provided(&rec, gogFields)
+		if tagged {
+			rec.Mark("status")
+		}
+		markWishlist(&rec)
+		records = append(records, rec)
+	}
+}
+
+// gogFields son las columnas de GOG que, con valor, pisan un campo del juego
+// al actualizar.
+var gogFields = map[string]string{
+	"genre":   "genre",
+	"minutes": "hoursPlayed",
+	"hours":   "hoursPlayed",
+	"rating":  "score",
+}
+
+// gogStatus devuelve el estado y si salió de un tag; sin tags el estado se
+// deduce de las horas y solo se usa al crear el juego.
+func gogStatus(tags string, hours float64) (string, bool) {
+	for _, tag := range strings.Split(strings.Trim(tags, "[]"), ",") {
+		tag = strings.ToLower(strings.Trim(strings.TrimSpace(tag), `'"`))
+		if status, ok := gogTagStatus[tag]; ok {
+			return status, true
+		}
+	}
+	if hours > 0 {
+		return models.StatusPaused, false
+	}
+	return models.StatusBacklog, false
+}
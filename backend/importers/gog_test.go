@@ -0,0 +1,43 @@
+package importers
+
+import (
+	"testing"
+
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGOG(t *testing.T) {
+	records := parseFixture(t, "gog", "gog_galaxy.csv")
+
+	require.Len(t, records, 5)
+	assert.Equal(t, models.ImportRecord{
+		Line: 2,
+		Input: models.GameInput{
+			Title:       "The Witcher 3: Wild Hunt",
+			Platform:    "PC",
+			Genre:       "Role-playing (RPG)",
+			HoursPlayed: 100.5,
+			Score:       10,
+			Status:      models.StatusCompleted,
+		},
+		Fields: map[string]bool{"title": true, "platform": true, "genre": true, "hoursPlayed": true, "score": true, "status": true},
+	}, records[0])
+
+	assert.Equal(t, 1.5, records[1].Input.HoursPlayed)
+	assert.Equal(t, 9, records[1].Input.Score)
+	assert.Equal(t, models.StatusPaused, records[1].Input.Status)
+	// Sin tags el estado es una deducción y no pisa el del juego.
+	assert.False(t, records[1].Fields["status"])
+
+	assert.Equal(t, "Xbox One", records[2].Input.Platform)
+	assert.Equal(t, models.StatusDropped, records[2].Input.Status)
+
+	require.Len(t, records[3].Errors, 1)
+	assert.Equal(t, "hoursPlayed", records[3].Errors[0].Field)
+
+	assert.True(t, records[4].Wishlist)
+	assert.False(t, records[0].Wishlist)
+}
@@ -0,0 +1,93 @@
+package importers
+
+import (
+	"io"
+	"math"
+	"strconv"
+
+	"gametracker/models"
+)
+
+var hltbColumns = map[string][]string{
+	"title":     {"title", "game"},
+	"platform":  {"platform"},
+	"playing":   {"playing"},
+	"backlog":   {"backlog"},
+	"completed": {"completed"},
+	"retired":   {"retired"},
+	"wishlist":  {"wishlist"},
+	"hours":     {"progress", "time played"},
+	"review":    {"review"},
+	"notes":     {"review notes", "notes"},
+	"started":   {"start date", "started"},
+	"finished":  {"completion date", "finish date", "completed date"},
+}
+
+// hltbFields son las columnas de HowLongToBeat que, con valor, pisan un
+// campo del juego al actualizar. El estado sale de las columnas de listas.
+var hltbFields = map[string]string{
+	"notes":    "personalNote",
+	"review":   "score",
+	"hours":    "hoursPlayed",
+	"started":  "startedAt",
+	"finished": "finishedAt",
+}
+
+// ParseHLTB lee el CSV de "Export" de la biblioteca de HowLongToBeat. Cada
+// lista (Playing, Backlog, Completed, Retired) es una columna marcada; si
+// hay varias gana la más avanzada. Progress es "hh:mm:ss" y Review va de
+// 0 a 100.
+func ParseHLTB(r io.Reader) ([]models.ImportRecord, error) {
+	table, err := newCSVTable(r, hltbColumns, "title", "platform")
+	if err != nil {
+		return nil, err
+	}
+	records := []models.ImportRecord{}
+	for {
+		row, line, err := table.next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rec := models.ImportRecord{Line: line}
+		in := &rec.Input
+		in.Title = row.get("title")
+		in.Platform = normalizePlatform(row.get("platform"))
+		in.PersonalNote = row.get("notes")
+		in.Status = hltbStatus(row)
+		if review := row.get("review"); review != "" {
+			score, err := strconv.ParseFloat(review, 64)
+			if err != nil || score < 0 || score > 100 {
+				rec.Errors = append(rec.Errors, models.FieldError{Field: "score", Message: "must be between 0 and 100"})
+			}
+			in.Score = int(math.Round(score / 10))
+		}
+		rec.Errors = append(rec.Errors, parseCommonColumns(row, in)...)
+		rec.Mark("title", "platform")
+		row.provided(&rec, hltbFields)
+		if in.Status != "" {
+			rec.Mark("status")
+		}
+		markWishlist(&rec)
+		records = append(records, rec)
+	}
+}
+
+func hltbStatus(row csvRow) string {
+	switch {
+	case row.flag("completed"):
+		return models.StatusCompleted
+	case row.flag("retired"):
+		return models.StatusDropped
+	case row.flag("playing"):
+		return models.StatusPlaying
+	case row.flag("backlog"):
+		return models.StatusBacklog
+	case row.flag("wishlist"):
+		return models.StatusWishlist
+	}
+	return ""
+}
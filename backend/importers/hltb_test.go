@@ -0,0 +1,45 @@
+package importers
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHLTB(t *testing.T) {
+	records := parseFixture(t, "hltb", "hltb.csv")
+
+	require.Len(t, records, 5)
+	assert.Equal(t, models.ImportRecord{
+		Line: 2,
+		Input: models.GameInput{
+			Title:        "Outer Wilds",
+			Platform:     "PC",
+			Status:       models.StatusCompleted,
+			Score:        10,
+			PersonalNote: "Inolvidable",
+			HoursPlayed:  21.25,
+			StartedAt:    date(2023, time.January, 3),
+			FinishedAt:   date(2023, time.January, 20),
+		},
+		Fields: map[string]bool{
+			"title": true, "platform": true, "status": true, "score": true, "personalNote": true,
+			"hoursPlayed": true, "startedAt": true, "finishedAt": true,
+		},
+	}, records[0])
+
+	assert.Equal(t, models.StatusPlaying, records[1].Input.Status)
+	assert.Equal(t, 48.0, records[1].Input.HoursPlayed)
+	assert.Equal(t, models.StatusDropped, records[2].Input.Status)
+	assert.Equal(t, 6, records[2].Input.Score)
+	assert.Equal(t, models.StatusBacklog, records[3].Input.Status)
+	assert.False(t, records[3].Wishlist)
+	assert.True(t, records[4].Wishlist)
+	for _, rec := range records {
+		assert.Empty(t, rec.Errors, rec.Input.Title)
+	}
+}
@@ -0,0 +1,264 @@
+// Package importers convierte los archivos que exportan otros servicios
+// (Steam, GOG Galaxy, Backloggd, HowLongToBeat) en filas de importación.
+// Las filas se validan y se guardan con el mismo flujo que /games/import.
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gametracker/models"
+)
+
+// ErrUnknownSource se devuelve cuando no hay importador para la fuente pedida.
+var ErrUnknownSource = errors.New("unknown import source")
+
+// Parser lee el archivo exportado por un servicio.
+type Parser func(io.Reader) ([]models.ImportRecord, error)
+
+var parsers = map[string]Parser{
+	"steam":     ParseSteam,
+	"gog":       ParseGOG,
+	"backloggd": ParseBackloggd,
+	"hltb":      ParseHLTB,
+}
+
+// Sources devuelve los nombres de fuente aceptados por Parse.
+func Sources() []string {
+	return []string{"steam", "gog", "backloggd", "hltb"}
+}
+
+// Parse lee r con el importador de source.
+func Parse(source string, r io.Reader) ([]models.ImportRecord, error) {
+	parse, ok := parsers[strings.ToLower(source)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (valid: %s)", ErrUnknownSource, source, strings.Join(Sources(), ", "))
+	}
+	return parse(r)
+}
+
+// csvTable lee un CSV con cabecera buscando cada columna por varios nombres
+// posibles, sin importar mayúsculas: cada servicio (y cada versión de su
+// export) los escribe distinto.
+type csvTable struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+// csvRow es una fila de csvTable.
+type csvRow struct {
+	fields  []string
+	columns map[string]int
+}
+
+// newCSVTable lee la cabecera. aliases va del nombre interno de la columna a
+// los nombres que puede tener en el archivo; las columnas de required tienen
+// que estar.
+func newCSVTable(r io.Reader, aliases map[string][]string, required ...string) (*csvTable, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty CSV")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	t := &csvTable{r: cr, columns: map[string]int{}}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		for column, names := range aliases {
+			if _, found := t.columns[column]; found {
+				continue
+			}
+			for _, alias := range names {
+				if strings.EqualFold(name, alias) {
+					t.columns[column] = i
+				}
+			}
+		}
+	}
+	for _, column := range required {
+		if _, ok := t.columns[column]; !ok {
+			return nil, fmt.Errorf("CSV must have a %q column", aliases[column][0])
+		}
+	}
+	return t, nil
+}
+
+// next devuelve la siguiente fila y su línea en el archivo, o io.EOF.
+func (t *csvTable) next() (csvRow, int, error) {
+	fields, err := t.r.Read()
+	if err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("invalid CSV: %w", err)
+		}
+		return csvRow{}, 0, err
+	}
+	line, _ := t.r.FieldPos(0)
+	return csvRow{fields: fields, columns: t.columns}, line, nil
+}
+
+// get devuelve la celda de column sin espacios, o "" si la fila no la tiene.
+func (r csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.fields) {
+		return ""
+	}
+	return strings.TrimSpace(r.fields[i])
+}
+
+// markWishlist marca como deseo la fila cuyo estado en el servicio es
+// Wishlist: el usuario no tiene el juego, así que no va a la biblioteca.
+func markWishlist(rec *models.ImportRecord) {
+	rec.Wishlist = rec.Input.Status == models.StatusWishlist
+}
+
+// provided registra en rec los campos de la fila que tienen valor: un
+// import que actualiza un juego existente solo pisa esos. fields va de
+// columna del export a campo de GameInput.
+func (r csvRow) provided(rec *models.ImportRecord, fields map[string]string) {
+	for column, field := range fields {
+		if r.get(column) != "" && r.get(column) != "--" {
+			rec.Mark(field)
+		}
+	}
+}
+
+// flag interpreta las columnas marcadas con una X, "1", "yes" o "true".
+func (r csvRow) flag(column string) bool {
+	switch strings.ToLower(r.get(column)) {
+	case "", "0", "no", "false", "n":
+		return false
+	}
+	return true
+}
+
+// parseHours acepta horas decimales ("12.5"), duraciones "hh:mm[:ss]" y
+// "12h 30m".
+func parseHours(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "--" {
+		return 0, nil
+	}
+	if hours, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64); err == nil {
+		return models.RoundHours(hours), nil
+	}
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		var total float64
+		units := []float64{1, 1.0 / 60, 1.0 / 3600}
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			total += float64(n) * units[i]
+		}
+		return models.RoundHours(total), nil
+	}
+	d, err := time.ParseDuration(strings.ReplaceAll(strings.ToLower(value), " ", ""))
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return models.RoundHours(d.Hours()), nil
+}
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "Jan 2, 2006", "January 2, 2006"}
+
+// parseDate acepta las fechas con o sin hora que usan los exports.
+func parseDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "--" {
+		return nil, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q", value)
+}
+
+// starsToScore pasa una puntuación de 0 a 5 estrellas (con medias) a 0..10.
+func starsToScore(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	stars, err := strconv.ParseFloat(value, 64)
+	if err != nil || stars < 0 || stars > 5 {
+		return 0, fmt.Errorf("invalid rating %q", value)
+	}
+	return int(math.Round(stars * 2)), nil
+}
+
+// platformNames traduce los nombres de tienda y consola que usan los exports
+// a los que se usan en la biblioteca. Las tiendas de PC se agrupan en "PC".
+var platformNames = map[string]string{
+	"pc":                "PC",
+	"windows":           "PC",
+	"steam":             "PC",
+	"gog":               "PC",
+	"epic":              "PC",
+	"epic games store":  "PC",
+	"origin":            "PC",
+	"ea app":            "PC",
+	"uplay":             "PC",
+	"ubisoft connect":   "PC",
+	"battlenet":         "PC",
+	"battle.net":        "PC",
+	"humble":            "PC",
+	"itch":              "PC",
+	"itch.io":           "PC",
+	"psn":               "PlayStation",
+	"ps3":               "PlayStation 3",
+	"playstation 3":     "PlayStation 3",
+	"ps4":               "PlayStation 4",
+	"playstation 4":     "PlayStation 4",
+	"ps5":               "PlayStation 5",
+	"playstation 5":     "PlayStation 5",
+	"xbox":              "Xbox",
+	"xboxone":           "Xbox One",
+	"xbox one":          "Xbox One",
+	"xbox series x|s":   "Xbox Series X|S",
+	"xbox series x/s":   "Xbox Series X|S",
+	"switch":            "Nintendo Switch",
+	"nintendo switch":   "Nintendo Switch",
+	"nintendo":          "Nintendo Switch",
+	"nintendo 3ds":      "Nintendo 3DS",
+	"3ds":               "Nintendo 3DS",
+	"mobile":            "Mobile",
+	"android":           "Mobile",
+	"ios":               "Mobile",
+	"ios (iphone/ipad)": "Mobile",
+}
+
+// normalizePlatform devuelve el nombre de plataforma de la biblioteca; los
+// desconocidos quedan como vienen.
+func normalizePlatform(value string) string {
+	value = strings.TrimSpace(value)
+	if name, ok := platformNames[strings.ToLower(value)]; ok {
+		return name
+	}
+	return value
+}
+
+// firstListItem toma el primer elemento de listas como "GOG, Steam" o
+// "['gog', 'steam']".
+func firstListItem(value string) string {
+	value = strings.Trim(strings.TrimSpace(value), "[]")
+	first := strings.SplitN(value, ",", 2)[0]
+	return strings.Trim(strings.TrimSpace(first), `'"`)
+}
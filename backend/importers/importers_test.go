@@ -0,0 +1,86 @@
+package importers
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseFixture lee testdata/name con el importador de source.
+func parseFixture(t *testing.T, source, name string) []models.ImportRecord {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	records, err := Parse(source, f)
+	require.NoError(t, err)
+	return records
+}
+
+func fixNow(t *testing.T, fixed time.Time) {
+	t.Helper()
+	prev := clock.Now
+	clock.Now = func() time.Time { return fixed }
+	t.Cleanup(func() { clock.Now = prev })
+}
+
+func date(year int, month time.Month, day int) *time.Time {
+	d := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	return &d
+}
+
+func TestParse_UnknownSource(t *testing.T) {
+	_, err := Parse("epic", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrUnknownSource)
+}
+
+func TestParse_MissingRequiredColumn(t *testing.T) {
+	_, err := Parse("backloggd", strings.NewReader("Game Name,Status\nElden Ring,Completed\n"))
+	assert.EqualError(t, err, `CSV must have a "platform" column`)
+}
+
+func TestParseHours(t *testing.T) {
+	cases := map[string]float64{
+		"":         0,
+		"--":       0,
+		"12.5":     12.5,
+		"12,5":     12.5,
+		"21:15:00": 21.25,
+		"1:20":     1.33,
+		"25h 30m":  25.5,
+		"45m":      0.75,
+	}
+	for value, want := range cases {
+		got, err := parseHours(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"mucho", "1:2:3:4", "-1h"} {
+		_, err := parseHours(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	for _, value := range []string{"2024-05-01", "2024-05-01 00:00:00", "May 1, 2024"} {
+		got, err := parseDate(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, date(2024, time.May, 1), got, value)
+	}
+	_, err := parseDate("01/05/2024")
+	assert.Error(t, err)
+}
+
+func TestNormalizePlatform(t *testing.T) {
+	assert.Equal(t, "PC", normalizePlatform(firstListItem("['gog', 'steam']")))
+	assert.Equal(t, "Xbox One", normalizePlatform("xboxone"))
+	assert.Equal(t, "PlayStation 5", normalizePlatform("PS5"))
+	assert.Equal(t, "Sega Saturn", normalizePlatform(" Sega Saturn "))
+}
@@ -0,0 +1,94 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/models"
+)
+
+// Juegos de Steam jugados hace menos que esto se importan como Playing.
+const steamRecentlyPlayed = 30 * 24 * time.Hour
+
+// steamGame es un juego de IPlayerService/GetOwnedGames. playtime_forever
+// está en minutos y rtime_last_played es un timestamp Unix (0 si nunca).
+type steamGame struct {
+	AppID           int    `json:"appid"`
+	Name            string `json:"name"`
+	PlaytimeForever int    `json:"playtime_forever"`
+	LastPlayed      int64  `json:"rtime_last_played"`
+}
+
+// ParseSteam lee la respuesta JSON de GetOwnedGames (con
+// include_appinfo=1). Acepta la respuesta completa ({"response":{"games":
+// [...]}}), solo {"games":[...]} o directamente el array.
+//
+// Todos los juegos quedan en PC. Sin horas son Backlog; jugados en los
+// últimos 30 días, Playing; el resto Paused: Steam no sabe si se terminaron.
+// Al actualizar un juego existente solo se pisan las horas.
+func ParseSteam(r io.Reader) ([]models.ImportRecord, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	games, err := steamGames(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.ImportRecord, 0, len(games))
+	for i, g := range games {
+		// El estado es una deducción, no un dato de Steam: solo se usa al
+		// crear el juego.
+		rec := models.ImportRecord{Line: i + 1}
+		rec.Mark("title", "platform", "hoursPlayed")
+		rec.Input.Title = g.Name
+		rec.Input.Platform = "PC"
+		rec.Input.HoursPlayed = models.RoundHours(float64(g.PlaytimeForever) / 60)
+		rec.Input.Status = steamStatus(g)
+		if g.Name == "" && g.AppID != 0 {
+			// Sin include_appinfo la respuesta no trae nombres.
+			rec.Errors = []models.FieldError{{Field: "title", Message: fmt.Sprintf("app %d has no name (export with include_appinfo=1)", g.AppID)}}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func steamGames(raw json.RawMessage) ([]steamGame, error) {
+	var games []steamGame
+	if err := json.Unmarshal(raw, &games); err == nil {
+		return games, nil
+	}
+	var wrapper struct {
+		Response *struct {
+			Games []steamGame `json:"games"`
+		} `json:"response"`
+		Games []steamGame `json:"games"`
+	}
+	if err := json.Unmarshal(raw, &wrapper); err != nil {
+		return nil, errors.New("JSON must be a Steam GetOwnedGames response")
+	}
+	if wrapper.Response != nil {
+		return wrapper.Response.Games, nil
+	}
+	if wrapper.Games == nil {
+		return nil, errors.New("JSON must be a Steam GetOwnedGames response")
+	}
+	return wrapper.Games, nil
+}
+
+func steamStatus(g steamGame) string {
+	switch {
+	case g.PlaytimeForever == 0:
+		return models.StatusBacklog
+	case g.LastPlayed > 0 && clock.Now().Sub(time.Unix(g.LastPlayed, 0)) < steamRecentlyPlayed:
+		return models.StatusPlaying
+	default:
+		return models.StatusPaused
+	}
+}
@@ -0,0 +1,43 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSteam(t *testing.T) {
+	fixNow(t, time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC))
+
+	records := parseFixture(t, "steam", "steam_owned_games.json")
+
+	require.Len(t, records, 4)
+	assert.Equal(t, models.ImportRecord{
+		Line:   1,
+		Input:  models.GameInput{Title: "Hades", Platform: "PC", HoursPlayed: 45.17, Status: models.StatusPlaying},
+		Fields: map[string]bool{"title": true, "platform": true, "hoursPlayed": true},
+	}, records[0])
+	assert.Equal(t, models.StatusPaused, records[1].Input.Status)
+	assert.Equal(t, 12.42, records[1].Input.HoursPlayed)
+	assert.Equal(t, models.StatusBacklog, records[2].Input.Status)
+	require.Len(t, records[3].Errors, 1)
+	assert.Equal(t, "title", records[3].Errors[0].Field)
+}
+
+func TestParseSteam_GamesArray(t *testing.T) {
+	records, err := ParseSteam(strings.NewReader(`[{"appid": 1, "name": "Portal", "playtime_forever": 0}]`))
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "Portal", records[0].Input.Title)
+}
+
+func TestParseSteam_NotSteamJSON(t *testing.T) {
+	_, err := ParseSteam(strings.NewReader(`{"items": []}`))
+	assert.Error(t, err)
+}
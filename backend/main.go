@@ -1,17 +1,30 @@
 package main
 
 import (
+	"context"
+	"gametracker/controller"
 	"gametracker/db"
 	"gametracker/routes"
+	"gametracker/service"
+	"gametracker/storage"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// `gametracker migrate ...` administra el esquema y termina sin levantar
+	// el servidor.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:], os.Stdout))
+	}
+
 	// Set Gin mode based on environment
 	ginMode := os.Getenv("GIN_MODE")
 	if ginMode == "" {
@@ -26,6 +39,16 @@ func main() {
 	}
 	log.Printf("Starting GameTracker in %s mode with log level: %s", ginMode, logLevel)
 
+	// ctx se cancela con SIGINT/SIGTERM y apaga el servidor y los procesos
+	// de fondo.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	timeout, err := shutdownTimeout()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Configure CORS
 	allowedOrigins := []string{
 		"http://localhost",
@@ -53,8 +76,8 @@ func main() {
 	// Configure CORS with flexible origin handling
 	corsConfig := cors.Config{
 		AllowMethods:  []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "HEAD", "PATCH"},
-		AllowHeaders:  []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
-		ExposeHeaders: []string{"Content-Length", "Content-Type"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "Access-Control-Request-Method", "Access-Control-Request-Headers", "If-Match"},
+		ExposeHeaders: []string{"Content-Length", "Content-Type", "ETag"},
 		MaxAge:        12 * 3600, // 12 hours
 	}
 
@@ -73,9 +96,78 @@ func main() {
 
 	r.Use(cors.New(corsConfig))
 
-	db.ConnectDB()
-	routes.SetupGameRoutes(r)
-	routes.SetupAuthRoutes(r)
+	// La base se abre sin esperarla: el servidor arranca igual y /readyz y
+	// las rutas de la API responden 503 hasta que db.Watch logra conectarse,
+	// y otra vez cada vez que se pierde la conexión.
+	dbConfig, err := db.LoadConfig()
+	if err != nil {
+		log.Fatal("Configuración de base de datos inválida: ", err)
+	}
+	if db.DB, err = db.Open(dbConfig); err != nil {
+		log.Fatal("No se pudo abrir la base de datos: ", err)
+	}
+	migrator, err := db.NewMigrator(db.DB)
+	if err != nil {
+		log.Fatal("No se pudieron cargar las migraciones: ", err)
+	}
+	go db.Watch(ctx, db.DB, dbConfig, func() {
+		// Con migraciones pendientes el servidor sigue arriba pero la API
+		// responde 503 (ver HealthController.RequireReady) hasta que se
+		// ejecute `migrate up`.
+		if err := migrator.Check(); err != nil {
+			log.Printf("El esquema de la base no está al día (ejecutar `migrate up`): %v", err)
+		}
+	})
+
+	trashConfig, err := service.LoadTrashConfig()
+	if err != nil {
+		log.Fatal("Configuración de papelera inválida: ", err)
+	}
+
+	metadataConfig, err := service.LoadMetadataConfig()
+	if err != nil {
+		log.Fatal("Configuración de metadatos inválida: ", err)
+	}
+
+	coverConfig, err := service.LoadCoverConfig()
+	if err != nil {
+		log.Fatal("Configuración de portadas inválida: ", err)
+	}
+	coverStorage, err := storage.NewLocal(coverConfig.Dir)
+	if err != nil {
+		log.Fatal("No se pudo preparar el directorio de portadas: ", err)
+	}
+
+	// Dependencias: repositorios y services sobre la conexión, y los
+	// controllers que los usan.
+	conn := db.DB
+	gameService := service.NewGameService(service.NewGameRepository(conn))
+	trashService := service.NewTrashService(conn, coverStorage)
+	trashService.StartTrashPurger(ctx, trashConfig)
+
+	authController := controller.NewAuthController(service.NewAuthService(service.NewUserRepository(conn), conn))
+	healthController := controller.NewHealthController(service.NewHealthService(conn))
+	controllers := routes.Controllers{
+		Auth:         authController,
+		Health:       healthController,
+		Games:        controller.NewGameController(gameService),
+		Trash:        controller.NewTrashController(trashService),
+		Search:       controller.NewSearchController(service.NewSearchService(conn)),
+		Stats:        controller.NewStatsController(service.NewStatsService(conn)),
+		Transfer:     controller.NewTransferController(service.NewTransferService(conn)),
+		Metadata:     controller.NewMetadataController(service.NewMetadataService(conn, service.NewMetadataProvider(metadataConfig), metadataConfig.CacheTTL), gameService),
+		Covers:       controller.NewCoverController(service.NewCoverService(conn, coverStorage, coverConfig.MaxBytes)),
+		Ownerships:   controller.NewOwnershipController(service.NewOwnershipService(conn)),
+		Achievements: controller.NewAchievementController(service.NewAchievementService(conn)),
+		Tags:         controller.NewTagController(service.NewTagService(conn)),
+		Collections:  controller.NewCollectionController(service.NewCollectionService(conn), gameService),
+		Sessions:     controller.NewSessionController(service.NewSessionService(conn)),
+		Wishlist:     controller.NewWishlistController(service.NewWishlistService(conn)),
+	}
+
+	routes.SetupHealthRoutes(r, healthController)
+	routes.SetupGameRoutes(r, controllers)
+	routes.SetupAuthRoutes(r, authController)
 
 	// Get port from environment
 	port := os.Getenv("API_PORT")
@@ -83,6 +175,14 @@ func main() {
 		port = "8080" // default
 	}
 
+	srv := &http.Server{Addr: ":" + port, Handler: r}
 	log.Printf("Server starting on port %s", port)
-	r.Run(":" + port)
+	serveErr := serve(ctx, srv, timeout)
+	if err := db.Close(); err != nil {
+		log.Printf("Error cerrando la base de datos: %v", err)
+	}
+	if serveErr != nil {
+		log.Fatal(serveErr)
+	}
+	log.Println("Servidor detenido")
 }
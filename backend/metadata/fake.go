@@ -0,0 +1,59 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"gametracker/models"
+)
+
+// FakeProvider es un proveedor en memoria para tests. Cuenta las llamadas
+// para poder verificar la caché; si Err no es nil todas las llamadas fallan.
+type FakeProvider struct {
+	Err error
+
+	mu          sync.Mutex
+	games       []models.GameMetadata
+	SearchCalls int
+	GetCalls    int
+}
+
+// NewFakeProvider arma un FakeProvider que conoce games.
+func NewFakeProvider(games ...models.GameMetadata) *FakeProvider {
+	return &FakeProvider{games: games}
+}
+
+func (p *FakeProvider) Name() string { return "fake" }
+
+// Search devuelve los juegos cuyo título contiene title, sin distinguir mayúsculas.
+func (p *FakeProvider) Search(_ context.Context, title string) ([]models.GameMetadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.SearchCalls++
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	results := []models.GameMetadata{}
+	for _, g := range p.games {
+		if strings.Contains(strings.ToLower(g.Title), strings.ToLower(title)) {
+			results = append(results, g)
+		}
+	}
+	return results, nil
+}
+
+func (p *FakeProvider) Get(_ context.Context, externalID string) (models.GameMetadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.GetCalls++
+	if p.Err != nil {
+		return models.GameMetadata{}, p.Err
+	}
+	for _, g := range p.games {
+		if g.ExternalID == externalID {
+			return g, nil
+		}
+	}
+	return models.GameMetadata{}, ErrNotFound
+}
@@ -0,0 +1,128 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gametracker/models"
+)
+
+// DefaultRAWGURL es la API pública de RAWG.
+const DefaultRAWGURL = "https://api.rawg.io/api"
+
+// Resultados que se piden por búsqueda.
+const searchPageSize = 10
+
+// HTTPProvider habla con una API estilo RAWG:
+//
+//	GET {BaseURL}/games?search=<título>&key=<APIKey>
+//	GET {BaseURL}/games/<id>?key=<APIKey>
+type HTTPProvider struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+// NewHTTPProvider arma el proveedor con un cliente con timeout.
+func NewHTTPProvider(baseURL, apiKey string, timeout time.Duration) *HTTPProvider {
+	if baseURL == "" {
+		baseURL = DefaultRAWGURL
+	}
+	return &HTTPProvider{
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type rawgName struct {
+	Name string `json:"name"`
+}
+
+type rawgGame struct {
+	ID              int        `json:"id"`
+	Name            string     `json:"name"`
+	Released        string     `json:"released"`
+	BackgroundImage string     `json:"background_image"`
+	Genres          []rawgName `json:"genres"`
+	Developers      []rawgName `json:"developers"`
+}
+
+func (p *HTTPProvider) Name() string { return "rawg" }
+
+func (p *HTTPProvider) Search(ctx context.Context, title string) ([]models.GameMetadata, error) {
+	query := url.Values{"search": {title}, "page_size": {strconv.Itoa(searchPageSize)}}
+	var page struct {
+		Results []rawgGame `json:"results"`
+	}
+	if err := p.get(ctx, "/games", query, &page); err != nil {
+		return nil, err
+	}
+	results := make([]models.GameMetadata, 0, len(page.Results))
+	for _, g := range page.Results {
+		results = append(results, g.metadata())
+	}
+	return results, nil
+}
+
+func (p *HTTPProvider) Get(ctx context.Context, externalID string) (models.GameMetadata, error) {
+	var g rawgGame
+	if err := p.get(ctx, "/games/"+url.PathEscape(externalID), url.Values{}, &g); err != nil {
+		return models.GameMetadata{}, err
+	}
+	return g.metadata(), nil
+}
+
+func (p *HTTPProvider) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if p.APIKey != "" {
+		query.Set("key", p.APIKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("metadata request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		// El cuerpo de error puede incluir la URL con la key: no se muestra.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("metadata request: unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("metadata response: %w", err)
+	}
+	return nil
+}
+
+func (g rawgGame) metadata() models.GameMetadata {
+	meta := models.GameMetadata{
+		ExternalID: strconv.Itoa(g.ID),
+		Title:      g.Name,
+		Genres:     make([]string, 0, len(g.Genres)),
+		CoverURL:   g.BackgroundImage,
+	}
+	for _, genre := range g.Genres {
+		meta.Genres = append(meta.Genres, genre.Name)
+	}
+	if len(g.Developers) > 0 {
+		meta.Developer = g.Developers[0].Name
+	}
+	if released, err := time.Parse("2006-01-02", g.Released); err == nil {
+		meta.ReleaseDate = &released
+	}
+	return meta
+}
@@ -0,0 +1,74 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rawgServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("key"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/games":
+			assert.Equal(t, "hades", r.URL.Query().Get("search"))
+			_, _ = w.Write([]byte(`{"count": 1, "results": [
+				{"id": 274755, "name": "Hades", "released": "2020-09-17",
+				 "background_image": "https://media.example.com/hades.jpg",
+				 "genres": [{"id": 4, "name": "Action"}, {"id": 5, "name": "RPG"}]}
+			]}`))
+		case "/games/274755":
+			_, _ = w.Write([]byte(`{"id": 274755, "name": "Hades", "released": "2020-09-17",
+				"background_image": "https://media.example.com/hades.jpg",
+				"genres": [{"name": "Action"}], "developers": [{"name": "Supergiant Games"}]}`))
+		case "/games/500":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"detail": "Not found."}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHTTPProvider_Search(t *testing.T) {
+	p := NewHTTPProvider(rawgServer(t).URL, "secret", time.Second)
+
+	results, err := p.Search(context.Background(), "hades")
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "274755", results[0].ExternalID)
+	assert.Equal(t, []string{"Action", "RPG"}, results[0].Genres)
+	assert.Equal(t, "https://media.example.com/hades.jpg", results[0].CoverURL)
+	require.NotNil(t, results[0].ReleaseDate)
+	assert.Equal(t, "2020-09-17", results[0].ReleaseDate.Format("2006-01-02"))
+}
+
+func TestHTTPProvider_Get(t *testing.T) {
+	p := NewHTTPProvider(rawgServer(t).URL, "secret", time.Second)
+
+	game, err := p.Get(context.Background(), "274755")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Hades", game.Title)
+	assert.Equal(t, "Supergiant Games", game.Developer)
+}
+
+func TestHTTPProvider_Errors(t *testing.T) {
+	p := NewHTTPProvider(rawgServer(t).URL, "secret", time.Second)
+
+	_, err := p.Get(context.Background(), "1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = p.Get(context.Background(), "500")
+	assert.EqualError(t, err, "metadata request: unexpected status 500")
+}
@@ -0,0 +1,23 @@
+// Package metadata busca datos de juegos (géneros, fecha de salida,
+// desarrollador, portada) en servicios externos.
+package metadata
+
+import (
+	"context"
+	"errors"
+
+	"gametracker/models"
+)
+
+// ErrNotFound se devuelve cuando el proveedor no conoce el ID pedido.
+var ErrNotFound = errors.New("game not found in metadata provider")
+
+// Provider es un servicio de metadatos. Search puede devolver datos
+// incompletos (sin desarrollador, por ejemplo); Get devuelve la ficha
+// completa o ErrNotFound.
+type Provider interface {
+	// Name identifica al proveedor en la caché.
+	Name() string
+	Search(ctx context.Context, title string) ([]models.GameMetadata, error)
+	Get(ctx context.Context, externalID string) (models.GameMetadata, error)
+}
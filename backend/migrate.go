@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"gametracker/db"
+)
+
+const migrateUsage = "usage: gametracker migrate up [--adopt-legacy[=<username>]]|down [steps] [--force]|status"
+
+// runMigrate implementa el subcomando `migrate`. Devuelve el código de
+// salida del proceso.
+func runMigrate(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(out, migrateUsage)
+		return 2
+	}
+	steps := 1
+	force := false
+	adopt := false
+	owner := ""
+	if args[0] == "up" {
+		for _, arg := range args[1:] {
+			name, value, _ := strings.Cut(arg, "=")
+			if name != "--adopt-legacy" {
+				fmt.Fprintln(out, migrateUsage)
+				return 2
+			}
+			adopt, owner = true, value
+		}
+	}
+	if args[0] == "down" {
+		for _, arg := range args[1:] {
+			if arg == "--force" {
+				force = true
+				continue
+			}
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				fmt.Fprintln(out, "steps must be a positive integer")
+				return 2
+			}
+			steps = n
+		}
+	}
+	if args[0] != "up" && args[0] != "down" && args[0] != "status" {
+		fmt.Fprintln(out, migrateUsage)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := db.ConnectDB(ctx); err != nil {
+		fmt.Fprintln(out, "error connecting to the database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := db.NewMigrator(db.DB)
+	if err != nil {
+		fmt.Fprintln(out, "error loading migrations:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		if adopt {
+			ownerID, err := migrator.AdoptLegacy(owner)
+			if err != nil {
+				fmt.Fprintln(out, "error adopting the legacy schema:", err)
+				return 1
+			}
+			if ownerID != 0 {
+				fmt.Fprintf(out, "adopted legacy schema, existing games assigned to user %d\n", ownerID)
+			}
+		}
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+			if errors.Is(err, db.ErrLegacySchema) {
+				fmt.Fprintln(out, "re-run with --adopt-legacy to add the missing columns and assign the existing games to the only user, or --adopt-legacy=<username> to pick one")
+			}
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "schema is up to date")
+		}
+	case "down":
+		if !force {
+			if initial, err := revertsInitial(migrator, steps); err != nil {
+				fmt.Fprintln(out, "error:", err)
+				return 1
+			} else if initial != nil {
+				fmt.Fprintf(out, "reverting %04d_%s drops every table and its data; back up the database and re-run with --force\n", initial.Version, initial.Name)
+				return 1
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		for _, m := range reverted {
+			fmt.Fprintf(out, "reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+			return 1
+		}
+		if len(reverted) == 0 {
+			fmt.Fprintln(out, "no migrations to revert")
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+	}
+	return 0
+}
+
+// revertsInitial devuelve la primera migración si está entre las últimas
+// steps aplicadas, es decir, si `migrate down steps` borraría el esquema.
+func revertsInitial(migrator *db.Migrator, steps int) (*db.Migration, error) {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return nil, err
+	}
+	var applied []db.Migration
+	for _, s := range statuses {
+		if s.AppliedAt != nil {
+			applied = append(applied, s.Migration)
+		}
+	}
+	if len(applied) == 0 || len(statuses) == 0 || applied[0].Version != statuses[0].Version {
+		return nil, nil
+	}
+	if steps >= len(applied) {
+		return &applied[0], nil
+	}
+	return nil, nil
+}
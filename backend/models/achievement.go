@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Achievement es un logro o un ítem de la checklist de completado de un
+// juego. El nombre es único dentro del juego: la importación lo usa para
+// actualizar los que ya existen.
+type Achievement struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	GameID      uint       `json:"gameId"      gorm:"not null;uniqueIndex:idx_achievement_game_name,priority:1"`
+	Game        *Game      `json:"-"           gorm:"constraint:OnDelete:CASCADE"`
+	Name        string     `json:"name"        gorm:"type:varchar(200);not null;uniqueIndex:idx_achievement_game_name,priority:2"`
+	Description string     `json:"description" gorm:"type:text"`
+	Unlocked    bool       `json:"unlocked"    gorm:"not null;default:false"`
+	UnlockedAt  *time.Time `json:"unlockedAt"`
+	CreatedAt   time.Time  `json:"createdAt"   gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updatedAt"   gorm:"not null"`
+}
+
+// AchievementInput es el body de alta de un logro y cada elemento de la
+// importación. Si viene desbloqueado sin fecha, el service pone la actual.
+type AchievementInput struct {
+	Name        string     `json:"name"        binding:"required,max=200"`
+	Description string     `json:"description" binding:"max=2000"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlockedAt"`
+}
+
+// Validate rechaza un nombre con solo espacios: Apply lo recorta y quedaría
+// vacío.
+func (in AchievementInput) Validate() []FieldError {
+	if strings.TrimSpace(in.Name) == "" {
+		return []FieldError{{Field: "name", Message: "is required"}}
+	}
+	return nil
+}
+
+// Apply vuelca el input sobre a. Un logro bloqueado no tiene fecha.
+func (in AchievementInput) Apply(a *Achievement) {
+	a.Name = strings.TrimSpace(in.Name)
+	a.Description = in.Description
+	a.Unlocked = in.Unlocked
+	a.UnlockedAt = nil
+	if in.Unlocked {
+		a.UnlockedAt = in.UnlockedAt
+	}
+}
+
+// AchievementImportInput importa hasta 1000 logros en bloque. Los que
+// coinciden por nombre con uno existente lo actualizan; con Replace además
+// se borran los que no vienen en la lista.
+type AchievementImportInput struct {
+	Achievements []AchievementInput `json:"achievements" binding:"required,max=1000,dive"`
+	Replace      bool               `json:"replace"`
+}
+
+// Validate valida cada logro y rechaza nombres repetidos (sin distinguir
+// mayúsculas) dentro de la misma importación.
+func (in AchievementImportInput) Validate() []FieldError {
+	seen := make(map[string]bool, len(in.Achievements))
+	for _, a := range in.Achievements {
+		if errs := a.Validate(); len(errs) > 0 {
+			return errs
+		}
+		key := strings.ToLower(strings.TrimSpace(a.Name))
+		if seen[key] {
+			return []FieldError{{Field: "achievements", Message: "duplicate name " + strings.TrimSpace(a.Name)}}
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// CoverSize es un tamaño de miniatura de portada, por ancho en píxeles.
+type CoverSize struct {
+	Name  string
+	Width int
+}
+
+// CoverOriginal es el nombre del archivo subido tal cual.
+const CoverOriginal = "original"
+
+// CoverSizes son las miniaturas que se generan al subir una portada.
+var CoverSizes = []CoverSize{
+	{Name: "small", Width: 160},
+	{Name: "medium", Width: 320},
+	{Name: "large", Width: 640},
+}
+
+// uploadedCoverURL reconoce las URLs que devuelve CoverPath, con o sin query.
+var uploadedCoverURL = regexp.MustCompile(`^/games/[0-9]+/cover(\?.*)?$`)
+
+// CoverPath es la ruta en la API de la portada subida del juego.
+func CoverPath(gameID uint) string {
+	return fmt.Sprintf("/games/%d/cover", gameID)
+}
+
+// IsUploadedCoverURL indica si url apunta a una portada subida y no a una
+// imagen externa. Es lo que queda en Game.CoverURL después de subirla.
+func IsUploadedCoverURL(url string) bool {
+	return uploadedCoverURL.MatchString(url)
+}
+
+// IsValidCoverSize indica si size es CoverOriginal o una de CoverSizes.
+func IsValidCoverSize(size string) bool {
+	if size == CoverOriginal {
+		return true
+	}
+	for _, s := range CoverSizes {
+		if s.Name == size {
+			return true
+		}
+	}
+	return false
+}
+
+// GameCover es la portada subida de un juego. Hash identifica el contenido:
+// cambia con cada subida y forma parte de las claves en el storage, así las
+// URLs con ?v=<hash> se pueden cachear para siempre.
+type GameCover struct {
+	GameID      uint      `json:"gameId" gorm:"primaryKey;autoIncrement:false"`
+	Game        *Game     `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Hash        string    `json:"hash" gorm:"type:char(16);not null"`
+	ContentType string    `json:"contentType" gorm:"type:varchar(32);not null"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"not null"`
+	UpdatedAt   time.Time `json:"updatedAt" gorm:"not null"`
+	// URLs va de cada tamaño (y "original") a su ruta en la API.
+	URLs map[string]string `json:"urls" gorm:"-"`
+}
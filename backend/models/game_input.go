@@ -0,0 +1,149 @@
+package models
+
+import (
+	"net/url"
+	"time"
+)
+
+// GameInput es el body de alta y modificación de juegos. Los campos que el
+// cliente no puede tocar (ID, UserID, fechas de auditoría) no están.
+type GameInput struct {
+	Title        string     `json:"title"        binding:"required,max=200"`
+	Platform     string     `json:"platform"     binding:"required,max=80"`
+	Genre        string     `json:"genre"        binding:"max=80"`
+	Status       string     `json:"status"       binding:"max=32"`
+	Progress     int        `json:"progress"     binding:"min=0,max=100"`
+	HoursPlayed  float64    `json:"hoursPlayed"  binding:"min=0"`
+	PersonalNote string     `json:"personalNote"`
+	Score        int        `json:"score"        binding:"min=0,max=10"`
+	StartedAt    *time.Time `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt"`
+	CoverURL     string     `json:"coverURL"     binding:"max=500"`
+	ReleaseDate  *time.Time `json:"releaseDate"`
+	Developer    string     `json:"developer"    binding:"max=120"`
+	// Version es opcional: si viene, tiene que coincidir con la actual
+	// (alternativa a If-Match para clientes que no manejan headers).
+	Version *uint `json:"version,omitempty"`
+	// Con ProgressFromAchievements el service ignora Progress y lo calcula
+	// con el porcentaje de logros desbloqueados.
+	ProgressFromAchievements bool `json:"progressFromAchievements"`
+}
+
+// FieldError describe un campo inválido del body, con su nombre JSON.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// NewGameInput copia los campos editables de g. Se usa en los updates para
+// que lo que no venga en el body conserve su valor actual.
+func NewGameInput(g Game) GameInput {
+	return GameInput{
+		Title:        g.Title,
+		Platform:     g.Platform,
+		Genre:        g.Genre,
+		Status:       g.Status,
+		Progress:     g.Progress,
+		HoursPlayed:  g.HoursPlayed,
+		PersonalNote: g.PersonalNote,
+		Score:        g.Score,
+		StartedAt:    g.StartedAt,
+		FinishedAt:   g.FinishedAt,
+		CoverURL:     g.CoverURL,
+		ReleaseDate:  g.ReleaseDate,
+		Developer:    g.Developer,
+
+		ProgressFromAchievements: g.ProgressFromAchievements,
+	}
+}
+
+// Apply vuelca el input sobre g.
+func (in GameInput) Apply(g *Game) {
+	g.Title = in.Title
+	g.Platform = in.Platform
+	g.Genre = in.Genre
+	g.Status = in.Status
+	g.Progress = in.Progress
+	g.ProgressFromAchievements = in.ProgressFromAchievements
+	g.HoursPlayed = in.HoursPlayed
+	g.PersonalNote = in.PersonalNote
+	g.Score = in.Score
+	g.StartedAt = in.StartedAt
+	g.FinishedAt = in.FinishedAt
+	g.CoverURL = in.CoverURL
+	g.ReleaseDate = in.ReleaseDate
+	g.Developer = in.Developer
+}
+
+// GameInputFields son los nombres JSON de los campos editables de GameInput
+// (sin version, que no es un dato del juego).
+var GameInputFields = []string{
+	"title", "platform", "genre", "status", "progress", "hoursPlayed",
+	"personalNote", "score", "startedAt", "finishedAt", "coverURL",
+	"releaseDate", "developer", "progressFromAchievements",
+}
+
+// ApplyFields vuelca sobre g solo los campos de fields (nombres JSON); el
+// resto conserva el valor que tiene g. Con fields nil es igual que Apply.
+func (in GameInput) ApplyFields(g *Game, fields map[string]bool) {
+	if fields == nil {
+		in.Apply(g)
+		return
+	}
+	merged := NewGameInput(*g)
+	for field := range fields {
+		switch field {
+		case "title":
+			merged.Title = in.Title
+		case "platform":
+			merged.Platform = in.Platform
+		case "genre":
+			merged.Genre = in.Genre
+		case "status":
+			merged.Status = in.Status
+		case "progress":
+			merged.Progress = in.Progress
+		case "hoursPlayed":
+			merged.HoursPlayed = in.HoursPlayed
+		case "personalNote":
+			merged.PersonalNote = in.PersonalNote
+		case "score":
+			merged.Score = in.Score
+		case "startedAt":
+			merged.StartedAt = in.StartedAt
+		case "finishedAt":
+			merged.FinishedAt = in.FinishedAt
+		case "coverURL":
+			merged.CoverURL = in.CoverURL
+		case "releaseDate":
+			merged.ReleaseDate = in.ReleaseDate
+		case "developer":
+			merged.Developer = in.Developer
+		case "progressFromAchievements":
+			merged.ProgressFromAchievements = in.ProgressFromAchievements
+		}
+	}
+	merged.Apply(g)
+}
+
+// Validate hace los chequeos que no se expresan con tags de binding.
+// Un estado vacío es válido: el service asigna DefaultGameStatus. CoverURL
+// puede ser una URL http(s) externa o la de la portada subida.
+func (in GameInput) Validate() []FieldError {
+	var errs []FieldError
+	if in.Status != "" && !IsValidGameStatus(in.Status) {
+		errs = append(errs, FieldError{Field: "status", Message: oneOfMessage(GameStatuses)})
+	}
+	if in.StartedAt != nil && in.FinishedAt != nil && in.FinishedAt.Before(*in.StartedAt) {
+		errs = append(errs, FieldError{Field: "finishedAt", Message: "must not be before startedAt"})
+	}
+	if in.CoverURL != "" && !IsUploadedCoverURL(in.CoverURL) && !isHTTPURL(in.CoverURL) {
+		errs = append(errs, FieldError{Field: "coverURL", Message: "must be a valid http(s) URL"})
+	}
+	return errs
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameInput_Validate(t *testing.T) {
+	started := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	before := started.Add(-time.Hour)
+	after := started.Add(time.Hour)
+
+	assert.Empty(t, GameInput{Title: "T", Platform: "PC"}.Validate())
+	assert.Empty(t, GameInput{Status: StatusPlaying, StartedAt: &started, FinishedAt: &after}.Validate())
+	assert.Empty(t, GameInput{StartedAt: &started, FinishedAt: &started}.Validate())
+
+	assert.Empty(t, GameInput{CoverURL: "https://example.com/cover.jpg"}.Validate())
+	assert.Empty(t, GameInput{CoverURL: "/games/7/cover?v=0123456789abcdef"}.Validate())
+	assert.Equal(t, "coverURL", GameInput{CoverURL: "/etc/passwd"}.Validate()[0].Field)
+	assert.Equal(t, "coverURL", GameInput{CoverURL: "ftp://example.com/cover.jpg"}.Validate()[0].Field)
+
+	errs := GameInput{Status: "playing", StartedAt: &started, FinishedAt: &before}.Validate()
+	assert.Equal(t, []string{"status", "finishedAt"}, []string{errs[0].Field, errs[1].Field})
+	assert.Equal(t, "must be one of Wishlist, Backlog, Playing, Paused, Completed, Dropped", errs[0].Message)
+}
+
+func TestGameInput_RoundTrip(t *testing.T) {
+	game := Game{ID: 7, UserID: 3, Title: "Hades", Platform: "PC", Score: 9}
+
+	input := NewGameInput(game)
+	input.Score = 10
+	input.Apply(&game)
+
+	assert.Equal(t, uint(7), game.ID)
+	assert.Equal(t, uint(3), game.UserID)
+	assert.Equal(t, "Hades", game.Title)
+	assert.Equal(t, 10, game.Score)
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// GameMetadata es lo que un proveedor de metadatos sabe de un juego.
+type GameMetadata struct {
+	ExternalID  string     `json:"externalId"`
+	Title       string     `json:"title"`
+	Genres      []string   `json:"genres"`
+	ReleaseDate *time.Time `json:"releaseDate"`
+	Developer   string     `json:"developer"`
+	CoverURL    string     `json:"coverURL"`
+}
+
+// GameEnrichment es la respuesta de /games/:id/enrich: el juego (ya
+// guardado), la ficha usada y los campos que se completaron.
+type GameEnrichment struct {
+	Game     Game         `json:"game"`
+	Metadata GameMetadata `json:"metadata"`
+	Filled   []string     `json:"filled"`
+}
+
+// MetadataCache guarda las respuestas de los proveedores de metadatos.
+// Kind es "search" (LookupKey es el título normalizado) o "game"
+// (LookupKey es el ID externo); Payload es la respuesta en JSON.
+type MetadataCache struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	Provider  string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_metadata_cache_lookup,priority:1"`
+	Kind      string    `gorm:"type:varchar(16);not null;uniqueIndex:idx_metadata_cache_lookup,priority:2"`
+	LookupKey string    `gorm:"type:varchar(200);not null;uniqueIndex:idx_metadata_cache_lookup,priority:3"`
+	Payload   string    `gorm:"type:mediumtext;not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
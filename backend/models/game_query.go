@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// GameFilter agrupa los filtros combinables del listado de juegos.
+// Los campos vacíos/nil no filtran.
+type GameFilter struct {
+	Title        string     // contiene (LIKE)
+	Status       string     // exacto, uno de GameStatuses
+	Genre        string     // contiene (LIKE)
+	Platform     string     // exacto
+	MinScore     *int       // inclusive
+	MaxScore     *int       // inclusive
+	StartedFrom  *time.Time // inclusive
+	StartedTo    *time.Time // exclusivo
+	FinishedFrom *time.Time // inclusive
+	FinishedTo   *time.Time // exclusivo
+	Tags         []string   // el juego tiene todas estas etiquetas
+	CollectionID uint       // el juego está en esta colección
+}
+
+// GameListQuery es un pedido de listado: filtros, orden y paginación.
+// Si Cursor no está vacío se usa paginación por cursor e ignora Page.
+type GameListQuery struct {
+	Filter   GameFilter
+	Sort     string // ej. "hoursPlayed,-score"
+	Page     int
+	PageSize int
+	Cursor   string
+}
+
+// GamePage es el sobre de respuesta del listado paginado.
+type GamePage struct {
+	Items      []Game `json:"items"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// GameSearchResult es un resultado de la búsqueda full-text.
+type GameSearchResult struct {
+	Game       Game           `json:"game"`
+	Relevance  float64        `json:"relevance"`
+	Highlights GameHighlights `json:"highlights"`
+}
+
+// GameHighlights contiene HTML escapado con los términos encontrados
+// envueltos en <mark>. PersonalNote es un fragmento alrededor del primer match.
+type GameHighlights struct {
+	Title        string `json:"title"`
+	PersonalNote string `json:"personalNote,omitempty"`
+}
@@ -0,0 +1,119 @@
+package models
+
+import "time"
+
+// GameStats resume la biblioteca del usuario (o la parte que cumple los
+// filtros pedidos). Las horas son las de Game.HoursPlayed.
+type GameStats struct {
+	TotalGames   int            `json:"total_games"`
+	ByStatus     map[string]int `json:"by_status"`
+	AverageHours float64        `json:"average_hours_played"`
+	// MostPlayedGenre es el género con más horas jugadas, no el más frecuente.
+	MostPlayedGenre string  `json:"most_played_genre"`
+	PendingGames    int     `json:"pending_games"`
+	TotalHours      float64 `json:"total_hours_played"`
+	// CompletionRate es completados / juegos que no están en la wishlist (0..1).
+	CompletionRate      float64         `json:"completion_rate"`
+	HoursByGenre        []GenreHours    `json:"hours_by_genre"`
+	HoursByPlatform     []PlatformHours `json:"hours_by_platform"`
+	AverageScoreByGenre []GenreScore    `json:"average_score_by_genre"`
+	Playtime            PlaytimeStats   `json:"playtime"`
+	Backlog             BacklogAge      `json:"backlog"`
+	// HoursByTag cuenta cada juego en todas sus etiquetas, así que las horas
+	// no suman el total.
+	HoursByTag []TagHours `json:"hours_by_tag"`
+}
+
+// GenreHours es una fila del ranking de géneros por horas jugadas.
+type GenreHours struct {
+	Rank  int     `json:"rank"`
+	Genre string  `json:"genre"`
+	Games int     `json:"games"`
+	Hours float64 `json:"hours"`
+}
+
+type PlatformHours struct {
+	Platform string  `json:"platform"`
+	Games    int     `json:"games"`
+	Hours    float64 `json:"hours"`
+}
+
+// TagHours resume los juegos de una etiqueta.
+type TagHours struct {
+	Tag       string  `json:"tag"`
+	Games     int     `json:"games"`
+	Hours     float64 `json:"hours"`
+	Completed int     `json:"completed"`
+}
+
+// GenreScore promedia solo los juegos puntuados (score > 0).
+type GenreScore struct {
+	Genre        string  `json:"genre"`
+	ScoredGames  int     `json:"scored_games"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// PlaytimeStats son percentiles de horas sobre los juegos con horas > 0.
+type PlaytimeStats struct {
+	Games  int     `json:"games"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	P90    float64 `json:"p90"`
+	Max    float64 `json:"max"`
+}
+
+// BacklogAge mide hace cuánto esperan los juegos en Backlog, en días desde
+// que se agregaron.
+type BacklogAge struct {
+	Games       int     `json:"games"`
+	AverageDays float64 `json:"average_days"`
+	MedianDays  float64 `json:"median_days"`
+	OldestDays  float64 `json:"oldest_days"`
+	OldestTitle string  `json:"oldest_title,omitempty"`
+}
+
+// TimelineBucket agrupa la actividad de un mes o una semana ISO.
+// Hours son las horas de los juegos terminados en el período (HoursPlayed);
+// SessionHours las de las sesiones registradas que empezaron en el período.
+type TimelineBucket struct {
+	Period       string    `json:"period"` // "2024-05" o "2024-W19"
+	Start        time.Time `json:"start"`
+	Added        int       `json:"added"`
+	Started      int       `json:"started"`
+	Finished     int       `json:"finished"`
+	Hours        float64   `json:"hours"`
+	SessionHours float64   `json:"session_hours"`
+}
+
+// YearReview es el resumen anual ("wrapped") de la biblioteca.
+type YearReview struct {
+	Year             int              `json:"year"`
+	GamesAdded       int              `json:"games_added"`
+	GamesStarted     int              `json:"games_started"`
+	GamesFinished    int              `json:"games_finished"`
+	HoursPlayed      float64          `json:"hours_played"`
+	TopGames         []YearGame       `json:"top_games"`
+	LongestGame      *YearGame        `json:"longest_game"`
+	GenresDiscovered []string         `json:"genres_discovered"`
+	CompletionStreak CompletionStreak `json:"completion_streak"`
+	Months           []TimelineBucket `json:"months"`
+}
+
+// YearGame es un juego dentro del resumen anual con sus horas de ese año.
+type YearGame struct {
+	ID    uint    `json:"id"`
+	Title string  `json:"title"`
+	Genre string  `json:"genre"`
+	Hours float64 `json:"hours"`
+	// Days es lo que tardó de empezarlo a terminarlo (solo LongestGame).
+	Days float64 `json:"days,omitempty"`
+}
+
+// CompletionStreak es la racha más larga de meses consecutivos del año con
+// al menos un juego terminado.
+type CompletionStreak struct {
+	Months int    `json:"months"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
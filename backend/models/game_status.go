@@ -0,0 +1,63 @@
+package models
+
+import "strings"
+
+// Estados válidos de un juego. Game.Status sigue siendo un string en la base
+// y en el JSON, pero solo puede tomar estos valores.
+const (
+	StatusWishlist  = "Wishlist"
+	StatusBacklog   = "Backlog"
+	StatusPlaying   = "Playing"
+	StatusPaused    = "Paused"
+	StatusCompleted = "Completed"
+	StatusDropped   = "Dropped"
+)
+
+// DefaultGameStatus se asigna a los juegos creados sin estado.
+const DefaultGameStatus = StatusBacklog
+
+// GameStatuses lista los estados en el orden en que se muestran.
+var GameStatuses = []string{
+	StatusWishlist,
+	StatusBacklog,
+	StatusPlaying,
+	StatusPaused,
+	StatusCompleted,
+	StatusDropped,
+}
+
+// oneOfMessage es el mensaje de validación para un estado que no está en
+// statuses.
+func oneOfMessage(statuses []string) string {
+	return "must be one of " + strings.Join(statuses, ", ")
+}
+
+// statusTransitions indica a qué estados se puede pasar desde cada uno.
+// Quedarse en el mismo estado siempre está permitido.
+var statusTransitions = map[string][]string{
+	StatusWishlist:  {StatusBacklog, StatusPlaying, StatusDropped},
+	StatusBacklog:   {StatusWishlist, StatusPlaying, StatusDropped},
+	StatusPlaying:   {StatusBacklog, StatusPaused, StatusCompleted, StatusDropped},
+	StatusPaused:    {StatusBacklog, StatusPlaying, StatusCompleted, StatusDropped},
+	StatusCompleted: {StatusPlaying}, // rejugar
+	StatusDropped:   {StatusBacklog, StatusPlaying},
+}
+
+// IsValidGameStatus compara de forma exacta (sensible a mayúsculas).
+func IsValidGameStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+// CanTransitionStatus indica si un juego puede pasar de from a to.
+func CanTransitionStatus(from, to string) bool {
+	if from == to {
+		return IsValidGameStatus(to)
+	}
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidGameStatus(t *testing.T) {
+	for _, status := range GameStatuses {
+		assert.True(t, IsValidGameStatus(status), status)
+	}
+	assert.False(t, IsValidGameStatus(""))
+	assert.False(t, IsValidGameStatus("playing"))
+	assert.False(t, IsValidGameStatus("Replaying"))
+}
+
+func TestCanTransitionStatus(t *testing.T) {
+	assert.True(t, CanTransitionStatus(StatusBacklog, StatusPlaying))
+	assert.True(t, CanTransitionStatus(StatusPlaying, StatusCompleted))
+	assert.True(t, CanTransitionStatus(StatusCompleted, StatusPlaying))
+	assert.True(t, CanTransitionStatus(StatusPaused, StatusPaused))
+
+	assert.False(t, CanTransitionStatus(StatusCompleted, StatusBacklog))
+	assert.False(t, CanTransitionStatus(StatusWishlist, StatusCompleted))
+	assert.False(t, CanTransitionStatus("Unknown", "Unknown"))
+}
@@ -0,0 +1,87 @@
+package models
+
+// Acciones posibles de una fila importada.
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportError   = "error"
+	// ImportWishlisted es una fila que se guardó en la lista de deseos.
+	ImportWishlisted = "wishlisted"
+)
+
+// ImportRecord es una fila ya decodificada del archivo a importar. Errors
+// trae los problemas de formato o validación; si no está vacío la fila no
+// se guarda. Fields son los campos que trae la fila, con su nombre JSON: al
+// actualizar un juego existente solo se pisan esos y el resto conserva su
+// valor. Con Fields nil la fila trae todos los campos de GameInput.
+// Wishlist marca un juego que el usuario todavía no tiene: se guarda como
+// WishlistItem (título, plataforma y nota) y no en la biblioteca.
+type ImportRecord struct {
+	Line     int
+	Input    GameInput
+	Fields   map[string]bool
+	Errors   []FieldError
+	Wishlist bool
+}
+
+// Mark registra que la fila trae los campos indicados.
+func (r *ImportRecord) Mark(fields ...string) {
+	if r.Fields == nil {
+		r.Fields = map[string]bool{}
+	}
+	for _, field := range fields {
+		r.Fields[field] = true
+	}
+}
+
+// ImportOptions controla cómo se aplican las filas.
+type ImportOptions struct {
+	// DryRun arma el reporte sin guardar nada.
+	DryRun bool
+	// UpdateExisting actualiza los juegos que ya existen con el mismo
+	// (Title, Platform) en lugar de saltearlos.
+	UpdateExisting bool
+}
+
+// ImportReport es el resultado de una importación, fila por fila.
+type ImportReport struct {
+	DryRun     bool        `json:"dryRun"`
+	Created    int         `json:"created"`
+	Updated    int         `json:"updated"`
+	Wishlisted int         `json:"wishlisted"`
+	Skipped    int         `json:"skipped"`
+	Errored    int         `json:"errored"`
+	Rows       []ImportRow `json:"rows"`
+}
+
+// ImportRow es el resultado de una fila. Line es la línea del CSV (la
+// cabecera es la 1) o la posición en el array JSON (desde 1).
+type ImportRow struct {
+	Line     int    `json:"line"`
+	Title    string `json:"title"`
+	Platform string `json:"platform"`
+	Action   string `json:"action"`
+	GameID   uint   `json:"gameId,omitempty"`
+	// WishlistItemID es el deseo creado o existente de una fila Wishlist.
+	WishlistItemID uint         `json:"wishlistItemId,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	Errors         []FieldError `json:"errors,omitempty"`
+}
+
+// Add registra row en el reporte y actualiza los contadores.
+func (r *ImportReport) Add(row ImportRow) {
+	switch row.Action {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportWishlisted:
+		r.Wishlisted++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportError:
+		r.Errored++
+	}
+	r.Rows = append(r.Rows, row)
+}
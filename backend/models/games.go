@@ -1,28 +1,41 @@
 package models
 
-import "time"
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Game struct {
 	ID           uint       `json:"id" gorm:"primaryKey;autoIncrement"`
-	Title        string     `json:"title"        gorm:"type:varchar(200);not null;index:idx_title_platform,priority:1"`
+	UserID       uint       `json:"userId"       gorm:"not null;index"`
+	User         *User      `json:"-"            gorm:"constraint:OnDelete:CASCADE"`
+	Title        string     `json:"title"        gorm:"type:varchar(200);not null;index:idx_title_platform,priority:1;index:idx_games_fulltext,class:FULLTEXT,priority:1"`
 	Platform     string     `json:"platform"     gorm:"type:varchar(80);not null;index:idx_title_platform,priority:2"`
 	Genre        string     `json:"genre"        gorm:"type:varchar(80);index"`
 	Status       string     `json:"status"       gorm:"type:varchar(32);index"`
 	Progress     int        `json:"progress"     gorm:"type:int;check:progress_between_0_100,progress >= 0 AND progress <= 100"`
-	HoursPlayed  float64    `json:"hoursPlayed"  gorm:"type:decimal(10,2);default:0"` 
-	PersonalNote string     `json:"personalNote" gorm:"type:text"`
+	HoursPlayed  float64    `json:"hoursPlayed"  gorm:"type:decimal(10,2);default:0"`
+	PersonalNote string     `json:"personalNote" gorm:"type:text;index:idx_games_fulltext,class:FULLTEXT,priority:2"`
 	Score        int        `json:"score"        gorm:"type:int;check:score_between_0_10,score >= 0 AND score <= 10"`
 	StartedAt    *time.Time `json:"startedAt"    gorm:"index"`
 	FinishedAt   *time.Time `json:"finishedAt"   gorm:"index"`
 	CoverURL     string     `json:"coverURL"     gorm:"type:varchar(500)"`
+	ReleaseDate  *time.Time `json:"releaseDate"`
+	Developer    string     `json:"developer"    gorm:"type:varchar(120)"`
+	Version      uint       `json:"version"      gorm:"not null;default:1"` // concurrencia optimista
 	CreatedAt    time.Time  `json:"createdAt"    gorm:"not null"`
 	UpdatedAt    time.Time  `json:"updatedAt"    gorm:"not null"`
+	// ProgressFromAchievements hace que Progress sea el porcentaje de logros
+	// desbloqueados en vez de un valor cargado a mano.
+	ProgressFromAchievements bool `json:"progressFromAchievements" gorm:"not null;default:false"`
+	// DeletedAt marca los juegos en la papelera; gorm los excluye de las
+	// consultas salvo con Unscoped.
+	DeletedAt gorm.DeletedAt `json:"deletedAt" gorm:"index"`
 }
 
-type GameStats struct {
-	TotalGames      int            `json:"total_games"`
-	ByStatus        map[string]int `json:"by_status"`
-	AverageHours    float64        `json:"average_hours_played"`
-	MostPlayedGenre string         `json:"most_played_genre"`
-	PendingGames    int            `json:"pending_games"`
-}
\ No newline at end of file
+// RoundHours redondea a 2 decimales, la precisión de la columna hours_played.
+func RoundHours(v float64) float64 {
+	return math.Round(v*100) / 100
+}
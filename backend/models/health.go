@@ -0,0 +1,20 @@
+package models
+
+// Readiness es la respuesta de /readyz: Status es "ok" si pasaron todos los
+// chequeos y "unavailable" si no. Checks tiene el resultado de cada uno: "ok"
+// o uno de los ReadinessCheck*, sin el error de la base (queda en el log).
+type Readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (r Readiness) Ready() bool { return r.Status == ReadinessOK }
+
+const (
+	ReadinessOK          = "ok"
+	ReadinessUnavailable = "unavailable"
+
+	ReadinessCheckUnreachable = "unreachable"
+	ReadinessCheckPending     = "pending migrations"
+	ReadinessCheckFailed      = "check failed"
+)
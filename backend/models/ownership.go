@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Formatos de una copia del juego.
+const (
+	FormatPhysical = "physical"
+	FormatDigital  = "digital"
+)
+
+// GameOwnership es una copia del juego que tiene el usuario: el mismo juego
+// en PC y en Switch es un solo Game con dos GameOwnership. Las horas de cada
+// plataforma se suman en Game.HoursPlayed.
+type GameOwnership struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	GameID      uint       `json:"gameId"      gorm:"not null;index"`
+	Game        *Game      `json:"-"           gorm:"constraint:OnDelete:CASCADE"`
+	Platform    string     `json:"platform"    gorm:"type:varchar(80);not null"`
+	Store       string     `json:"store"       gorm:"type:varchar(80)"`
+	Edition     string     `json:"edition"     gorm:"type:varchar(120)"`
+	PurchasedAt *time.Time `json:"purchasedAt"`
+	PricePaid   *float64   `json:"pricePaid"   gorm:"type:decimal(10,2)"`
+	Format      string     `json:"format"      gorm:"type:varchar(16)"`
+	HoursPlayed float64    `json:"hoursPlayed" gorm:"type:decimal(10,2);default:0"`
+	CreatedAt   time.Time  `json:"createdAt"   gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updatedAt"   gorm:"not null"`
+}
+
+// OwnershipInput es el body de alta y modificación de copias. Sin
+// hoursPlayed, el alta de la primera copia hereda las horas cargadas a mano
+// en el juego para no perderlas.
+type OwnershipInput struct {
+	Platform    string     `json:"platform"    binding:"required,max=80"`
+	Store       string     `json:"store"       binding:"max=80"`
+	Edition     string     `json:"edition"     binding:"max=120"`
+	PurchasedAt *time.Time `json:"purchasedAt"`
+	PricePaid   *float64   `json:"pricePaid"   binding:"omitempty,min=0"`
+	Format      string     `json:"format"      binding:"max=16"`
+	HoursPlayed *float64   `json:"hoursPlayed" binding:"omitempty,min=0"`
+}
+
+// Validate hace los chequeos que no se expresan con tags de binding.
+func (in OwnershipInput) Validate() []FieldError {
+	if in.Format != "" && in.Format != FormatPhysical && in.Format != FormatDigital {
+		return []FieldError{{Field: "format", Message: "must be physical or digital"}}
+	}
+	return nil
+}
+
+// Apply vuelca el input sobre o. Las horas solo se pisan si vienen.
+func (in OwnershipInput) Apply(o *GameOwnership) {
+	o.Platform = in.Platform
+	o.Store = in.Store
+	o.Edition = in.Edition
+	o.PurchasedAt = in.PurchasedAt
+	o.PricePaid = in.PricePaid
+	o.Format = in.Format
+	if in.HoursPlayed != nil {
+		o.HoursPlayed = *in.HoursPlayed
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// PlaySession es una sesión de juego. Con EndedAt nil es el cronómetro en
+// curso, que todavía no suma horas. OwnershipID indica, si se conoce, en qué
+// copia del juego se jugó.
+type PlaySession struct {
+	ID              uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	GameID          uint           `json:"gameId"          gorm:"not null;index"`
+	Game            *Game          `json:"-"               gorm:"constraint:OnDelete:CASCADE"`
+	OwnershipID     *uint          `json:"ownershipId"     gorm:"index"`
+	Ownership       *GameOwnership `json:"-"               gorm:"constraint:OnDelete:SET NULL"`
+	StartedAt       time.Time      `json:"startedAt"       gorm:"not null;index"`
+	EndedAt         *time.Time     `json:"endedAt"`
+	DurationMinutes int            `json:"durationMinutes" gorm:"not null;default:0"`
+	Note            string         `json:"note"            gorm:"type:varchar(500)"`
+	CreatedAt       time.Time      `json:"createdAt"       gorm:"not null"`
+	UpdatedAt       time.Time      `json:"updatedAt"       gorm:"not null"`
+}
+
+// Running indica si la sesión es un cronómetro sin detener.
+func (s PlaySession) Running() bool {
+	return s.EndedAt == nil
+}
+
+// PlaySessionInput es el body para cargar o reemplazar una sesión a mano. Se
+// indica el fin o la duración; si vienen los dos, manda el fin. OwnershipID
+// es opcional y tiene que ser una copia del mismo juego.
+type PlaySessionInput struct {
+	StartedAt       *time.Time `json:"startedAt"       binding:"required"`
+	EndedAt         *time.Time `json:"endedAt"`
+	DurationMinutes *int       `json:"durationMinutes" binding:"omitempty,min=0"`
+	Note            string     `json:"note"            binding:"max=500"`
+	OwnershipID     *uint      `json:"ownershipId"`
+}
+
+// Validate hace los chequeos que no se expresan con tags de binding.
+func (in PlaySessionInput) Validate() []FieldError {
+	var errs []FieldError
+	if in.EndedAt == nil && in.DurationMinutes == nil {
+		errs = append(errs, FieldError{Field: "endedAt", Message: "endedAt or durationMinutes is required"})
+	}
+	if in.StartedAt != nil && in.EndedAt != nil && in.EndedAt.Before(*in.StartedAt) {
+		errs = append(errs, FieldError{Field: "endedAt", Message: "must not be before startedAt"})
+	}
+	return errs
+}
+
+// Apply vuelca el input sobre s calculando el fin o la duración que falte.
+func (in PlaySessionInput) Apply(s *PlaySession) {
+	s.StartedAt = *in.StartedAt
+	s.Note = in.Note
+	s.OwnershipID = in.OwnershipID
+	if in.EndedAt != nil {
+		endedAt := *in.EndedAt
+		s.EndedAt = &endedAt
+		s.DurationMinutes = SessionMinutes(s.StartedAt, endedAt)
+		return
+	}
+	endedAt := s.StartedAt.Add(time.Duration(*in.DurationMinutes) * time.Minute)
+	s.EndedAt = &endedAt
+	s.DurationMinutes = *in.DurationMinutes
+}
+
+// SessionMinutes redondea la duración entre start y end a minutos enteros.
+func SessionMinutes(start, end time.Time) int {
+	return int(end.Sub(start).Round(time.Minute) / time.Minute)
+}
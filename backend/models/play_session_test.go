@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaySessionInput_ApplyWithEnd(t *testing.T) {
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	end := start.Add(2*time.Hour + 10*time.Minute + 40*time.Second)
+	ignored := 5
+
+	var session PlaySession
+	PlaySessionInput{StartedAt: &start, EndedAt: &end, DurationMinutes: &ignored, Note: "jefe final"}.Apply(&session)
+
+	assert.Equal(t, 131, session.DurationMinutes)
+	assert.Equal(t, end, *session.EndedAt)
+	assert.Equal(t, "jefe final", session.Note)
+	assert.False(t, session.Running())
+}
+
+func TestPlaySessionInput_ApplyWithDuration(t *testing.T) {
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	minutes := 45
+
+	var session PlaySession
+	PlaySessionInput{StartedAt: &start, DurationMinutes: &minutes}.Apply(&session)
+
+	require.NotNil(t, session.EndedAt)
+	assert.Equal(t, start.Add(45*time.Minute), *session.EndedAt)
+	assert.Equal(t, 45, session.DurationMinutes)
+}
+
+func TestPlaySessionInput_Validate(t *testing.T) {
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Minute)
+
+	errs := PlaySessionInput{StartedAt: &start}.Validate()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "endedAt", errs[0].Field)
+
+	errs = PlaySessionInput{StartedAt: &start, EndedAt: &before}.Validate()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "must not be before startedAt", errs[0].Message)
+}
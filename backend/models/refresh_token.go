@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// RefreshToken es un token de refresco persistido. Solo se guarda el hash
+// SHA-256: el valor en claro se entrega una única vez al cliente.
+//
+// Todos los tokens que surgen de rotar un mismo login comparten FamilyID,
+// que además viaja como "sid" en los access tokens. Revocar la familia
+// cierra la sesión completa.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `json:"userId" gorm:"not null;index"`
+	User      *User      `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	FamilyID  string     `json:"familyId" gorm:"type:varchar(64);not null;index"`
+	TokenHash string     `json:"-" gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expiresAt" gorm:"not null"`
+	UsedAt    *time.Time `json:"usedAt"`    // se completa al rotarlo
+	RevokedAt *time.Time `json:"revokedAt"` // logout o reutilización detectada
+	CreatedAt time.Time  `json:"createdAt" gorm:"not null"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
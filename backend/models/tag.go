@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Tag es una etiqueta libre del usuario ("Co-op", "Roguelike"). A diferencia
+// de Genre, un juego puede tener varias.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `json:"userId"    gorm:"not null;uniqueIndex:idx_tags_user_name,priority:1"`
+	User      *User     `json:"-"         gorm:"constraint:OnDelete:CASCADE"`
+	Name      string    `json:"name"      gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_user_name,priority:2"`
+	CreatedAt time.Time `json:"createdAt" gorm:"not null"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"not null"`
+	// Games es la cantidad de juegos con la etiqueta (solo en los listados).
+	Games int `json:"games" gorm:"-:migration;->"`
+}
+
+// GameTag es la tabla intermedia entre juegos y etiquetas.
+type GameTag struct {
+	GameID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	Game      *Game     `gorm:"constraint:OnDelete:CASCADE"`
+	TagID     uint      `gorm:"primaryKey;autoIncrement:false;index"`
+	Tag       *Tag      `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// Collection es una lista de juegos armada por el usuario ("Verano 2026").
+type Collection struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      uint      `json:"userId"      gorm:"not null;uniqueIndex:idx_collections_user_name,priority:1"`
+	User        *User     `json:"-"           gorm:"constraint:OnDelete:CASCADE"`
+	Name        string    `json:"name"        gorm:"type:varchar(100);not null;uniqueIndex:idx_collections_user_name,priority:2"`
+	Description string    `json:"description" gorm:"type:varchar(500)"`
+	CreatedAt   time.Time `json:"createdAt"   gorm:"not null"`
+	UpdatedAt   time.Time `json:"updatedAt"   gorm:"not null"`
+	// Games es la cantidad de juegos de la colección (solo en los listados).
+	Games int `json:"games" gorm:"-:migration;->"`
+}
+
+// CollectionGame es la tabla intermedia entre colecciones y juegos.
+type CollectionGame struct {
+	CollectionID uint        `gorm:"primaryKey;autoIncrement:false"`
+	Collection   *Collection `gorm:"constraint:OnDelete:CASCADE"`
+	GameID       uint        `gorm:"primaryKey;autoIncrement:false;index"`
+	Game         *Game       `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time   `gorm:"not null"`
+}
+
+// TagInput es el body de alta y renombrado de etiquetas.
+type TagInput struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+func (TagInput) Validate() []FieldError { return nil }
+
+// CollectionInput es el body de alta y modificación de colecciones.
+type CollectionInput struct {
+	Name        string `json:"name"        binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+func (CollectionInput) Validate() []FieldError { return nil }
+
+// GameTagsInput reemplaza las etiquetas de un juego por nombre; las que no
+// existen se crean.
+type GameTagsInput struct {
+	Tags []string `json:"tags" binding:"max=50,dive,required,max=50"`
+}
+
+func (GameTagsInput) Validate() []FieldError { return nil }
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Prioridad de un deseo: 1 es la más baja y 5 la más alta.
+const (
+	MinWishlistPriority     = 1
+	MaxWishlistPriority     = 5
+	DefaultWishlistPriority = 3
+)
+
+// WishlistItem es un juego que el usuario todavía no tiene. Se guarda aparte
+// de Game porque precio objetivo y fecha de salida no aplican a la
+// biblioteca; al comprarlo se promueve a un Game (ver PromoteInput).
+type WishlistItem struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      uint       `json:"userId"      gorm:"not null;index"`
+	User        *User      `json:"-"           gorm:"constraint:OnDelete:CASCADE"`
+	Title       string     `json:"title"       gorm:"type:varchar(200);not null"`
+	Platform    string     `json:"platform"    gorm:"type:varchar(80)"`
+	TargetPrice *float64   `json:"targetPrice" gorm:"type:decimal(10,2)"`
+	ReleaseDate *time.Time `json:"releaseDate" gorm:"index"`
+	Priority    int        `json:"priority"    gorm:"type:int;not null;default:3;check:priority_between_1_5,priority >= 1 AND priority <= 5"`
+	Note        string     `json:"note"        gorm:"type:varchar(500)"`
+	CreatedAt   time.Time  `json:"createdAt"   gorm:"not null"`
+	UpdatedAt   time.Time  `json:"updatedAt"   gorm:"not null"`
+}
+
+// WishlistInput es el body de alta y modificación de deseos. Sin prioridad
+// se usa DefaultWishlistPriority.
+type WishlistInput struct {
+	Title       string     `json:"title"       binding:"required,max=200"`
+	Platform    string     `json:"platform"    binding:"max=80"`
+	TargetPrice *float64   `json:"targetPrice" binding:"omitempty,min=0"`
+	ReleaseDate *time.Time `json:"releaseDate"`
+	Priority    int        `json:"priority"    binding:"omitempty,min=1,max=5"`
+	Note        string     `json:"note"        binding:"max=500"`
+}
+
+// Validate rechaza un título con solo espacios, que required deja pasar.
+func (in WishlistInput) Validate() []FieldError {
+	if strings.TrimSpace(in.Title) == "" {
+		return []FieldError{{Field: "title", Message: "is required"}}
+	}
+	return nil
+}
+
+// Apply vuelca el input sobre item.
+func (in WishlistInput) Apply(item *WishlistItem) {
+	item.Title = in.Title
+	item.Platform = in.Platform
+	item.TargetPrice = in.TargetPrice
+	item.ReleaseDate = in.ReleaseDate
+	item.Priority = in.Priority
+	if item.Priority == 0 {
+		item.Priority = DefaultWishlistPriority
+	}
+	item.Note = in.Note
+}
+
+// PromoteInput es el body opcional para pasar un deseo a la biblioteca. La
+// plataforma por defecto es la del deseo y el estado, DefaultGameStatus;
+// Wishlist no se acepta porque el juego deja de ser un deseo.
+type PromoteInput struct {
+	Platform string `json:"platform" binding:"max=80"`
+	Status   string `json:"status"   binding:"max=32"`
+}
+
+// promoteStatuses son los estados con los que un deseo puede pasar a la
+// biblioteca: todos menos Wishlist.
+func promoteStatuses() []string {
+	statuses := make([]string, 0, len(GameStatuses)-1)
+	for _, status := range GameStatuses {
+		if status != StatusWishlist {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+func (in PromoteInput) Validate() []FieldError {
+	if in.Status == StatusWishlist {
+		return []FieldError{{Field: "status", Message: "cannot be Wishlist when adding the game to the library"}}
+	}
+	if in.Status != "" && !IsValidGameStatus(in.Status) {
+		return []FieldError{{Field: "status", Message: oneOfMessage(promoteStatuses())}}
+	}
+	return nil
+}
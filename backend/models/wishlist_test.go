@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWishlistInput_Validate(t *testing.T) {
+	assert.Empty(t, WishlistInput{Title: "Hades"}.Validate())
+	assert.Equal(t, []FieldError{{Field: "title", Message: "is required"}}, WishlistInput{Title: "  \t"}.Validate())
+}
+
+func TestPromoteInput_Validate(t *testing.T) {
+	assert.Empty(t, PromoteInput{}.Validate())
+	assert.Empty(t, PromoteInput{Status: StatusPlaying}.Validate())
+
+	errs := PromoteInput{Status: StatusWishlist}.Validate()
+	assert.Equal(t, "cannot be Wishlist when adding the game to the library", errs[0].Message)
+
+	errs = PromoteInput{Status: "playing"}.Validate()
+	assert.Equal(t, "status", errs[0].Field)
+	assert.Equal(t, "must be one of Backlog, Playing, Paused, Completed, Dropped", errs[0].Message)
+}
@@ -5,14 +5,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAuthRoutes(r *gin.Engine) {
-	authController := controller.NewAuthController()
-	
+func SetupAuthRoutes(r *gin.Engine, authController *controller.AuthController) {
 	// Rutas públicas de autenticación
 	auth := r.Group("/auth")
 	{
 		auth.POST("/register", authController.Register)
 		auth.POST("/login", authController.Login)
+		auth.POST("/refresh", authController.Refresh)
+		auth.POST("/logout", authController.Logout)
 	}
 
 	// Rutas protegidas
@@ -5,17 +5,115 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupGameRoutes(r *gin.Engine) {
+// SetupHealthRoutes registra los chequeos de vida y de disponibilidad, sin
+// autenticación para que los usen docker y los balanceadores.
+func SetupHealthRoutes(r *gin.Engine, healthController *controller.HealthController) {
+	r.GET("/healthz", healthController.Healthz)
+	r.GET("/readyz", healthController.Readyz)
+}
+
+// Controllers reúne los controllers de la API, ya armados con sus services
+// (ver main).
+type Controllers struct {
+	Auth         *controller.AuthController
+	Health       *controller.HealthController
+	Games        *controller.GameController
+	Trash        *controller.TrashController
+	Search       *controller.SearchController
+	Stats        *controller.StatsController
+	Transfer     *controller.TransferController
+	Metadata     *controller.MetadataController
+	Covers       *controller.CoverController
+	Ownerships   *controller.OwnershipController
+	Achievements *controller.AchievementController
+	Tags         *controller.TagController
+	Collections  *controller.CollectionController
+	Sessions     *controller.SessionController
+	Wishlist     *controller.WishlistController
+}
+
+// SetupGameRoutes registra las rutas de la biblioteca; ctl.Health corta los
+// grupos con 503 mientras la base no esté disponible, ctl.Auth los protege y
+// el resto de los controllers atiende cada funcionalidad.
+func SetupGameRoutes(r *gin.Engine, ctl Controllers) {
+	// Cada usuario solo ve y edita su propia biblioteca
 	games := r.Group("/games")
+	games.Use(ctl.Health.RequireReady(), ctl.Auth.AuthMiddleware())
+	{
+		games.GET("/", ctl.Games.GetAllGames)
+		games.POST("/", ctl.Games.CreateGame)
+		games.GET("/:id", ctl.Games.GetGameByID)
+		games.PUT("/:id", ctl.Games.UpdateGame)
+		games.PATCH("/:id", ctl.Games.PatchGame)
+		games.DELETE("/:id", ctl.Games.DeleteGame)
+		games.GET("/trash", ctl.Trash.ListTrash)
+		games.GET("/export", ctl.Transfer.ExportGames)
+		games.POST("/import", ctl.Transfer.ImportGames)
+		games.POST("/import/:source", ctl.Transfer.ImportFromSource)
+		games.POST("/:id/restore", ctl.Trash.RestoreGame)
+		games.POST("/:id/enrich", ctl.Metadata.EnrichGame)
+		games.POST("/:id/cover", ctl.Covers.UploadCover)
+		games.GET("/:id/cover", ctl.Covers.GetCover)
+		games.DELETE("/:id/cover", ctl.Covers.DeleteCover)
+		games.GET("/:id/ownerships", ctl.Ownerships.ListOwnerships)
+		games.POST("/:id/ownerships", ctl.Ownerships.CreateOwnership)
+		games.PUT("/:id/ownerships/:ownershipId", ctl.Ownerships.UpdateOwnership)
+		games.DELETE("/:id/ownerships/:ownershipId", ctl.Ownerships.DeleteOwnership)
+		games.GET("/:id/achievements", ctl.Achievements.ListAchievements)
+		games.POST("/:id/achievements", ctl.Achievements.CreateAchievement)
+		games.POST("/:id/achievements/import", ctl.Achievements.ImportAchievements)
+		games.POST("/:id/achievements/:achievementId/toggle", ctl.Achievements.ToggleAchievement)
+		games.DELETE("/:id/achievements/:achievementId", ctl.Achievements.DeleteAchievement)
+		games.GET("/:id/tags", ctl.Tags.GetGameTags)
+		games.PUT("/:id/tags", ctl.Tags.SetGameTags)
+		games.GET("/:id/collections", ctl.Collections.GetGameCollections)
+		games.POST("/:id/collections/:collectionId", ctl.Collections.AddGameToCollection)
+		games.DELETE("/:id/collections/:collectionId", ctl.Collections.RemoveGameFromCollection)
+		games.GET("/:id/sessions", ctl.Sessions.ListSessions)
+		games.POST("/:id/sessions", ctl.Sessions.CreateSession)
+		games.PUT("/:id/sessions/:sessionId", ctl.Sessions.UpdateSession)
+		games.DELETE("/:id/sessions/:sessionId", ctl.Sessions.DeleteSession)
+		games.POST("/:id/sessions/start", ctl.Sessions.StartSession)
+		games.POST("/:id/sessions/stop", ctl.Sessions.StopSession)
+		games.GET("/search", ctl.Search.SearchGames)
+		games.GET("/title", ctl.Games.GetByTitle)
+		games.GET("/status", ctl.Games.GetByStatus)
+		games.GET("/genre", ctl.Games.GetByGenre)
+		games.GET("/stats", ctl.Stats.GetStats)
+		games.GET("/stats/timeline", ctl.Stats.GetTimeline)
+		games.GET("/stats/year/:year", ctl.Stats.GetYearReview)
+	}
+
+	// Juegos que el usuario todavía no tiene
+	wishlist := r.Group("/wishlist")
+	wishlist.Use(ctl.Health.RequireReady(), ctl.Auth.AuthMiddleware())
+	{
+		wishlist.GET("/", ctl.Wishlist.ListWishlist)
+		wishlist.POST("/", ctl.Wishlist.CreateWishlistItem)
+		wishlist.GET("/upcoming", ctl.Wishlist.UpcomingReleases)
+		wishlist.GET("/:id", ctl.Wishlist.GetWishlistItem)
+		wishlist.PUT("/:id", ctl.Wishlist.UpdateWishlistItem)
+		wishlist.DELETE("/:id", ctl.Wishlist.DeleteWishlistItem)
+		wishlist.POST("/:id/promote", ctl.Wishlist.PromoteWishlistItem)
+	}
+
+	tags := r.Group("/tags")
+	tags.Use(ctl.Health.RequireReady(), ctl.Auth.AuthMiddleware())
+	{
+		tags.GET("/", ctl.Tags.ListTags)
+		tags.POST("/", ctl.Tags.CreateTag)
+		tags.PUT("/:id", ctl.Tags.UpdateTag)
+		tags.DELETE("/:id", ctl.Tags.DeleteTag)
+	}
+
+	collections := r.Group("/collections")
+	collections.Use(ctl.Health.RequireReady(), ctl.Auth.AuthMiddleware())
 	{
-		games.GET("/", controller.GetAllGames)
-		games.POST("/", controller.CreateGame)
-		games.GET("/:id", controller.GetGameByID)
-		games.PUT("/:id", controller.UpdateGame)
-		games.DELETE("/:id", controller.DeleteGame)
-		games.GET("/title", controller.GetByTitle)
-		games.GET("/status", controller.GetByStatus)
-		games.GET("/genre", controller.GetByGenre)
-		games.GET("/stats", controller.GetStats)
+		collections.GET("/", ctl.Collections.ListCollections)
+		collections.POST("/", ctl.Collections.CreateCollection)
+		collections.GET("/:id", ctl.Collections.GetCollection)
+		collections.PUT("/:id", ctl.Collections.UpdateCollection)
+		collections.DELETE("/:id", ctl.Collections.DeleteCollection)
+		collections.GET("/:id/games", ctl.Collections.ListCollectionGames)
 	}
 }
@@ -10,7 +10,7 @@ go install gotest.tools/gotestsum@latest
 go install github.com/jstemmer/go-junit-report/v2@latest
 
 echo 🧪 Ejecutando pruebas unitarias con cobertura...
-gotestsum --format=standard-verbose --junitfile test-results-go.xml -- -covermode=atomic -coverprofile=coverage.out -coverpkg=./service,./controller,./models ./service ./controller ./models
+gotestsum --format=standard-verbose --junitfile test-results-go.xml -- -covermode=atomic -coverprofile=coverage.out -coverpkg=./service,./controller,./importers,./metadata,./storage,./db,./models ./service ./controller ./importers ./metadata ./storage ./db ./models
 
 echo 📊 Generando reportes de cobertura...
 go tool cover -func=coverage.out > coverage.txt
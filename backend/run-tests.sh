@@ -8,7 +8,7 @@ go install github.com/jstemmer/go-junit-report/v2@latest
 
 echo "🧪 Ejecutando pruebas unitarias con cobertura..."
 gotestsum --format=standard-verbose --junitfile test-results-go.xml -- \
-  -covermode=atomic -coverprofile=coverage.out ./service ./controller
+  -covermode=atomic -coverprofile=coverage.out ./service ./controller ./importers ./metadata ./storage ./db
 
 echo "📊 Generando reportes de cobertura..."
 go tool cover -func=coverage.out > coverage.txt
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// shutdownTimeout lee SHUTDOWN_TIMEOUT: cuánto se espera a que terminen los
+// requests en curso antes de cortarlos.
+func shutdownTimeout() (time.Duration, error) {
+	raw := os.Getenv("SHUTDOWN_TIMEOUT")
+	if raw == "" {
+		return defaultShutdownTimeout, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("SHUTDOWN_TIMEOUT inválido: %s", raw)
+	}
+	return d, nil
+}
+
+// serve atiende requests hasta que se cancela ctx (SIGINT/SIGTERM) y después
+// deja de aceptar conexiones y espera a los requests en curso hasta timeout.
+// Si el servidor no puede escuchar devuelve ese error sin esperar a ctx.
+func serve(ctx context.Context, srv *http.Server, timeout time.Duration) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Apagando el servidor: esperando hasta %v a los requests en curso", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"gametracker/clock"
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAchievementNotFound = errors.New("achievement not found")
+	ErrAchievementExists   = errors.New("an achievement with that name already exists")
+)
+
+// Las funciones de logros verifican primero que el juego sea del usuario y,
+// si el juego tiene ProgressFromAchievements, recalculan Progress en la
+// misma transacción.
+
+// AchievementService administra los logros de cada juego y el progreso que
+// se deriva de ellos.
+type AchievementService struct {
+	conn  *gorm.DB
+	games *GameService
+}
+
+func NewAchievementService(conn *gorm.DB) *AchievementService {
+	return &AchievementService{conn: conn, games: NewGameService(NewGameRepository(conn))}
+}
+
+// ListAchievements devuelve los logros del juego en el orden en que se
+// cargaron.
+func (s *AchievementService) ListAchievements(userID uint, gameID string) ([]models.Achievement, error) {
+	achievements := []models.Achievement{}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return achievements, err
+	}
+	err = s.conn.Where("game_id = ?", game.ID).Order("id ASC").Find(&achievements).Error
+	return achievements, err
+}
+
+func (s *AchievementService) CreateAchievement(userID uint, gameID string, input models.AchievementInput) (models.Achievement, error) {
+	var achievement models.Achievement
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return achievement, err
+	}
+	input.Apply(&achievement)
+	achievement.GameID = game.ID
+	stampUnlocked(&achievement)
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Achievement{}).
+			Where("game_id = ? AND name = ?", game.ID, achievement.Name).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAchievementExists
+		}
+		if err := tx.Create(&achievement).Error; err != nil {
+			return err
+		}
+		return recalcProgress(tx, game)
+	})
+	return achievement, err
+}
+
+// ImportAchievements carga logros en bloque: actualiza los que ya existen
+// con el mismo nombre (sin distinguir mayúsculas), crea el resto y, con
+// Replace, borra los que no vinieron. Devuelve todos los logros del juego.
+func (s *AchievementService) ImportAchievements(userID uint, gameID string, input models.AchievementImportInput) ([]models.Achievement, error) {
+	achievements := []models.Achievement{}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return achievements, err
+	}
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		existing := []models.Achievement{}
+		if err := tx.Where("game_id = ?", game.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byName := make(map[string]*models.Achievement, len(existing))
+		for i := range existing {
+			byName[strings.ToLower(existing[i].Name)] = &existing[i]
+		}
+
+		imported := make(map[uint]bool, len(input.Achievements))
+		var created []models.Achievement
+		for _, in := range input.Achievements {
+			current, ok := byName[strings.ToLower(strings.TrimSpace(in.Name))]
+			if !ok {
+				var achievement models.Achievement
+				in.Apply(&achievement)
+				achievement.GameID = game.ID
+				stampUnlocked(&achievement)
+				created = append(created, achievement)
+				continue
+			}
+			// Un logro que ya estaba desbloqueado conserva su fecha si la
+			// importación no trae otra.
+			unlockedAt := current.UnlockedAt
+			in.Apply(current)
+			if current.Unlocked && current.UnlockedAt == nil {
+				current.UnlockedAt = unlockedAt
+			}
+			stampUnlocked(current)
+			imported[current.ID] = true
+			if err := tx.Model(current).Select("name", "description", "unlocked", "unlocked_at").Updates(current).Error; err != nil {
+				return err
+			}
+		}
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+		if input.Replace {
+			var stale []uint
+			for _, a := range existing {
+				if !imported[a.ID] {
+					stale = append(stale, a.ID)
+				}
+			}
+			if len(stale) > 0 {
+				if err := tx.Where("game_id = ?", game.ID).Delete(&models.Achievement{}, stale).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if err := recalcProgress(tx, game); err != nil {
+			return err
+		}
+		return tx.Where("game_id = ?", game.ID).Order("id ASC").Find(&achievements).Error
+	})
+	return achievements, err
+}
+
+// ToggleAchievement desbloquea el logro (con la fecha actual) o lo vuelve a
+// bloquear.
+func (s *AchievementService) ToggleAchievement(userID uint, gameID, achievementID string) (models.Achievement, error) {
+	var achievement models.Achievement
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return achievement, err
+	}
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("game_id = ?", game.ID).First(&achievement, achievementID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAchievementNotFound
+		}
+		if err != nil {
+			return err
+		}
+		achievement.Unlocked = !achievement.Unlocked
+		achievement.UnlockedAt = nil
+		stampUnlocked(&achievement)
+		if err := tx.Model(&achievement).Select("unlocked", "unlocked_at").Updates(&achievement).Error; err != nil {
+			return err
+		}
+		return recalcProgress(tx, game)
+	})
+	return achievement, err
+}
+
+func (s *AchievementService) DeleteAchievement(userID uint, gameID, achievementID string) error {
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return err
+	}
+	return s.conn.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("game_id = ?", game.ID).Delete(&models.Achievement{}, achievementID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrAchievementNotFound
+		}
+		return recalcProgress(tx, game)
+	})
+}
+
+// stampUnlocked pone la fecha actual a un logro desbloqueado que no la trae.
+func stampUnlocked(a *models.Achievement) {
+	if a.Unlocked && a.UnlockedAt == nil {
+		stamp := clock.Now()
+		a.UnlockedAt = &stamp
+	}
+}
+
+// achievementProgress calcula el porcentaje de logros desbloqueados del
+// juego, redondeado. Sin logros es 0.
+func achievementProgress(tx *gorm.DB, gameID uint) (int, error) {
+	var counts struct {
+		Total    int64
+		Unlocked int64
+	}
+	err := tx.Model(&models.Achievement{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN unlocked THEN 1 ELSE 0 END), 0) AS unlocked").
+		Where("game_id = ?", gameID).Scan(&counts).Error
+	if err != nil || counts.Total == 0 {
+		return 0, err
+	}
+	return int(math.Round(float64(counts.Unlocked) * 100 / float64(counts.Total))), nil
+}
+
+// derivedProgress es el Progress de un juego con ProgressFromAchievements a
+// partir del porcentaje de logros desbloqueados. Un juego Completed queda en
+// 100 aunque le falten logros: gana la regla de applyStatusLifecycle.
+func derivedProgress(status string, achievements int) int {
+	if status == models.StatusCompleted {
+		return 100
+	}
+	return achievements
+}
+
+// recalcProgress actualiza Progress de los juegos que lo derivan de sus
+// logros y sube su versión, como recalcHoursPlayed.
+func recalcProgress(tx *gorm.DB, game models.Game) error {
+	if !game.ProgressFromAchievements {
+		return nil
+	}
+	achievements, err := achievementProgress(tx, game.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.Game{}).Where("id = ?", game.ID).Updates(map[string]interface{}{
+		"progress": derivedProgress(game.Status, achievements),
+		"version":  gorm.Expr("version + 1"),
+	}).Error
+}
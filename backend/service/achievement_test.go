@@ -0,0 +1,160 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectTrackedGame espera la lectura de un juego que deriva Progress de sus
+// logros.
+func expectTrackedGame(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\?").
+		WithArgs(uint(1), "5", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "progress", "progress_from_achievements"}).
+			AddRow(5, 1, "Hollow Knight", 40, true))
+}
+
+func expectAchievementProgress(mock sqlmock.Sqlmock, total, unlocked int) {
+	mock.ExpectQuery("^SELECT COUNT\\(\\*\\) AS total, COALESCE\\(SUM\\(CASE WHEN unlocked THEN 1 ELSE 0 END\\), 0\\) AS unlocked FROM `achievements` WHERE game_id = \\?$").
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "unlocked"}).AddRow(total, unlocked))
+}
+
+func TestToggleAchievement_UnlocksAndRecalculatesProgress(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	fixed := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	fixNow(t, fixed)
+	expectTrackedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `achievements` WHERE game_id = \\? AND `achievements`.`id` = \\?").
+		WithArgs(uint(5), "3", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "game_id", "name", "unlocked"}).AddRow(3, 5, "Mapa completo", false))
+	mock.ExpectExec("^UPDATE `achievements` SET `unlocked`=\\?,`unlocked_at`=\\?,`updated_at`=\\? WHERE `id` = \\?$").
+		WithArgs(true, fixed, sqlmock.AnyArg(), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAchievementProgress(mock, 3, 2)
+	mock.ExpectExec("^UPDATE `games` SET `progress`=\\?,`version`=version \\+ 1,`updated_at`=\\? WHERE id = \\? AND `games`.`deleted_at` IS NULL$").
+		WithArgs(67, sqlmock.AnyArg(), uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	achievement, err := NewAchievementService(conn).ToggleAchievement(1, "5", "3")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, achievement.Unlocked)
+	require.NotNil(t, achievement.UnlockedAt)
+	assert.Equal(t, fixed, *achievement.UnlockedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestToggleAchievement_ManualProgressUntouched(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `achievements`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "game_id", "name", "unlocked", "unlocked_at"}).
+			AddRow(3, 5, "Mapa completo", true, time.Now()))
+	mock.ExpectExec("^UPDATE `achievements` SET `unlocked`=\\?,`unlocked_at`=\\?").
+		WithArgs(false, nil, sqlmock.AnyArg(), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	achievement, err := NewAchievementService(conn).ToggleAchievement(1, "5", "3")
+
+	require.NoError(t, err)
+	assert.False(t, achievement.Unlocked)
+	assert.Nil(t, achievement.UnlockedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportAchievements_UpdatesByNameAndReplaces(t *testing.T) {
+	// Arrange: "mapa completo" ya existe, "Sin daño" no viene y se borra
+	conn, mock, _ := newMockDB(t)
+	fixed := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	fixNow(t, fixed)
+	expectTrackedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `achievements` WHERE game_id = \\?").
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "game_id", "name", "unlocked"}).
+			AddRow(3, 5, "mapa completo", false).
+			AddRow(4, 5, "Sin daño", false))
+	mock.ExpectExec("^UPDATE `achievements` SET `name`=\\?,`description`=\\?,`unlocked`=\\?,`unlocked_at`=\\?,`updated_at`=\\? WHERE `id` = \\?$").
+		WithArgs("Mapa completo", "", true, fixed, sqlmock.AnyArg(), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `achievements`").
+		WithArgs(uint(5), "Jefe final", "", false, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("^DELETE FROM `achievements` WHERE game_id = \\? AND `achievements`.`id` = \\?$").
+		WithArgs(uint(5), uint(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAchievementProgress(mock, 2, 1)
+	mock.ExpectExec("^UPDATE `games` SET `progress`=\\?").
+		WithArgs(50, sqlmock.AnyArg(), uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT \\* FROM `achievements` WHERE game_id = \\? ORDER BY id ASC").
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "game_id", "name", "unlocked"}).
+			AddRow(3, 5, "Mapa completo", true).
+			AddRow(7, 5, "Jefe final", false))
+	mock.ExpectCommit()
+
+	// Act
+	achievements, err := NewAchievementService(conn).ImportAchievements(1, "5", models.AchievementImportInput{
+		Achievements: []models.AchievementInput{
+			{Name: " Mapa completo ", Unlocked: true},
+			{Name: "Jefe final"},
+		},
+		Replace: true,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, achievements, 2)
+	assert.Equal(t, "Mapa completo", achievements[0].Name)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAchievement_Duplicate(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `achievements` WHERE game_id = \\? AND name = \\?").
+		WithArgs(uint(5), "Jefe final").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	_, err := NewAchievementService(conn).CreateAchievement(1, "5", models.AchievementInput{Name: "Jefe final"})
+
+	assert.ErrorIs(t, err, ErrAchievementExists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_ProgressFromAchievements(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	game := &models.Game{ID: 5, Title: "Hollow Knight", Platform: "PC", Status: "Playing", Progress: 90, ProgressFromAchievements: true}
+
+	expectHoursDerived(mock, 0)
+	expectAchievementProgress(mock, 8, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := NewGameService(NewGameRepository(conn)).Update(1, models.Game{ID: 5, Status: "Playing", Progress: 40, Version: 1}, game)
+
+	// Assert: el progreso tipeado se ignora
+	require.NoError(t, err)
+	assert.Equal(t, 25, game.Progress)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,30 +2,44 @@ package service
 
 import (
 	"errors"
-	"gametracker/db"
 	"gametracker/models"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
-type AuthService struct{}
+// AuthService registra y autentica usuarios a través de users y guarda las
+// sesiones (refresh tokens) en conn.
+type AuthService struct {
+	jwtConfig JWTConfig
+	users     UserRepository
+	conn      *gorm.DB
+}
 
-func NewAuthService() *AuthService {
-	return &AuthService{}
+// NewAuthService crea el servicio con la configuración JWT del entorno.
+func NewAuthService(users UserRepository, conn *gorm.DB) *AuthService {
+	cfg, err := LoadJWTConfig()
+	if err != nil {
+		log.Fatal("Configuración JWT inválida: ", err)
+	}
+	return NewAuthServiceWithConfig(cfg, users, conn)
 }
 
-var jwtSecret = []byte("gametracker_secret_key_2024") // En producción usar variable de entorno
+// NewAuthServiceWithConfig crea el servicio con una configuración explícita (útil en tests).
+func NewAuthServiceWithConfig(cfg JWTConfig, users UserRepository, conn *gorm.DB) *AuthService {
+	return &AuthService{jwtConfig: cfg, users: users, conn: conn}
+}
 
 // Register crea un nuevo usuario
 func (s *AuthService) Register(req models.RegisterRequest) (*models.User, error) {
-	// Verificar si el usuario ya existe (solo verificar existencia, no cargar datos)
-	var count int64
-	if err := db.DB.Model(&models.User{}).Where("username = ? OR email = ?", req.Username, req.Email).Count(&count).Error; err != nil {
+	// Verificar si el usuario ya existe
+	exists, err := s.users.ExistsByUsernameOrEmail(req.Username, req.Email)
+	if err != nil {
 		return nil, errors.New("error al verificar usuario existente")
 	}
-	if count > 0 {
+	if exists {
 		return nil, errors.New("usuario o email ya existe")
 	}
 
@@ -45,7 +59,7 @@ func (s *AuthService) Register(req models.RegisterRequest) (*models.User, error)
 	}
 
 	// Guardar en base de datos
-	if err := db.DB.Create(user).Error; err != nil {
+	if err := s.users.Create(user); err != nil {
 		return nil, errors.New("error al crear usuario")
 	}
 
@@ -56,11 +70,10 @@ func (s *AuthService) Register(req models.RegisterRequest) (*models.User, error)
 
 // Login autentica un usuario
 func (s *AuthService) Login(req models.LoginRequest) (*models.AuthResponse, error) {
-	var user models.User
-	
 	// Buscar usuario por username o email
-	if err := db.DB.Where("username = ? OR email = ?", req.Username, req.Username).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	user, err := s.users.FindByLogin(req.Username)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
 			return nil, errors.New("usuario no encontrado")
 		}
 		return nil, errors.New("error al buscar usuario")
@@ -71,8 +84,13 @@ func (s *AuthService) Login(req models.LoginRequest) (*models.AuthResponse, erro
 		return nil, errors.New("contraseña incorrecta")
 	}
 
-	// Generar token JWT
-	token, err := s.generateToken(user.ID, user.Username)
+	// Abrir sesión: refresh token persistido + access token de vida corta
+	sessionID, refreshToken, err := s.startSession(user.ID)
+	if err != nil {
+		return nil, errors.New("error al crear sesión")
+	}
+
+	token, err := s.generateToken(user.ID, user.Username, sessionID)
 	if err != nil {
 		return nil, errors.New("error al generar token")
 	}
@@ -81,32 +99,55 @@ func (s *AuthService) Login(req models.LoginRequest) (*models.AuthResponse, erro
 	user.Password = ""
 
 	return &models.AuthResponse{
-		Token: token,
-		User:  user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         user,
 	}, nil
 }
 
-// generateToken genera un token JWT
-func (s *AuthService) generateToken(userID uint, username string) (string, error) {
+// generateToken genera un access token JWT firmado con la clave activa.
+// sessionID identifica la familia de refresh tokens ("sid").
+func (s *AuthService) generateToken(userID uint, username string, sessionID string) (string, error) {
+	secret, ok := s.jwtConfig.key(s.jwtConfig.ActiveKeyID)
+	if !ok {
+		return "", errors.New("clave de firma activa no configurada")
+	}
+
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id":  userID,
 		"username": username,
-		"exp":      time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 días
-		"iat":      time.Now().Unix(),
+		"sid":      sessionID,
+		"iss":      s.jwtConfig.Issuer,
+		"aud":      s.jwtConfig.Audience,
+		"exp":      now.Add(s.jwtConfig.AccessTTL).Unix(),
+		"iat":      now.Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token.Header["kid"] = s.jwtConfig.ActiveKeyID
+	return token.SignedString(secret)
 }
 
-// ValidateToken valida un token JWT
+// ValidateToken valida un token JWT: firma con la clave indicada por su kid,
+// emisor y audiencia.
 func (s *AuthService) ValidateToken(tokenString string) (*jwt.Token, error) {
 	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("método de firma inválido")
 		}
-		return jwtSecret, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := s.jwtConfig.key(kid)
+		if !ok {
+			return nil, errors.New("kid desconocido")
+		}
+		return secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.jwtConfig.Issuer),
+		jwt.WithAudience(s.jwtConfig.Audience),
+		jwt.WithExpirationRequired(),
+	)
 }
 
 // GetUserFromToken extrae información del usuario desde el token
@@ -6,18 +6,19 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
 func TestNewAuthService(t *testing.T) {
-	service := NewAuthService()
+	service := NewAuthService(nil, nil)
 	assert.NotNil(t, service)
 }
 
 func TestAuthService_Register_Success(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
 	req := models.RegisterRequest{
@@ -41,7 +42,7 @@ func TestAuthService_Register_Success(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 	user, err := service.Register(req)
 
 	require.NoError(t, err)
@@ -53,7 +54,7 @@ func TestAuthService_Register_Success(t *testing.T) {
 }
 
 func TestAuthService_Register_UserExists(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
 	req := models.RegisterRequest{
@@ -70,7 +71,7 @@ func TestAuthService_Register_UserExists(t *testing.T) {
 		WithArgs("existinguser", "existing@example.com").
 		WillReturnRows(countRows)
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 	user, err := service.Register(req)
 
 	require.Error(t, err)
@@ -81,7 +82,7 @@ func TestAuthService_Register_UserExists(t *testing.T) {
 }
 
 func TestAuthService_Register_CountError(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
 	req := models.RegisterRequest{
@@ -95,7 +96,7 @@ func TestAuthService_Register_CountError(t *testing.T) {
 		WithArgs("anyuser", "any@example.com").
 		WillReturnError(assert.AnError)
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 	user, err := service.Register(req)
 
 	require.Error(t, err)
@@ -106,7 +107,7 @@ func TestAuthService_Register_CountError(t *testing.T) {
 }
 
 func TestAuthService_Register_CreateError(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
 	req := models.RegisterRequest{
@@ -129,7 +130,7 @@ func TestAuthService_Register_CreateError(t *testing.T) {
 		WillReturnError(assert.AnError)
 	mock.ExpectRollback()
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 	user, err := service.Register(req)
 
 	require.Error(t, err)
@@ -139,7 +140,7 @@ func TestAuthService_Register_CreateError(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 func TestAuthService_Login_UserNotFound(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
 	req := models.LoginRequest{
@@ -151,7 +152,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 		WithArgs("nonexistent", "nonexistent", 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 	authResponse, err := service.Login(req)
 
 	require.Error(t, err)
@@ -162,10 +163,10 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 }
 
 func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
-	_, _, sqlDB := setupTestDB(t)
+	gormDB, _, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 	token, err := service.ValidateToken("invalid.token.here")
 
 	// ValidateToken returns a token even if invalid, so err can be nil or not
@@ -179,11 +180,11 @@ func TestAuthService_ValidateToken_InvalidToken(t *testing.T) {
 }
 
 func TestAuthService_GetUserFromToken_InvalidToken(t *testing.T) {
-	_, _, sqlDB := setupTestDB(t)
+	gormDB, _, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
-	service := NewAuthService()
-	
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
+
 	// Create an invalid token
 	token, _ := service.ValidateToken("invalid.token.here")
 	userID, username, err := service.GetUserFromToken(token)
@@ -194,10 +195,10 @@ func TestAuthService_GetUserFromToken_InvalidToken(t *testing.T) {
 }
 
 func TestAuthService_Login_Success(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 
 	// Hash a real password for testing
 	testUser := models.User{}
@@ -216,6 +217,7 @@ func TestAuthService_Login_Success(t *testing.T) {
 	mock.ExpectQuery("^SELECT \\* FROM `users` WHERE username = \\? OR email = \\? ORDER BY `users`.`id` LIMIT \\?$").
 		WithArgs("testuser", "testuser", 1).
 		WillReturnRows(rows)
+	expectRefreshTokenInsert(mock)
 
 	authResponse, err := service.Login(req)
 
@@ -229,10 +231,10 @@ func TestAuthService_Login_Success(t *testing.T) {
 }
 
 func TestAuthService_ValidateToken_Success(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 
 	// Hash password
 	testUser := models.User{}
@@ -251,6 +253,7 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 	mock.ExpectQuery("^SELECT \\* FROM `users` WHERE username = \\? OR email = \\? ORDER BY `users`.`id` LIMIT \\?$").
 		WithArgs("testuser", "testuser", 1).
 		WillReturnRows(rows)
+	expectRefreshTokenInsert(mock)
 
 	// Login to get valid token
 	authResponse, err := service.Login(req)
@@ -267,10 +270,10 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 }
 
 func TestAuthService_GetUserFromToken_Success(t *testing.T) {
-	_, mock, sqlDB := setupTestDB(t)
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
 
-	service := NewAuthService()
+	service := NewAuthService(NewUserRepository(gormDB), gormDB)
 
 	// Hash password
 	testUser := models.User{}
@@ -289,6 +292,7 @@ func TestAuthService_GetUserFromToken_Success(t *testing.T) {
 	mock.ExpectQuery("^SELECT \\* FROM `users` WHERE username = \\? OR email = \\? ORDER BY `users`.`id` LIMIT \\?$").
 		WithArgs("testuser", "testuser", 1).
 		WillReturnRows(rows)
+	expectRefreshTokenInsert(mock)
 
 	// Login to get valid token
 	authResponse, err := service.Login(req)
@@ -307,3 +311,81 @@ func TestAuthService_GetUserFromToken_Success(t *testing.T) {
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func testJWTConfig() JWTConfig {
+	return JWTConfig{
+		Keys:        []JWTKey{{ID: "k1", Secret: []byte("secret-1")}},
+		ActiveKeyID: "k1",
+		Issuer:      "gametracker-test",
+		Audience:    "gametracker-test-api",
+		AccessTTL:   time.Hour,
+	}
+}
+
+func TestAuthService_GenerateToken_SetsKidIssuerAndAudience(t *testing.T) {
+	service := NewAuthServiceWithConfig(testJWTConfig(), nil, nil)
+
+	tokenString, err := service.generateToken(1, "testuser", "session-1")
+	require.NoError(t, err)
+
+	token, err := service.ValidateToken(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", token.Header["kid"])
+
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, "gametracker-test", claims["iss"])
+	assert.Equal(t, "gametracker-test-api", claims["aud"])
+}
+
+func TestAuthService_ValidateToken_KeyRotation(t *testing.T) {
+	oldService := NewAuthServiceWithConfig(testJWTConfig(), nil, nil)
+	oldToken, err := oldService.generateToken(1, "testuser", "session-1")
+	require.NoError(t, err)
+
+	// Rotación: se agrega k2 como clave activa y k1 sigue aceptada
+	rotated := testJWTConfig()
+	rotated.Keys = append(rotated.Keys, JWTKey{ID: "k2", Secret: []byte("secret-2")})
+	rotated.ActiveKeyID = "k2"
+	newService := NewAuthServiceWithConfig(rotated, nil, nil)
+
+	token, err := newService.ValidateToken(oldToken)
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	newToken, err := newService.generateToken(1, "testuser", "session-1")
+	require.NoError(t, err)
+	token, err = newService.ValidateToken(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", token.Header["kid"])
+
+	// Al retirar k1, los tokens viejos dejan de validar
+	retired := rotated
+	retired.Keys = rotated.Keys[1:]
+	_, err = NewAuthServiceWithConfig(retired, nil, nil).ValidateToken(oldToken)
+	assert.Error(t, err)
+}
+
+func TestAuthService_ValidateToken_WrongIssuerOrAudience(t *testing.T) {
+	tokenString, err := NewAuthServiceWithConfig(testJWTConfig(), nil, nil).generateToken(1, "testuser", "session-1")
+	require.NoError(t, err)
+
+	otherIssuer := testJWTConfig()
+	otherIssuer.Issuer = "someone-else"
+	_, err = NewAuthServiceWithConfig(otherIssuer, nil, nil).ValidateToken(tokenString)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+
+	otherAudience := testJWTConfig()
+	otherAudience.Audience = "another-api"
+	_, err = NewAuthServiceWithConfig(otherAudience, nil, nil).ValidateToken(tokenString)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+}
+
+func TestAuthService_ValidateToken_ExpiredToken(t *testing.T) {
+	cfg := testJWTConfig()
+	cfg.AccessTTL = -time.Minute
+	tokenString, err := NewAuthServiceWithConfig(cfg, nil, nil).generateToken(1, "testuser", "session-1")
+	require.NoError(t, err)
+
+	_, err = NewAuthServiceWithConfig(testJWTConfig(), nil, nil).ValidateToken(tokenString)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
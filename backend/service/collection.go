@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrCollectionExists   = errors.New("a collection with that name already exists")
+)
+
+// collectionGamesCount cuenta los juegos de cada colección sin los de la
+// papelera.
+const collectionGamesCount = "(SELECT COUNT(*) FROM collection_games JOIN games ON games.id = collection_games.game_id " +
+	"WHERE collection_games.collection_id = collections.id AND games.deleted_at IS NULL) AS games"
+
+// CollectionService administra las colecciones del usuario y los juegos que
+// contienen.
+type CollectionService struct {
+	conn  *gorm.DB
+	games *GameService
+}
+
+func NewCollectionService(conn *gorm.DB) *CollectionService {
+	return &CollectionService{conn: conn, games: NewGameService(NewGameRepository(conn))}
+}
+
+// ListCollections devuelve las colecciones del usuario por nombre, con la
+// cantidad de juegos de cada una.
+func (s *CollectionService) ListCollections(userID uint) ([]models.Collection, error) {
+	collections := []models.Collection{}
+	err := s.conn.Model(&models.Collection{}).Select("collections.*, "+collectionGamesCount).
+		Where("user_id = ?", userID).Order("name ASC").Find(&collections).Error
+	return collections, err
+}
+
+func (s *CollectionService) GetCollection(userID uint, id string) (models.Collection, error) {
+	var collection models.Collection
+	err := s.conn.Model(&models.Collection{}).Select("collections.*, "+collectionGamesCount).
+		Where("user_id = ?", userID).First(&collection, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return collection, ErrCollectionNotFound
+	}
+	return collection, err
+}
+
+func (s *CollectionService) CreateCollection(collection *models.Collection) error {
+	collection.ID = 0
+	collection.Name = strings.TrimSpace(collection.Name)
+	if err := s.checkCollectionName(collection.UserID, 0, collection.Name); err != nil {
+		return err
+	}
+	return s.conn.Create(collection).Error
+}
+
+func (s *CollectionService) UpdateCollection(userID uint, id string, input models.CollectionInput) (models.Collection, error) {
+	collection, err := s.GetCollection(userID, id)
+	if err != nil {
+		return collection, err
+	}
+	collection.Name = strings.TrimSpace(input.Name)
+	collection.Description = input.Description
+	if err := s.checkCollectionName(userID, collection.ID, collection.Name); err != nil {
+		return collection, err
+	}
+	err = s.conn.Model(&collection).Select("name", "description").Updates(&collection).Error
+	return collection, err
+}
+
+// DeleteCollection borra la colección; los juegos no se tocan.
+func (s *CollectionService) DeleteCollection(userID uint, id string) error {
+	collection, err := s.GetCollection(userID, id)
+	if err != nil {
+		return err
+	}
+	return s.conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.CollectionGame{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&collection).Error
+	})
+}
+
+// GetGameCollections devuelve las colecciones en las que está el juego.
+func (s *CollectionService) GetGameCollections(userID uint, gameID string) ([]models.Collection, error) {
+	collections := []models.Collection{}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return collections, err
+	}
+	err = s.conn.Select("collections.*").
+		Joins("JOIN collection_games ON collection_games.collection_id = collections.id").
+		Where("collection_games.game_id = ?", game.ID).
+		Order("collections.name ASC").Find(&collections).Error
+	return collections, err
+}
+
+// AddGameToCollection agrega el juego a la colección. Si ya estaba no hace
+// nada.
+func (s *CollectionService) AddGameToCollection(userID uint, gameID, collectionID string) error {
+	game, collection, err := s.gameAndCollection(userID, gameID, collectionID)
+	if err != nil {
+		return err
+	}
+	link := models.CollectionGame{CollectionID: collection.ID, GameID: game.ID}
+	return s.conn.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error
+}
+
+// RemoveGameFromCollection saca el juego de la colección. No es error que
+// no estuviera.
+func (s *CollectionService) RemoveGameFromCollection(userID uint, gameID, collectionID string) error {
+	game, collection, err := s.gameAndCollection(userID, gameID, collectionID)
+	if err != nil {
+		return err
+	}
+	return s.conn.Where("collection_id = ? AND game_id = ?", collection.ID, game.ID).
+		Delete(&models.CollectionGame{}).Error
+}
+
+func (s *CollectionService) gameAndCollection(userID uint, gameID, collectionID string) (models.Game, models.Collection, error) {
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return game, models.Collection{}, err
+	}
+	var collection models.Collection
+	err = s.conn.Where("user_id = ?", userID).First(&collection, collectionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return game, collection, ErrCollectionNotFound
+	}
+	return game, collection, err
+}
+
+func (s *CollectionService) checkCollectionName(userID, exceptID uint, name string) error {
+	var count int64
+	err := s.conn.Model(&models.Collection{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, exceptID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCollectionExists
+	}
+	return nil
+}
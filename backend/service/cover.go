@@ -0,0 +1,272 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders registrados para image.Decode
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"gametracker/models"
+	"gametracker/storage"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultCoverMaxBytes = 5 << 20
+	DefaultCoverDir      = "data/covers"
+	// Límite de píxeles para no decodificar imágenes gigantes (bombas de
+	// descompresión): 40 MP alcanzan para cualquier portada.
+	MaxCoverPixels = 40_000_000
+
+	coverThumbnailQuality = 85
+)
+
+var (
+	ErrCoverNotFound    = errors.New("cover not found")
+	ErrCoverTooLarge    = errors.New("cover too large")
+	ErrUnsupportedImage = errors.New("unsupported image type (use JPEG, PNG, GIF or WebP)")
+	ErrCoversDisabled   = errors.New("cover storage not configured")
+)
+
+// Tipos aceptados (según http.DetectContentType) y su extensión.
+var coverTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// CoverConfig configura dónde se guardan las portadas y su tamaño máximo.
+type CoverConfig struct {
+	Dir      string
+	MaxBytes int64
+}
+
+// LoadCoverConfig lee COVER_STORAGE_DIR y COVER_MAX_BYTES.
+func LoadCoverConfig() (CoverConfig, error) {
+	cfg := CoverConfig{Dir: envOrDefault("COVER_STORAGE_DIR", DefaultCoverDir), MaxBytes: DefaultCoverMaxBytes}
+	if raw := os.Getenv("COVER_MAX_BYTES"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n <= 0 {
+			return cfg, errors.New("COVER_MAX_BYTES inválido: " + raw)
+		}
+		cfg.MaxBytes = n
+	}
+	return cfg, nil
+}
+
+// CoverService guarda las portadas en storage y su registro en game_covers.
+// Con storage nil las portadas quedan deshabilitadas (ErrCoversDisabled).
+type CoverService struct {
+	conn     *gorm.DB
+	games    *GameService
+	storage  storage.Storage
+	maxBytes int64
+}
+
+func NewCoverService(conn *gorm.DB, store storage.Storage, maxBytes int64) *CoverService {
+	return &CoverService{conn: conn, games: NewGameService(NewGameRepository(conn)), storage: store, maxBytes: maxBytes}
+}
+
+// MaxBytes es el tamaño máximo aceptado para una portada.
+func (s *CoverService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// SaveCover guarda la imagen de r como portada del juego junto con sus
+// miniaturas (JPEG) y reemplaza la anterior. El tipo se detecta por el
+// contenido, no por el nombre ni el Content-Type del cliente.
+func (s *CoverService) SaveCover(ctx context.Context, userID uint, gameID string, r io.Reader) (models.GameCover, error) {
+	var cover models.GameCover
+	if s.storage == nil {
+		return cover, ErrCoversDisabled
+	}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return cover, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return cover, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return cover, fmt.Errorf("%w (max %d bytes)", ErrCoverTooLarge, s.maxBytes)
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := coverTypes[contentType]
+	if !ok {
+		return cover, ErrUnsupportedImage
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return cover, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > MaxCoverPixels {
+		return cover, fmt.Errorf("%w (max %d pixels)", ErrCoverTooLarge, MaxCoverPixels)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return cover, ErrUnsupportedImage
+	}
+
+	sum := sha256.Sum256(data)
+	cover = models.GameCover{
+		GameID:      game.ID,
+		Hash:        hex.EncodeToString(sum[:])[:16],
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+		Size:        int64(len(data)),
+	}
+	prefix := coverPrefix(cover)
+	if err := s.storage.Put(ctx, prefix+"/"+models.CoverOriginal+ext, bytes.NewReader(data), contentType); err != nil {
+		return cover, err
+	}
+	for _, size := range models.CoverSizes {
+		var thumb bytes.Buffer
+		if err := jpeg.Encode(&thumb, thumbnail(img, size.Width), &jpeg.Options{Quality: coverThumbnailQuality}); err != nil {
+			return cover, err
+		}
+		if err := s.storage.Put(ctx, prefix+"/"+size.Name+".jpg", &thumb, "image/jpeg"); err != nil {
+			return cover, err
+		}
+	}
+
+	var previous models.GameCover
+	if err := s.conn.Where("game_id = ?", game.ID).First(&previous).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return cover, err
+	}
+	setCoverURLs(&cover)
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "game_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"hash", "content_type", "width", "height", "size", "updated_at"}),
+		}).Create(&cover).Error
+		if err != nil {
+			return err
+		}
+		// La portada subida reemplaza a la URL externa del juego.
+		return tx.Model(&models.Game{}).Where("id = ?", game.ID).Updates(map[string]interface{}{
+			"cover_url": cover.URLs[models.CoverOriginal],
+			"version":   gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return cover, err
+	}
+	if previous.Hash != "" && previous.Hash != cover.Hash {
+		if err := s.storage.Delete(ctx, coverPrefix(previous)); err != nil {
+			log.Printf("delete old cover %s: %v", coverPrefix(previous), err)
+		}
+	}
+	return cover, nil
+}
+
+// GetCover devuelve los datos de la portada del juego.
+func (s *CoverService) GetCover(userID uint, gameID string) (models.GameCover, error) {
+	var cover models.GameCover
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return cover, err
+	}
+	err = s.conn.Where("game_id = ?", game.ID).First(&cover).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return cover, ErrCoverNotFound
+	}
+	if err != nil {
+		return cover, err
+	}
+	setCoverURLs(&cover)
+	return cover, nil
+}
+
+// OpenCover abre el archivo de la portada en el tamaño pedido.
+func (s *CoverService) OpenCover(ctx context.Context, cover models.GameCover, size string) (io.ReadCloser, storage.Object, error) {
+	if s.storage == nil {
+		return nil, storage.Object{}, ErrCoversDisabled
+	}
+	key := coverPrefix(cover) + "/" + size + ".jpg"
+	if size == models.CoverOriginal {
+		key = coverPrefix(cover) + "/" + models.CoverOriginal + coverTypes[cover.ContentType]
+	}
+	body, obj, err := s.storage.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, obj, ErrCoverNotFound
+	}
+	return body, obj, err
+}
+
+// DeleteCover borra la portada del juego y sus archivos. Si Game.CoverURL
+// apuntaba a ella queda vacía; una URL externa puesta después se conserva.
+func (s *CoverService) DeleteCover(ctx context.Context, userID uint, gameID string) error {
+	if s.storage == nil {
+		return ErrCoversDisabled
+	}
+	cover, err := s.GetCover(userID, gameID)
+	if err != nil {
+		return err
+	}
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("game_id = ?", cover.GameID).Delete(&models.GameCover{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Game{}).
+			Where("id = ? AND cover_url LIKE ?", cover.GameID, models.CoverPath(cover.GameID)+"%").
+			Updates(map[string]interface{}{"cover_url": "", "version": gorm.Expr("version + 1")}).Error
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, coverPrefix(cover)); err != nil {
+		log.Printf("delete cover %s: %v", coverPrefix(cover), err)
+	}
+	return nil
+}
+
+func coverPrefix(cover models.GameCover) string {
+	return fmt.Sprintf("covers/%d/%s", cover.GameID, cover.Hash)
+}
+
+func setCoverURLs(cover *models.GameCover) {
+	base := models.CoverPath(cover.GameID)
+	cover.URLs = map[string]string{
+		models.CoverOriginal: fmt.Sprintf("%s?v=%s", base, cover.Hash),
+	}
+	for _, size := range models.CoverSizes {
+		cover.URLs[size.Name] = fmt.Sprintf("%s?size=%s&v=%s", base, size.Name, cover.Hash)
+	}
+}
+
+// thumbnail escala img al ancho pedido manteniendo la proporción (sin
+// agrandar) sobre fondo blanco, porque JPEG no tiene transparencia.
+func thumbnail(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() < width {
+		width = bounds.Dx()
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
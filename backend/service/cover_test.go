@@ -0,0 +1,212 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"gametracker/models"
+	"gametracker/storage"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	coverLookupQuery = "SELECT \\* FROM `game_covers` WHERE game_id = \\?"
+	coverURLUpdate   = "^UPDATE `games` SET `cover_url`=\\?,`version`=version \\+ 1,`updated_at`=\\? WHERE id = \\? AND `games`.`deleted_at` IS NULL$"
+)
+
+// newCoverService arma un CoverService sobre conn con un storage local en
+// un directorio temporal.
+func newCoverService(t *testing.T, conn *gorm.DB, maxBytes int64) (*CoverService, *storage.Local) {
+	t.Helper()
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	return NewCoverService(conn, local, maxBytes), local
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func decodedSize(t *testing.T, s storage.Storage, key string) (int, int) {
+	t.Helper()
+	body, _, err := s.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer body.Close()
+	cfg, err := jpeg.DecodeConfig(body)
+	require.NoError(t, err)
+	return cfg.Width, cfg.Height
+}
+
+func TestSaveCover_StoresOriginalAndThumbnails(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	covers, local := newCoverService(t, conn, DefaultCoverMaxBytes)
+	data := pngImage(t, 400, 600)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])[:16]
+
+	expectOwnedGame(mock)
+	mock.ExpectQuery(coverLookupQuery).
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"game_id", "hash"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `game_covers` .* ON DUPLICATE KEY UPDATE").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(coverURLUpdate).
+		WithArgs("/games/5/cover?v="+hash, sqlmock.AnyArg(), uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	cover, err := covers.SaveCover(context.Background(), 1, "5", bytes.NewReader(data))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), cover.GameID)
+	assert.Equal(t, hash, cover.Hash)
+	assert.Equal(t, "image/png", cover.ContentType)
+	assert.Equal(t, 400, cover.Width)
+	assert.Equal(t, 600, cover.Height)
+	assert.Equal(t, int64(len(data)), cover.Size)
+	assert.Equal(t, "/games/5/cover?size=small&v="+cover.Hash, cover.URLs["small"])
+
+	prefix := "covers/5/" + cover.Hash
+	body, obj, err := local.Get(context.Background(), prefix+"/original.png")
+	require.NoError(t, err)
+	stored, _ := io.ReadAll(body)
+	require.NoError(t, body.Close())
+	assert.Equal(t, data, stored)
+	assert.Equal(t, "image/png", obj.ContentType)
+
+	// Las miniaturas mantienen la proporción y nunca agrandan la imagen
+	w, h := decodedSize(t, local, prefix+"/small.jpg")
+	assert.Equal(t, [2]int{160, 240}, [2]int{w, h})
+	w, h = decodedSize(t, local, prefix+"/medium.jpg")
+	assert.Equal(t, [2]int{320, 480}, [2]int{w, h})
+	w, h = decodedSize(t, local, prefix+"/large.jpg")
+	assert.Equal(t, [2]int{400, 600}, [2]int{w, h})
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveCover_ReplacesPreviousFiles(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	covers, local := newCoverService(t, conn, DefaultCoverMaxBytes)
+	ctx := context.Background()
+	require.NoError(t, local.Put(ctx, "covers/5/0123456789abcdef/original.png", strings.NewReader("old"), "image/png"))
+
+	expectOwnedGame(mock)
+	mock.ExpectQuery(coverLookupQuery).
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"game_id", "hash", "content_type"}).AddRow(5, "0123456789abcdef", "image/png"))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `game_covers`").WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(coverURLUpdate).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	_, err := covers.SaveCover(ctx, 1, "5", bytes.NewReader(pngImage(t, 10, 10)))
+
+	// Assert
+	require.NoError(t, err)
+	_, _, err = local.Get(ctx, "covers/5/0123456789abcdef/original.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCover_ClearsUploadedCoverURL(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	covers, local := newCoverService(t, conn, DefaultCoverMaxBytes)
+	ctx := context.Background()
+	require.NoError(t, local.Put(ctx, "covers/5/0123456789abcdef/original.png", strings.NewReader("img"), "image/png"))
+
+	expectOwnedGame(mock)
+	mock.ExpectQuery(coverLookupQuery).
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"game_id", "hash", "content_type"}).AddRow(5, "0123456789abcdef", "image/png"))
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM `game_covers` WHERE game_id = \\?$").
+		WithArgs(uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Solo se vacía si CoverURL sigue apuntando a la portada subida
+	mock.ExpectExec("^UPDATE `games` SET `cover_url`=\\?,`version`=version \\+ 1,`updated_at`=\\? WHERE \\(id = \\? AND cover_url LIKE \\?\\) AND `games`.`deleted_at` IS NULL$").
+		WithArgs("", sqlmock.AnyArg(), uint(5), "/games/5/cover%").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := covers.DeleteCover(ctx, 1, "5")
+
+	// Assert
+	require.NoError(t, err)
+	_, _, err = local.Get(ctx, "covers/5/0123456789abcdef/original.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveCover_RejectsNonImages(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	covers, _ := newCoverService(t, conn, DefaultCoverMaxBytes)
+	expectOwnedGame(mock)
+
+	// Un .png que en realidad es texto se detecta por el contenido
+	_, err := covers.SaveCover(context.Background(), 1, "5", strings.NewReader("<html>not an image</html>"))
+
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveCover_TooLarge(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	covers, _ := newCoverService(t, conn, 100)
+	expectOwnedGame(mock)
+
+	_, err := covers.SaveCover(context.Background(), 1, "5", bytes.NewReader(make([]byte, 101)))
+
+	assert.ErrorIs(t, err, ErrCoverTooLarge)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveCover_Disabled(t *testing.T) {
+	covers := NewCoverService(nil, nil, DefaultCoverMaxBytes)
+
+	_, err := covers.SaveCover(context.Background(), 1, "5", strings.NewReader("x"))
+
+	assert.ErrorIs(t, err, ErrCoversDisabled)
+}
+
+func TestOpenCover_Sizes(t *testing.T) {
+	covers, local := newCoverService(t, nil, DefaultCoverMaxBytes)
+	ctx := context.Background()
+	cover := models.GameCover{GameID: 5, Hash: "0123456789abcdef", ContentType: "image/webp"}
+	require.NoError(t, local.Put(ctx, "covers/5/0123456789abcdef/original.webp", strings.NewReader("webp"), "image/webp"))
+
+	body, obj, err := covers.OpenCover(ctx, cover, models.CoverOriginal)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "image/webp", obj.ContentType)
+
+	_, _, err = covers.OpenCover(ctx, cover, "small")
+	assert.ErrorIs(t, err, ErrCoverNotFound)
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ErrInvalidQuery envuelve errores de parámetros de listado (sort, cursor...).
+// Los controllers lo mapean a 400.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Las fechas nulas se ordenan como si fueran la más antigua posible, igual
+// que hace MySQL con NULL en orden ascendente. Usar un valor concreto permite
+// comparar en la paginación por cursor.
+var nullSortTime = time.Date(1000, 1, 1, 0, 0, 0, 0, time.Local)
+
+type sortColumn struct {
+	expr   string
+	isTime bool
+	value  func(g *models.Game) interface{}
+}
+
+// sortableColumns mapea el nombre JSON del campo a su expresión SQL.
+var sortableColumns = map[string]sortColumn{
+	"title":       {expr: "title", value: func(g *models.Game) interface{} { return g.Title }},
+	"platform":    {expr: "platform", value: func(g *models.Game) interface{} { return g.Platform }},
+	"genre":       {expr: "genre", value: func(g *models.Game) interface{} { return g.Genre }},
+	"status":      {expr: "status", value: func(g *models.Game) interface{} { return g.Status }},
+	"progress":    {expr: "progress", value: func(g *models.Game) interface{} { return g.Progress }},
+	"hoursPlayed": {expr: "hours_played", value: func(g *models.Game) interface{} { return g.HoursPlayed }},
+	"score":       {expr: "score", value: func(g *models.Game) interface{} { return g.Score }},
+	"startedAt": {expr: "COALESCE(started_at, '1000-01-01 00:00:00')", isTime: true,
+		value: func(g *models.Game) interface{} { return timeOrNull(g.StartedAt) }},
+	"finishedAt": {expr: "COALESCE(finished_at, '1000-01-01 00:00:00')", isTime: true,
+		value: func(g *models.Game) interface{} { return timeOrNull(g.FinishedAt) }},
+	"createdAt": {expr: "created_at", isTime: true, value: func(g *models.Game) interface{} { return g.CreatedAt }},
+	"updatedAt": {expr: "updated_at", isTime: true, value: func(g *models.Game) interface{} { return g.UpdatedAt }},
+}
+
+type sortKey struct {
+	name   string
+	column sortColumn
+	desc   bool
+}
+
+// gameCursor es lo que viaja (en base64) como nextCursor: los valores de
+// orden del último juego devuelto, su ID y el sort con que se generó.
+type gameCursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+	ID     uint          `json:"id"`
+}
+
+// listGames lista la biblioteca del usuario con filtros combinados, orden y
+// paginación por página o por cursor. Total cuenta todos los juegos que
+// cumplen los filtros, no solo los de la página.
+func listGames(conn *gorm.DB, userID uint, q models.GameListQuery) (models.GamePage, error) {
+	page := models.GamePage{Items: []models.Game{}}
+
+	keys, err := parseSort(q.Sort)
+	if err != nil {
+		return page, err
+	}
+	if err := validateGameFilter(q.Filter); err != nil {
+		return page, err
+	}
+	var cursor *gameCursor
+	if q.Cursor != "" {
+		decoded, err := decodeGameCursor(q.Cursor, keys)
+		if err != nil {
+			return page, err
+		}
+		cursor = &decoded
+	}
+
+	pageSize := q.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	page.PageSize = pageSize
+
+	base := applyGameFilters(conn.Model(&models.Game{}).Where("user_id = ?", userID), q.Filter)
+	if err := base.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return page, err
+	}
+
+	query := base.Session(&gorm.Session{})
+	if cursor != nil {
+		where, args := keysetCondition(keys, *cursor)
+		query = query.Where(where, args...)
+	} else {
+		pageNumber := q.Page
+		if pageNumber <= 0 {
+			pageNumber = 1
+		}
+		page.Page = pageNumber
+		query = query.Offset((pageNumber - 1) * pageSize)
+	}
+
+	var games []models.Game
+	// Pedimos uno de más para saber si hay página siguiente.
+	if err := applySort(query, keys).Limit(pageSize + 1).Find(&games).Error; err != nil {
+		return page, err
+	}
+
+	if len(games) > pageSize {
+		games = games[:pageSize]
+		page.NextCursor = encodeGameCursor(keys, &games[len(games)-1])
+	}
+	page.Items = games
+	return page, nil
+}
+
+// validateGameFilter rechaza filtros que no pueden matchear nada válido.
+func validateGameFilter(f models.GameFilter) error {
+	if f.Status != "" && !models.IsValidGameStatus(f.Status) {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidQuery, f.Status)
+	}
+	return nil
+}
+
+// platformMatch compara con la plataforma del juego o con la de alguna de
+// sus copias; recibe la plataforma dos veces.
+const platformMatch = "platform = ? OR EXISTS (SELECT 1 FROM game_ownerships WHERE game_ownerships.game_id = games.id " +
+	"AND LOWER(game_ownerships.platform) = LOWER(?))"
+
+// findLibraryGame busca el juego del usuario con ese título en platform, como
+// plataforma del juego o de una de sus copias. Sin coincidencias devuelve
+// gorm.ErrRecordNotFound.
+func findLibraryGame(tx *gorm.DB, userID uint, title, platform string, game *models.Game) error {
+	return tx.Where("user_id = ? AND title = ? AND ("+platformMatch+")", userID, title, platform, platform).First(game).Error
+}
+
+// applyGameFilters agrega al query los filtros no vacíos de f.
+func applyGameFilters(tx *gorm.DB, f models.GameFilter) *gorm.DB {
+	if f.Title != "" {
+		tx = tx.Where("title LIKE ?", "%"+f.Title+"%")
+	}
+	if f.Status != "" {
+		tx = tx.Where("status = ?", f.Status)
+	}
+	if f.Genre != "" {
+		tx = tx.Where("genre LIKE ?", "%"+f.Genre+"%")
+	}
+	if f.Platform != "" {
+		tx = tx.Where(platformMatch, f.Platform, f.Platform)
+	}
+	if f.MinScore != nil {
+		tx = tx.Where("score >= ?", *f.MinScore)
+	}
+	if f.MaxScore != nil {
+		tx = tx.Where("score <= ?", *f.MaxScore)
+	}
+	if f.StartedFrom != nil {
+		tx = tx.Where("started_at >= ?", *f.StartedFrom)
+	}
+	if f.StartedTo != nil {
+		tx = tx.Where("started_at < ?", *f.StartedTo)
+	}
+	if f.FinishedFrom != nil {
+		tx = tx.Where("finished_at >= ?", *f.FinishedFrom)
+	}
+	if f.FinishedTo != nil {
+		tx = tx.Where("finished_at < ?", *f.FinishedTo)
+	}
+	// Subconsultas y no JOINs: así los filtros de arriba no se vuelven
+	// ambiguos y cada juego aparece una sola vez.
+	for _, tag := range f.Tags {
+		tx = tx.Where("EXISTS (SELECT 1 FROM game_tags JOIN tags ON tags.id = game_tags.tag_id "+
+			"WHERE game_tags.game_id = games.id AND tags.name = ?)", tag)
+	}
+	if f.CollectionID != 0 {
+		tx = tx.Where("EXISTS (SELECT 1 FROM collection_games "+
+			"WHERE collection_games.game_id = games.id AND collection_games.collection_id = ?)", f.CollectionID)
+	}
+	return tx
+}
+
+// parseSort interpreta "hoursPlayed,-score": un "-" adelante ordena descendente.
+func parseSort(raw string) ([]sortKey, error) {
+	var keys []sortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+		column, ok := sortableColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		keys = append(keys, sortKey{name: name, column: column, desc: desc})
+	}
+	return keys, nil
+}
+
+// applySort ordena por las claves pedidas y desempata siempre por id, así el
+// orden es total y el cursor no saltea ni repite juegos.
+func applySort(tx *gorm.DB, keys []sortKey) *gorm.DB {
+	for _, key := range keys {
+		direction := " ASC"
+		if key.desc {
+			direction = " DESC"
+		}
+		tx = tx.Order(key.column.expr + direction)
+	}
+	return tx.Order("id ASC")
+}
+
+// keysetCondition arma (k1 > v1) OR (k1 = v1 AND k2 < v2) OR ... OR (todas
+// iguales AND id > lastID), respetando la dirección de cada clave.
+func keysetCondition(keys []sortKey, cursor gameCursor) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for i := 0; i <= len(keys); i++ {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].column.expr+" = ?")
+			args = append(args, cursor.Values[j])
+		}
+		if i < len(keys) {
+			op := " > ?"
+			if keys[i].desc {
+				op = " < ?"
+			}
+			parts = append(parts, keys[i].column.expr+op)
+			args = append(args, cursor.Values[i])
+		} else {
+			parts = append(parts, "id > ?")
+			args = append(args, cursor.ID)
+		}
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return strings.Join(clauses, " OR "), args
+}
+
+func encodeGameCursor(keys []sortKey, last *models.Game) string {
+	cursor := gameCursor{Sort: sortSpec(keys), ID: last.ID}
+	for _, key := range keys {
+		value := key.column.value(last)
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339Nano)
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeGameCursor(raw string, keys []sortKey) (gameCursor, error) {
+	var cursor gameCursor
+	invalid := fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, invalid
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, invalid
+	}
+	if cursor.Sort != sortSpec(keys) || len(cursor.Values) != len(keys) {
+		return cursor, fmt.Errorf("%w: cursor does not match sort", ErrInvalidQuery)
+	}
+	for i, key := range keys {
+		if !key.column.isTime {
+			continue
+		}
+		s, ok := cursor.Values[i].(string)
+		if !ok {
+			return cursor, invalid
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return cursor, invalid
+		}
+		cursor.Values[i] = t
+	}
+	return cursor, nil
+}
+
+// sortSpec normaliza las claves a la forma "a,-b".
+func sortSpec(keys []sortKey) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.name
+		if key.desc {
+			parts[i] = "-" + key.name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func timeOrNull(t *time.Time) time.Time {
+	if t == nil {
+		return nullSortTime
+	}
+	return *t
+}
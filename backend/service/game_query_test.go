@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	keys, err := parseSort("hoursPlayed, -score,hoursPlayed")
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "hours_played", keys[0].column.expr)
+	assert.False(t, keys[0].desc)
+	assert.Equal(t, "score", keys[1].column.expr)
+	assert.True(t, keys[1].desc)
+	assert.Equal(t, "hoursPlayed,-score", sortSpec(keys))
+
+	_, err = parseSort("password")
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestListGames_PageAndNextCursor(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `games` WHERE user_id = \\? AND genre LIKE \\? AND `games`.`deleted_at` IS NULL$").
+		WithArgs(uint(1), "%RPG%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	// pageSize 2 + 1 para detectar si hay más
+	mock.ExpectQuery("^SELECT \\* FROM `games` WHERE user_id = \\? AND genre LIKE \\? AND `games`.`deleted_at` IS NULL ORDER BY score DESC,id ASC LIMIT \\?$").
+		WithArgs(uint(1), "%RPG%", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "score"}).
+			AddRow(4, "A", 9).
+			AddRow(2, "B", 8).
+			AddRow(7, "C", 8))
+
+	page, err := NewGameService(NewGameRepository(conn)).List(1, models.GameListQuery{
+		Filter:   models.GameFilter{Genre: "RPG"},
+		Sort:     "-score",
+		PageSize: 2,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Equal(t, 1, page.Page)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, uint(2), page.Items[1].ID)
+	require.NotEmpty(t, page.NextCursor)
+
+	cursor, err := decodeGameCursor(page.NextCursor, []sortKey{{name: "score", column: sortableColumns["score"], desc: true}})
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), cursor.ID)
+	assert.Equal(t, float64(8), cursor.Values[0])
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListGames_Cursor(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	startedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	keys, err := parseSort("-startedAt")
+	require.NoError(t, err)
+	cursor := encodeGameCursor(keys, &models.Game{ID: 5, StartedAt: &startedAt})
+
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL$").
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+	mock.ExpectQuery("^SELECT \\* FROM `games` WHERE user_id = \\? AND \\(\\(COALESCE\\(started_at, '1000-01-01 00:00:00'\\) < \\?\\) OR \\(COALESCE\\(started_at, '1000-01-01 00:00:00'\\) = \\? AND id > \\?\\)\\) AND `games`.`deleted_at` IS NULL ORDER BY COALESCE\\(started_at, '1000-01-01 00:00:00'\\) DESC,id ASC LIMIT \\?$").
+		WithArgs(uint(1), startedAt, startedAt, uint(5), 21).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(6, "Next"))
+
+	page, err := NewGameService(NewGameRepository(conn)).List(1, models.GameListQuery{Sort: "-startedAt", Cursor: cursor})
+
+	require.NoError(t, err)
+	assert.Zero(t, page.Page)
+	require.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListGames_CursorSortMismatch(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	keys, err := parseSort("title")
+	require.NoError(t, err)
+	cursor := encodeGameCursor(keys, &models.Game{ID: 5, Title: "Zelda"})
+
+	_, err = NewGameService(NewGameRepository(conn)).List(1, models.GameListQuery{Sort: "-score", Cursor: cursor})
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+type gameRepository struct {
+	conn *gorm.DB
+}
+
+// NewGameRepository implementa GameRepository sobre conn, que puede ser la
+// conexión o una transacción en curso.
+func NewGameRepository(conn *gorm.DB) GameRepository {
+	return &gameRepository{conn: conn}
+}
+
+func (r *gameRepository) FindByID(userID uint, id string) (models.Game, error) {
+	var game models.Game
+	result := r.conn.Where("user_id = ?", userID).First(&game, id)
+	if result.Error != nil {
+		// No logeamos record not found: es un flujo esperado.
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return game, ErrNotFound
+		}
+		return game, result.Error
+	}
+	return game, nil
+}
+
+func (r *gameRepository) Find(userID uint, filter models.GameFilter) ([]models.Game, error) {
+	var games []models.Game
+	query := applyGameFilters(r.conn.Where("user_id = ?", userID), filter)
+	result := query.Find(&games)
+	return games, result.Error
+}
+
+func (r *gameRepository) List(userID uint, q models.GameListQuery) (models.GamePage, error) {
+	return listGames(r.conn, userID, q)
+}
+
+func (r *gameRepository) Create(game *models.Game) error {
+	return r.conn.Create(game).Error
+}
+
+func (r *gameRepository) Update(game *models.Game, version uint) error {
+	// No usamos Save: si el UPDATE no afecta filas hace un upsert, y eso
+	// permitiría crear/pisar juegos de otro usuario.
+	res := r.conn.Model(game).
+		Where("user_id = ? AND version = ?", game.UserID, version).
+		Select("*").Updates(game)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *gameRepository) Delete(userID uint, id string) error {
+	res := r.conn.Where("user_id = ?", userID).Delete(&models.Game{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gameRepository) HoursDerived(gameID uint) (bool, error) {
+	return hoursDerived(r.conn, gameID)
+}
+
+func (r *gameRepository) AchievementProgress(gameID uint) (int, error) {
+	return achievementProgress(r.conn, gameID)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGameRepository es un GameRepository en memoria para probar las reglas
+// de GameService sin SQL.
+type fakeGameRepository struct {
+	GameRepository
+	created  []models.Game
+	updated  []models.Game
+	version  uint // versión que tiene el juego "en la base"
+	derived  bool
+	progress int
+}
+
+func (f *fakeGameRepository) Create(game *models.Game) error {
+	game.ID = uint(len(f.created) + 1)
+	f.created = append(f.created, *game)
+	return nil
+}
+
+func (f *fakeGameRepository) Update(game *models.Game, version uint) error {
+	if version != f.version {
+		return ErrVersionConflict
+	}
+	f.version = game.Version
+	f.updated = append(f.updated, *game)
+	return nil
+}
+
+func (f *fakeGameRepository) HoursDerived(uint) (bool, error)       { return f.derived, nil }
+func (f *fakeGameRepository) AchievementProgress(uint) (int, error) { return f.progress, nil }
+
+func TestGameService_CreateAppliesLifecycle(t *testing.T) {
+	t.Parallel()
+	repo := &fakeGameRepository{}
+	games := NewGameService(repo)
+
+	game := &models.Game{UserID: 1, Title: "Celeste", Platform: "PC", Status: models.StatusCompleted, Progress: 30}
+	require.NoError(t, games.Create(game))
+
+	require.Len(t, repo.created, 1)
+	assert.Equal(t, uint(1), repo.created[0].Version)
+	assert.Equal(t, 100, repo.created[0].Progress)
+	assert.NotNil(t, repo.created[0].FinishedAt)
+}
+
+func TestGameService_CreateRejectsInvalidStatus(t *testing.T) {
+	t.Parallel()
+	repo := &fakeGameRepository{}
+
+	err := NewGameService(repo).Create(&models.Game{Title: "Celeste", Status: "Finished"})
+
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+	assert.Empty(t, repo.created)
+}
+
+func TestGameService_UpdateDerivesHoursAndProgress(t *testing.T) {
+	t.Parallel()
+	repo := &fakeGameRepository{version: 3, derived: true, progress: 75}
+	games := NewGameService(repo)
+	previous := models.Game{ID: 5, Status: models.StatusPlaying, HoursPlayed: 12, Version: 3}
+
+	game := &models.Game{ID: 5, Title: "Hades", Status: models.StatusPlaying, HoursPlayed: 99, Progress: 10, ProgressFromAchievements: true}
+	require.NoError(t, games.Update(1, previous, game))
+
+	assert.Equal(t, 12.0, game.HoursPlayed)
+	assert.Equal(t, 75, game.Progress)
+	assert.Equal(t, uint(1), game.UserID)
+	assert.Equal(t, uint(4), game.Version)
+}
+
+func TestGameService_CompletedWinsOverAchievementProgress(t *testing.T) {
+	t.Parallel()
+	repo := &fakeGameRepository{version: 3, progress: 40}
+	games := NewGameService(repo)
+	previous := models.Game{ID: 5, Status: models.StatusPlaying, Progress: 40, Version: 3}
+
+	game := &models.Game{ID: 5, Title: "Hades", Status: models.StatusCompleted, Progress: 40, ProgressFromAchievements: true}
+	require.NoError(t, games.Update(1, previous, game))
+	assert.Equal(t, 100, game.Progress)
+
+	created := &models.Game{Title: "Celeste", Status: models.StatusCompleted, ProgressFromAchievements: true}
+	require.NoError(t, games.Create(created))
+	assert.Equal(t, 100, created.Progress)
+}
+
+func TestGameService_UpdateConflictKeepsVersion(t *testing.T) {
+	t.Parallel()
+	repo := &fakeGameRepository{version: 4}
+	games := NewGameService(repo)
+
+	game := &models.Game{ID: 5, Title: "Hades", Status: models.StatusPlaying}
+	err := games.Update(1, models.Game{ID: 5, Status: models.StatusPlaying, Version: 3}, game)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Equal(t, uint(3), game.Version)
+	assert.Empty(t, repo.updated)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"gametracker/clock"
+	"gametracker/models"
+)
+
+var (
+	// ErrInvalidStatus se devuelve cuando Status no es uno de models.GameStatuses.
+	ErrInvalidStatus = errors.New("invalid status")
+	// ErrInvalidStatusTransition se devuelve cuando el cambio de estado no está permitido.
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+)
+
+// applyStatusLifecycle valida el estado de game y, si cambió respecto de
+// previousStatus, completa las fechas del ciclo de vida:
+//
+//   - al pasar a Playing se estampa StartedAt (si no tenía)
+//   - al pasar a Completed se estampa FinishedAt (si no tenía), StartedAt
+//     si faltaba, y Progress queda en 100
+//
+// previousStatus vacío significa que el juego se está creando. Un estado
+// anterior inválido (datos previos al enum) puede pasar a cualquier estado.
+func applyStatusLifecycle(game *models.Game, previousStatus string) error {
+	if game.Status == "" {
+		game.Status = models.DefaultGameStatus
+	}
+	if !models.IsValidGameStatus(game.Status) {
+		return fmt.Errorf("%w: %q (valid: %v)", ErrInvalidStatus, game.Status, models.GameStatuses)
+	}
+
+	if previousStatus == game.Status {
+		return nil
+	}
+	if models.IsValidGameStatus(previousStatus) && !models.CanTransitionStatus(previousStatus, game.Status) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, previousStatus, game.Status)
+	}
+
+	stamp := clock.Now()
+	switch game.Status {
+	case models.StatusPlaying:
+		if game.StartedAt == nil {
+			game.StartedAt = &stamp
+		}
+	case models.StatusCompleted:
+		if game.FinishedAt == nil {
+			game.FinishedAt = &stamp
+		}
+		if game.StartedAt == nil {
+			startedAt := *game.FinishedAt
+			game.StartedAt = &startedAt
+		}
+		game.Progress = 100
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixNow(t *testing.T, fixed time.Time) {
+	t.Helper()
+	prev := clock.Now
+	clock.Now = func() time.Time { return fixed }
+	t.Cleanup(func() { clock.Now = prev })
+}
+
+func TestApplyStatusLifecycle_DefaultsToBacklog(t *testing.T) {
+	game := &models.Game{Title: "New"}
+
+	require.NoError(t, applyStatusLifecycle(game, ""))
+
+	assert.Equal(t, models.StatusBacklog, game.Status)
+	assert.Nil(t, game.StartedAt)
+	assert.Nil(t, game.FinishedAt)
+}
+
+func TestApplyStatusLifecycle_InvalidStatus(t *testing.T) {
+	for _, status := range []string{"playing", "Replaying", "In Progress"} {
+		err := applyStatusLifecycle(&models.Game{Status: status}, "")
+		assert.ErrorIs(t, err, ErrInvalidStatus, status)
+	}
+}
+
+func TestApplyStatusLifecycle_StartPlaying(t *testing.T) {
+	stamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fixNow(t, stamp)
+
+	game := &models.Game{Status: models.StatusPlaying}
+	require.NoError(t, applyStatusLifecycle(game, models.StatusBacklog))
+
+	require.NotNil(t, game.StartedAt)
+	assert.Equal(t, stamp, *game.StartedAt)
+	assert.Nil(t, game.FinishedAt)
+}
+
+func TestApplyStatusLifecycle_Complete(t *testing.T) {
+	stamp := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fixNow(t, stamp)
+	startedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	game := &models.Game{Status: models.StatusCompleted, Progress: 80, StartedAt: &startedAt}
+	require.NoError(t, applyStatusLifecycle(game, models.StatusPlaying))
+
+	assert.Equal(t, 100, game.Progress)
+	assert.Equal(t, startedAt, *game.StartedAt)
+	require.NotNil(t, game.FinishedAt)
+	assert.Equal(t, stamp, *game.FinishedAt)
+}
+
+func TestApplyStatusLifecycle_CompleteKeepsExplicitDates(t *testing.T) {
+	finishedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	game := &models.Game{Status: models.StatusCompleted, FinishedAt: &finishedAt}
+	require.NoError(t, applyStatusLifecycle(game, ""))
+
+	assert.Equal(t, finishedAt, *game.FinishedAt)
+	assert.Equal(t, finishedAt, *game.StartedAt)
+}
+
+func TestApplyStatusLifecycle_Transitions(t *testing.T) {
+	assert.NoError(t, applyStatusLifecycle(&models.Game{Status: models.StatusPlaying}, models.StatusCompleted))
+	assert.NoError(t, applyStatusLifecycle(&models.Game{Status: models.StatusDropped}, models.StatusDropped))
+	// Estados anteriores al enum pueden migrar a cualquiera válido
+	assert.NoError(t, applyStatusLifecycle(&models.Game{Status: models.StatusPaused}, "In Progress"))
+
+	err := applyStatusLifecycle(&models.Game{Status: models.StatusWishlist}, models.StatusCompleted)
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+// errDryRun fuerza el rollback de la transacción de una importación de prueba.
+var errDryRun = errors.New("dry run")
+
+// maxWishlistNote es el largo de WishlistItem.Note.
+const maxWishlistNote = 500
+
+// TransferService exporta e importa la biblioteca del usuario.
+type TransferService struct {
+	conn *gorm.DB
+}
+
+func NewTransferService(conn *gorm.DB) *TransferService {
+	return &TransferService{conn: conn}
+}
+
+// ExportGames recorre la biblioteca del usuario (sin la papelera) ordenada
+// por ID y llama a fn con cada juego. Lee fila por fila para no cargar toda
+// la biblioteca en memoria.
+func (s *TransferService) ExportGames(userID uint, fn func(models.Game) error) error {
+	rows, err := s.conn.Model(&models.Game{}).Where("user_id = ?", userID).Order("id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var game models.Game
+		if err := s.conn.ScanRows(rows, &game); err != nil {
+			return err
+		}
+		if err := fn(game); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ImportGames aplica las filas en una transacción y devuelve qué pasó con
+// cada una. Los duplicados se detectan por (Title, Platform) dentro del mismo
+// archivo, y contra la biblioteca también por la plataforma de las copias: un
+// juego cargado en PC del que se tiene una copia en Switch ya está. Las
+// filas Wishlist de los importadores van a la lista de deseos.
+// Una fila con errores no frena al resto: cada una se guarda en su propio
+// savepoint, porque en PostgreSQL un error deja abortada la transacción
+// entera hasta volver a un savepoint. En DryRun se hace todo el trabajo y al
+// final se descarta, así el reporte es el mismo que el real.
+func (s *TransferService) ImportGames(userID uint, records []models.ImportRecord, opts models.ImportOptions) (models.ImportReport, error) {
+	report := models.ImportReport{DryRun: opts.DryRun, Rows: []models.ImportRow{}}
+	err := s.conn.Transaction(func(tx *gorm.DB) error {
+		seen := map[string]int{}
+		for _, rec := range records {
+			report.Add(importRecord(tx, userID, rec, opts, seen))
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return models.ImportReport{}, err
+	}
+	return report, nil
+}
+
+// importRecord guarda una fila; seen guarda la primera línea de cada
+// (Title, Platform) ya visto en el archivo.
+func importRecord(tx *gorm.DB, userID uint, rec models.ImportRecord, opts models.ImportOptions, seen map[string]int) models.ImportRow {
+	row := models.ImportRow{Line: rec.Line, Title: rec.Input.Title, Platform: rec.Input.Platform}
+	if len(rec.Errors) > 0 {
+		row.Action = models.ImportError
+		row.Errors = rec.Errors
+		return row
+	}
+
+	key := strings.ToLower(strings.TrimSpace(rec.Input.Title)) + "\x00" + strings.ToLower(strings.TrimSpace(rec.Input.Platform))
+	if line, ok := seen[key]; ok {
+		row.Action = models.ImportSkipped
+		row.Message = fmt.Sprintf("duplicate of line %d", line)
+		return row
+	}
+	seen[key] = rec.Line
+
+	// tx.Transaction anidado usa SAVEPOINT / ROLLBACK TO SAVEPOINT.
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if rec.Wishlist {
+			return saveImportWishlistItem(tx, userID, rec, &row)
+		}
+		return saveImportRecord(tx, userID, rec, opts, &row)
+	})
+	if err != nil {
+		return importFailed(row, err)
+	}
+	return row
+}
+
+// saveImportRecord crea o actualiza el juego de la fila y completa row. Un
+// error revierte lo que haya hecho la fila.
+func saveImportRecord(tx *gorm.DB, userID uint, rec models.ImportRecord, opts models.ImportOptions, row *models.ImportRow) error {
+	var existing models.Game
+	err := findLibraryGame(tx, userID, rec.Input.Title, rec.Input.Platform, &existing)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		game := models.Game{UserID: userID}
+		rec.Input.Apply(&game)
+		if err := createGame(tx, &game); err != nil {
+			return err
+		}
+		row.Action = models.ImportCreated
+		row.GameID = game.ID
+	case err != nil:
+		return err
+	case !opts.UpdateExisting:
+		row.Action = models.ImportSkipped
+		row.GameID = existing.ID
+		row.Message = "game already exists"
+	default:
+		// Solo se pisan las columnas que trae el archivo: las que faltan
+		// conservan su valor en vez de quedar en cero.
+		game := existing
+		rec.Input.ApplyFields(&game, rec.Fields)
+		if err := updateGame(tx, userID, existing, &game); err != nil {
+			return err
+		}
+		row.Action = models.ImportUpdated
+		row.GameID = game.ID
+	}
+	return nil
+}
+
+// saveImportWishlistItem guarda en la lista de deseos una fila marcada como
+// Wishlist. Si el juego ya está en la biblioteca o en la lista, la fila se
+// saltea: un deseo no pisa nada.
+func saveImportWishlistItem(tx *gorm.DB, userID uint, rec models.ImportRecord, row *models.ImportRow) error {
+	title, platform := rec.Input.Title, rec.Input.Platform
+	var owned models.Game
+	err := findLibraryGame(tx, userID, title, platform, &owned)
+	switch {
+	case err == nil:
+		row.Action = models.ImportSkipped
+		row.GameID = owned.ID
+		row.Message = "game already in library"
+		return nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	var existing models.WishlistItem
+	err = tx.Where("user_id = ? AND title = ? AND platform = ?", userID, title, platform).First(&existing).Error
+	switch {
+	case err == nil:
+		row.Action = models.ImportSkipped
+		row.WishlistItemID = existing.ID
+		row.Message = "game already in wishlist"
+		return nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	note := []rune(rec.Input.PersonalNote)
+	if len(note) > maxWishlistNote {
+		note = note[:maxWishlistNote]
+	}
+	item := models.WishlistItem{UserID: userID, Title: title, Platform: platform, Priority: models.DefaultWishlistPriority, Note: string(note)}
+	if err := tx.Create(&item).Error; err != nil {
+		return err
+	}
+	row.Action = models.ImportWishlisted
+	row.WishlistItemID = item.ID
+	return nil
+}
+
+// importFailed marca la fila como error. Los errores de estado se muestran
+// tal cual; los de base no, para no filtrar detalles del motor.
+func importFailed(row models.ImportRow, err error) models.ImportRow {
+	row.Action = models.ImportError
+	if errors.Is(err, ErrInvalidStatus) || errors.Is(err, ErrInvalidStatusTransition) {
+		row.Errors = []models.FieldError{{Field: "status", Message: err.Error()}}
+	} else {
+		row.Message = "could not save game"
+	}
+	return row
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const findByTitlePlatform = "SELECT \\* FROM `games` WHERE \\(user_id = \\? AND title = \\? AND \\(platform = \\? OR EXISTS .*\\)\\) AND `games`.`deleted_at` IS NULL"
+
+// expectSavepoint espera el savepoint con el que empieza cada fila que llega
+// a la base.
+func expectSavepoint(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("^SAVEPOINT sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestExportGames(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	mock.ExpectQuery("^SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL ORDER BY id ASC$").
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform"}).
+			AddRow(1, 1, "Hades", "PC").
+			AddRow(2, 1, "Celeste", "Switch"))
+
+	// Act
+	var titles []string
+	err := NewTransferService(conn).ExportGames(1, func(g models.Game) error {
+		titles = append(titles, g.Title)
+		return nil
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hades", "Celeste"}, titles)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportGames_Report(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	records := []models.ImportRecord{
+		{Line: 2, Input: models.GameInput{Title: "Hades", Platform: "PC"}},
+		{Line: 3, Input: models.GameInput{Title: "Celeste", Platform: "Switch"}},
+		{Line: 4, Input: models.GameInput{Title: "hades", Platform: "pc"}},
+		{Line: 5, Input: models.GameInput{Title: "Bad"}, Errors: []models.FieldError{{Field: "platform", Message: "is required"}}},
+	}
+
+	mock.ExpectBegin()
+	// Hades no existe: se crea
+	expectSavepoint(mock)
+	mock.ExpectQuery(findByTitlePlatform).
+		WithArgs(uint(1), "Hades", "PC", "PC", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO `games`").WillReturnResult(sqlmock.NewResult(10, 1))
+	// Celeste ya existe: se saltea
+	expectSavepoint(mock)
+	mock.ExpectQuery(findByTitlePlatform).
+		WithArgs(uint(1), "Celeste", "Switch", "Switch", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform"}).AddRow(7, 1, "Celeste", "Switch"))
+	mock.ExpectCommit()
+
+	// Act
+	report, err := NewTransferService(conn).ImportGames(1, records, models.ImportOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 1, report.Errored)
+	require.Len(t, report.Rows, 4)
+	assert.Equal(t, models.ImportRow{Line: 2, Title: "Hades", Platform: "PC", Action: models.ImportCreated, GameID: 10}, report.Rows[0])
+	assert.Equal(t, uint(7), report.Rows[1].GameID)
+	assert.Equal(t, "game already exists", report.Rows[1].Message)
+	assert.Equal(t, "duplicate of line 2", report.Rows[2].Message)
+	assert.Equal(t, models.ImportError, report.Rows[3].Action)
+	assert.Equal(t, "platform", report.Rows[3].Errors[0].Field)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportGames_UpdateExisting(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	records := []models.ImportRecord{
+		{Line: 1, Input: models.GameInput{Title: "Celeste", Platform: "Switch", Status: models.StatusPlaying, HoursPlayed: 12}},
+		{Line: 2, Input: models.GameInput{Title: "Hades", Platform: "PC", Status: models.StatusBacklog}},
+	}
+
+	mock.ExpectBegin()
+	expectSavepoint(mock)
+	mock.ExpectQuery(findByTitlePlatform).
+		WithArgs(uint(1), "Celeste", "Switch", "Switch", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform", "status", "version"}).
+			AddRow(7, 1, "Celeste", "Switch", models.StatusBacklog, 2))
+	expectHoursDerived(mock, 0)
+	mock.ExpectExec("UPDATE `games` SET .* WHERE \\(user_id = \\? AND version = \\?\\)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Completed -> Backlog no es una transición válida: se vuelve al
+	// savepoint y el resto de la importación sigue.
+	expectSavepoint(mock)
+	mock.ExpectQuery(findByTitlePlatform).
+		WithArgs(uint(1), "Hades", "PC", "PC", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "platform", "status", "version"}).
+			AddRow(8, 1, "Hades", "PC", models.StatusCompleted, 1))
+	mock.ExpectExec("^ROLLBACK TO SAVEPOINT sp\\d+$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	report, err := NewTransferService(conn).ImportGames(1, records, models.ImportOptions{UpdateExisting: true})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Errored)
+	assert.Equal(t, uint(7), report.Rows[0].GameID)
+	assert.Equal(t, "status", report.Rows[1].Errors[0].Field)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportGames_DryRunRollsBack(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	mock.ExpectBegin()
+	expectSavepoint(mock)
+	mock.ExpectQuery(findByTitlePlatform).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO `games`").WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectRollback()
+
+	// Act
+	records := []models.ImportRecord{{Line: 2, Input: models.GameInput{Title: "Hades", Platform: "PC"}}}
+	report, err := NewTransferService(conn).ImportGames(1, records, models.ImportOptions{DryRun: true})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLite_ImportUpdateKeepsMissingColumns(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	game := createSQLiteGame(t, conn, models.Game{
+		Title: "Hades", Platform: "PC", Genre: "Roguelike", Status: models.StatusPlaying,
+		HoursPlayed: 10, Score: 9, PersonalNote: "Mejor run: Zagreus con lanza",
+	})
+	records := []models.ImportRecord{{
+		Line:   2,
+		Input:  models.GameInput{Title: "Hades", Platform: "PC", HoursPlayed: 25},
+		Fields: map[string]bool{"title": true, "platform": true, "hoursPlayed": true},
+	}}
+
+	report, err := NewTransferService(conn).ImportGames(1, records, models.ImportOptions{UpdateExisting: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Updated)
+	updated, err := NewGameService(NewGameRepository(conn)).Get(1, fmt.Sprint(game.ID))
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, updated.HoursPlayed)
+	assert.Equal(t, "Roguelike", updated.Genre)
+	assert.Equal(t, models.StatusPlaying, updated.Status)
+	assert.Equal(t, 9, updated.Score)
+	assert.Equal(t, "Mejor run: Zagreus con lanza", updated.PersonalNote)
+}
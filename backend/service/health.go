@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"gametracker/db"
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+// HealthService dice si el backend puede atender requests: la base responde
+// y su esquema tiene todas las migraciones del binario.
+type HealthService struct {
+	conn *gorm.DB
+}
+
+func NewHealthService(conn *gorm.DB) *HealthService {
+	return &HealthService{conn: conn}
+}
+
+// Ready corre los chequeos con el deadline de ctx. Si la base no responde no
+// se revisan las migraciones. Los errores se loguean y la respuesta solo dice
+// qué chequeo falló: /readyz no tiene autenticación.
+func (s *HealthService) Ready(ctx context.Context) models.Readiness {
+	readiness := models.Readiness{Status: models.ReadinessOK, Checks: map[string]string{}}
+	fail := func(check, result string, err error) {
+		log.Printf("readyz %s: %v", check, err)
+		readiness.Status = models.ReadinessUnavailable
+		readiness.Checks[check] = result
+	}
+
+	sqlDB, err := s.conn.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		fail("database", models.ReadinessCheckUnreachable, err)
+		return readiness
+	}
+	readiness.Checks["database"] = models.ReadinessOK
+
+	migrator, err := db.NewMigrator(s.conn.WithContext(ctx))
+	if err == nil {
+		err = migrator.Check()
+	}
+	if errors.Is(err, db.ErrSchemaBehind) {
+		fail("migrations", models.ReadinessCheckPending, err)
+		return readiness
+	}
+	if err != nil {
+		fail("migrations", models.ReadinessCheckFailed, err)
+		return readiness
+	}
+	readiness.Checks["migrations"] = models.ReadinessOK
+	return readiness
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clave de desarrollo: solo se usa si no hay ninguna clave configurada y
+// GIN_MODE no es release.
+const devJWTSecret = "gametracker_secret_key_2024"
+
+const (
+	defaultJWTIssuer     = "gametracker"
+	defaultJWTAudience   = "gametracker-api"
+	defaultJWTAccessTTL  = 15 * time.Minute
+	defaultJWTRefreshTTL = 30 * 24 * time.Hour
+)
+
+var devKeyWarning sync.Once
+
+// JWTKey es una clave de firma identificada por su kid.
+type JWTKey struct {
+	ID     string
+	Secret []byte
+}
+
+// JWTConfig agrupa todo lo necesario para emitir y validar tokens.
+// Keys contiene todas las claves aceptadas al validar; ActiveKeyID es la que
+// se usa para firmar. Durante una rotación se agrega la clave nueva, se la
+// marca activa y la vieja se quita cuando vencen los tokens que firmó.
+type JWTConfig struct {
+	Keys        []JWTKey
+	ActiveKeyID string
+	Issuer      string
+	Audience    string
+	AccessTTL   time.Duration
+	RefreshTTL  time.Duration
+}
+
+// LoadJWTConfig lee la configuración JWT de las variables de entorno:
+//
+//	JWT_KEYS       lista "kid:secreto" separada por comas
+//	JWT_SECRET     atajo para una única clave (kid "default")
+//	JWT_ACTIVE_KID kid usado para firmar (por defecto la primera clave)
+//	JWT_ISSUER, JWT_AUDIENCE
+//	JWT_ACCESS_TTL  duración de Go, ej. "15m"
+//	JWT_REFRESH_TTL duración de Go, ej. "720h"
+//
+// Sin claves usa la de desarrollo, salvo con GIN_MODE=release, donde es un
+// error: la clave de desarrollo está en el código y cualquiera podría firmar
+// tokens.
+func LoadJWTConfig() (JWTConfig, error) {
+	cfg := JWTConfig{
+		ActiveKeyID: strings.TrimSpace(os.Getenv("JWT_ACTIVE_KID")),
+		Issuer:      envOrDefault("JWT_ISSUER", defaultJWTIssuer),
+		Audience:    envOrDefault("JWT_AUDIENCE", defaultJWTAudience),
+		AccessTTL:   defaultJWTAccessTTL,
+		RefreshTTL:  defaultJWTRefreshTTL,
+	}
+
+	if raw := os.Getenv("JWT_KEYS"); raw != "" {
+		keys, err := parseJWTKeys(raw)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Keys = keys
+	} else if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		cfg.Keys = []JWTKey{{ID: "default", Secret: []byte(secret)}}
+	}
+
+	var err error
+	if cfg.AccessTTL, err = durationFromEnv("JWT_ACCESS_TTL", cfg.AccessTTL); err != nil {
+		return cfg, err
+	}
+	if cfg.RefreshTTL, err = durationFromEnv("JWT_REFRESH_TTL", cfg.RefreshTTL); err != nil {
+		return cfg, err
+	}
+
+	if len(cfg.Keys) == 0 {
+		if os.Getenv("GIN_MODE") == "release" {
+			return cfg, errors.New("JWT_KEYS o JWT_SECRET son obligatorios con GIN_MODE=release")
+		}
+		devKeyWarning.Do(func() {
+			log.Println("ADVERTENCIA: JWT_KEYS/JWT_SECRET no configurados, usando clave de desarrollo")
+		})
+		cfg.Keys = []JWTKey{{ID: "dev", Secret: []byte(devJWTSecret)}}
+	}
+	if cfg.ActiveKeyID == "" {
+		cfg.ActiveKeyID = cfg.Keys[0].ID
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate verifica que la clave activa exista y que no haya kids repetidos.
+func (cfg JWTConfig) Validate() error {
+	if len(cfg.Keys) == 0 {
+		return errors.New("no hay claves JWT configuradas")
+	}
+	seen := make(map[string]bool, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		if key.ID == "" || len(key.Secret) == 0 {
+			return errors.New("clave JWT sin kid o sin secreto")
+		}
+		if seen[key.ID] {
+			return errors.New("kid JWT repetido: " + key.ID)
+		}
+		seen[key.ID] = true
+	}
+	if !seen[cfg.ActiveKeyID] {
+		return errors.New("JWT_ACTIVE_KID no corresponde a ninguna clave: " + cfg.ActiveKeyID)
+	}
+	if cfg.AccessTTL <= 0 || cfg.RefreshTTL <= 0 {
+		return errors.New("el TTL de los tokens debe ser positivo")
+	}
+	return nil
+}
+
+func (cfg JWTConfig) key(kid string) ([]byte, bool) {
+	for _, key := range cfg.Keys {
+		if key.ID == kid {
+			return key.Secret, true
+		}
+	}
+	return nil, false
+}
+
+func parseJWTKeys(raw string) ([]JWTKey, error) {
+	var keys []JWTKey
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("JWT_KEYS debe tener el formato kid:secreto")
+		}
+		keys = append(keys, JWTKey{ID: strings.TrimSpace(kid), Secret: []byte(strings.TrimSpace(secret))})
+	}
+	return keys, nil
+}
+
+func durationFromEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return defaultValue, errors.New(key + " inválido: " + raw)
+	}
+	return d, nil
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
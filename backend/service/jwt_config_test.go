@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadJWTConfig_Defaults(t *testing.T) {
+	t.Setenv("GIN_MODE", "")
+	t.Setenv("JWT_KEYS", "")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_ACTIVE_KID", "")
+	t.Setenv("JWT_ACCESS_TTL", "")
+
+	cfg, err := LoadJWTConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, "dev", cfg.ActiveKeyID)
+	assert.Equal(t, defaultJWTIssuer, cfg.Issuer)
+	assert.Equal(t, defaultJWTAudience, cfg.Audience)
+	assert.Equal(t, defaultJWTAccessTTL, cfg.AccessTTL)
+}
+
+func TestLoadJWTConfig_ReleaseRequiresKeys(t *testing.T) {
+	t.Setenv("GIN_MODE", "release")
+	t.Setenv("JWT_KEYS", "")
+	t.Setenv("JWT_SECRET", "")
+
+	_, err := LoadJWTConfig()
+
+	assert.ErrorContains(t, err, "GIN_MODE=release")
+}
+
+func TestLoadJWTConfig_MultipleKeys(t *testing.T) {
+	t.Setenv("JWT_KEYS", "2024-01:old-secret, 2024-06:new-secret")
+	t.Setenv("JWT_ACTIVE_KID", "2024-06")
+	t.Setenv("JWT_ISSUER", "gametracker-qa")
+	t.Setenv("JWT_AUDIENCE", "gametracker-qa-api")
+	t.Setenv("JWT_ACCESS_TTL", "15m")
+
+	cfg, err := LoadJWTConfig()
+
+	require.NoError(t, err)
+	require.Len(t, cfg.Keys, 2)
+	assert.Equal(t, "2024-01", cfg.Keys[0].ID)
+	assert.Equal(t, []byte("new-secret"), cfg.Keys[1].Secret)
+	assert.Equal(t, "2024-06", cfg.ActiveKeyID)
+	assert.Equal(t, "gametracker-qa", cfg.Issuer)
+	assert.Equal(t, "gametracker-qa-api", cfg.Audience)
+	assert.Equal(t, 15*time.Minute, cfg.AccessTTL)
+}
+
+func TestLoadJWTConfig_SingleSecret(t *testing.T) {
+	t.Setenv("JWT_KEYS", "")
+	t.Setenv("JWT_SECRET", "super-secret")
+	t.Setenv("JWT_ACTIVE_KID", "")
+
+	cfg, err := LoadJWTConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, "default", cfg.ActiveKeyID)
+	assert.Equal(t, []byte("super-secret"), cfg.Keys[0].Secret)
+}
+
+func TestLoadJWTConfig_Invalid(t *testing.T) {
+	t.Run("unknown active kid", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "k1:secret")
+		t.Setenv("JWT_ACTIVE_KID", "k2")
+		_, err := LoadJWTConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed keys", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "no-colon")
+		_, err := LoadJWTConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicated kid", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "k1:a,k1:b")
+		t.Setenv("JWT_ACTIVE_KID", "")
+		_, err := LoadJWTConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("bad ttl", func(t *testing.T) {
+		t.Setenv("JWT_KEYS", "k1:a")
+		t.Setenv("JWT_ACTIVE_KID", "")
+		t.Setenv("JWT_ACCESS_TTL", "forever")
+		_, err := LoadJWTConfig()
+		assert.Error(t, err)
+	})
+}
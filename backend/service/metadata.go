@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/metadata"
+	"gametracker/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultMetadataCacheTTL = 30 * 24 * time.Hour
+	DefaultMetadataTimeout  = 10 * time.Second
+
+	metadataSearch = "search"
+	metadataGame   = "game"
+)
+
+var (
+	// ErrMetadataDisabled se devuelve si no hay proveedor configurado.
+	ErrMetadataDisabled = errors.New("metadata provider not configured")
+	// ErrNoMetadataMatch se devuelve si el proveedor no conoce el juego.
+	ErrNoMetadataMatch = errors.New("no metadata found for game")
+	// ErrMetadataUnavailable envuelve las fallas del proveedor (red, status).
+	ErrMetadataUnavailable = errors.New("metadata provider unavailable")
+)
+
+// MetadataConfig elige el proveedor de metadatos. Con Provider vacío el
+// enriquecimiento queda deshabilitado.
+type MetadataConfig struct {
+	Provider string
+	BaseURL  string
+	APIKey   string
+	Timeout  time.Duration
+	CacheTTL time.Duration
+}
+
+// LoadMetadataConfig lee la configuración del proveedor de metadatos:
+//
+//	METADATA_PROVIDER  "rawg" o vacío (deshabilitado)
+//	METADATA_API_URL   base de la API (por defecto la de RAWG)
+//	METADATA_API_KEY
+//	METADATA_TIMEOUT   duración de Go, ej. "10s"
+//	METADATA_CACHE_TTL cuánto se reutiliza una respuesta, ej. "720h"
+func LoadMetadataConfig() (MetadataConfig, error) {
+	cfg := MetadataConfig{
+		Provider: strings.ToLower(strings.TrimSpace(os.Getenv("METADATA_PROVIDER"))),
+		BaseURL:  os.Getenv("METADATA_API_URL"),
+		APIKey:   os.Getenv("METADATA_API_KEY"),
+	}
+	var err error
+	if cfg.Timeout, err = durationFromEnv("METADATA_TIMEOUT", DefaultMetadataTimeout); err != nil {
+		return cfg, err
+	}
+	if cfg.CacheTTL, err = durationFromEnv("METADATA_CACHE_TTL", DefaultMetadataCacheTTL); err != nil {
+		return cfg, err
+	}
+	if cfg.Provider != "" && cfg.Provider != "rawg" {
+		return cfg, errors.New("METADATA_PROVIDER inválido: " + cfg.Provider)
+	}
+	return cfg, nil
+}
+
+// NewMetadataProvider arma el proveedor descripto por cfg, o nil si está
+// deshabilitado.
+func NewMetadataProvider(cfg MetadataConfig) metadata.Provider {
+	if cfg.Provider == "" {
+		log.Println("Proveedor de metadatos deshabilitado (METADATA_PROVIDER vacío)")
+		return nil
+	}
+	return metadata.NewHTTPProvider(cfg.BaseURL, cfg.APIKey, cfg.Timeout)
+}
+
+// MetadataService completa juegos con la ficha del proveedor de metadatos y
+// guarda sus respuestas en metadata_cache durante cacheTTL. Con provider nil
+// el enriquecimiento queda deshabilitado (ErrMetadataDisabled).
+type MetadataService struct {
+	conn     *gorm.DB
+	games    *GameService
+	provider metadata.Provider
+	cacheTTL time.Duration
+}
+
+func NewMetadataService(conn *gorm.DB, provider metadata.Provider, cacheTTL time.Duration) *MetadataService {
+	return &MetadataService{
+		conn:     conn,
+		games:    NewGameService(NewGameRepository(conn)),
+		provider: provider,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// EnrichGame completa los campos vacíos de game (género, portada, fecha de
+// salida y desarrollador) con la ficha del proveedor. Sin externalID busca
+// por título y usa el resultado con el mismo título o, si no hay, el
+// primero. Si no hay nada que completar el juego no se modifica.
+func (s *MetadataService) EnrichGame(ctx context.Context, userID uint, game models.Game, externalID string) (models.GameEnrichment, error) {
+	result := models.GameEnrichment{Game: game, Filled: []string{}}
+	p := s.provider
+	if p == nil {
+		return result, ErrMetadataDisabled
+	}
+
+	if externalID == "" {
+		matches, err := s.searchMetadata(ctx, p, game.Title)
+		if err != nil {
+			return result, err
+		}
+		match, ok := bestMetadataMatch(game.Title, matches)
+		if !ok {
+			return result, ErrNoMetadataMatch
+		}
+		externalID = match.ExternalID
+	}
+	meta, err := s.getMetadata(ctx, p, externalID)
+	if err != nil {
+		return result, err
+	}
+	result.Metadata = meta
+
+	updated := game
+	if updated.Genre == "" && len(meta.Genres) > 0 && len(meta.Genres[0]) <= 80 {
+		updated.Genre = meta.Genres[0]
+		result.Filled = append(result.Filled, "genre")
+	}
+	if updated.CoverURL == "" && meta.CoverURL != "" && len(meta.CoverURL) <= 500 {
+		updated.CoverURL = meta.CoverURL
+		result.Filled = append(result.Filled, "coverURL")
+	}
+	if updated.ReleaseDate == nil && meta.ReleaseDate != nil {
+		updated.ReleaseDate = meta.ReleaseDate
+		result.Filled = append(result.Filled, "releaseDate")
+	}
+	if updated.Developer == "" && meta.Developer != "" && len(meta.Developer) <= 120 {
+		updated.Developer = meta.Developer
+		result.Filled = append(result.Filled, "developer")
+	}
+	if len(result.Filled) == 0 {
+		return result, nil
+	}
+	if err := s.games.Update(userID, game, &updated); err != nil {
+		return result, err
+	}
+	result.Game = updated
+	return result, nil
+}
+
+func bestMetadataMatch(title string, matches []models.GameMetadata) (models.GameMetadata, bool) {
+	for _, m := range matches {
+		if strings.EqualFold(strings.TrimSpace(m.Title), strings.TrimSpace(title)) {
+			return m, true
+		}
+	}
+	if len(matches) == 0 {
+		return models.GameMetadata{}, false
+	}
+	return matches[0], true
+}
+
+// searchMetadata busca por título pasando por la caché.
+func (s *MetadataService) searchMetadata(ctx context.Context, p metadata.Provider, title string) ([]models.GameMetadata, error) {
+	key := strings.ToLower(strings.TrimSpace(title))
+	var matches []models.GameMetadata
+	if s.readMetadataCache(p.Name(), metadataSearch, key, &matches) {
+		return matches, nil
+	}
+	matches, err := p.Search(ctx, title)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMetadataUnavailable, err)
+	}
+	s.writeMetadataCache(p.Name(), metadataSearch, key, matches)
+	return matches, nil
+}
+
+// getMetadata trae la ficha de externalID pasando por la caché. Los "no
+// encontrado" no se guardan: el proveedor puede agregar el juego después.
+func (s *MetadataService) getMetadata(ctx context.Context, p metadata.Provider, externalID string) (models.GameMetadata, error) {
+	var meta models.GameMetadata
+	if s.readMetadataCache(p.Name(), metadataGame, externalID, &meta) {
+		return meta, nil
+	}
+	meta, err := p.Get(ctx, externalID)
+	if errors.Is(err, metadata.ErrNotFound) {
+		return meta, ErrNoMetadataMatch
+	}
+	if err != nil {
+		return meta, fmt.Errorf("%w: %v", ErrMetadataUnavailable, err)
+	}
+	s.writeMetadataCache(p.Name(), metadataGame, externalID, meta)
+	return meta, nil
+}
+
+// readMetadataCache carga en out la respuesta guardada si no venció. La
+// caché es best effort: si falla la lectura se consulta al proveedor.
+func (s *MetadataService) readMetadataCache(provider, kind, key string, out interface{}) bool {
+	var entry models.MetadataCache
+	err := s.conn.Where("provider = ? AND kind = ? AND lookup_key = ? AND expires_at > ?", provider, kind, key, clock.Now()).
+		First(&entry).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("metadata cache read %s/%s: %v", kind, key, err)
+		}
+		return false
+	}
+	if err := json.Unmarshal([]byte(entry.Payload), out); err != nil {
+		log.Printf("metadata cache decode %s/%s: %v", kind, key, err)
+		return false
+	}
+	return true
+}
+
+func (s *MetadataService) writeMetadataCache(provider, kind, key string, value interface{}) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("metadata cache encode %s/%s: %v", kind, key, err)
+		return
+	}
+	entry := models.MetadataCache{
+		Provider:  provider,
+		Kind:      kind,
+		LookupKey: key,
+		Payload:   string(payload),
+		ExpiresAt: clock.Now().Add(s.cacheTTL),
+	}
+	err = s.conn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider"}, {Name: "kind"}, {Name: "lookup_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"payload", "expires_at", "updated_at"}),
+	}).Create(&entry).Error
+	if err != nil {
+		log.Printf("metadata cache write %s/%s: %v", kind, key, err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gametracker/metadata"
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const metadataCacheQuery = "SELECT \\* FROM `metadata_caches` WHERE provider = \\? AND kind = \\? AND lookup_key = \\? AND expires_at > \\?"
+
+var hadesRelease = time.Date(2020, 9, 17, 0, 0, 0, 0, time.UTC)
+
+var hadesMetadata = models.GameMetadata{
+	ExternalID:  "274755",
+	Title:       "Hades",
+	Genres:      []string{"Action", "RPG"},
+	ReleaseDate: &hadesRelease,
+	Developer:   "Supergiant Games",
+	CoverURL:    "https://media.example.com/hades.jpg",
+}
+
+func TestEnrichGame_FillsMissingFields(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	fake := metadata.NewFakeProvider(
+		models.GameMetadata{ExternalID: "1", Title: "Hades II"},
+		hadesMetadata,
+	)
+
+	// Caché vacía: búsqueda y ficha van al proveedor y se guardan
+	mock.ExpectQuery(metadataCacheQuery).
+		WithArgs("fake", "search", "hades", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `metadata_caches` .* ON DUPLICATE KEY UPDATE").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(metadataCacheQuery).
+		WithArgs("fake", "game", "274755", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `metadata_caches`").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+	expectHoursDerived(mock, 0)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET .* WHERE \\(user_id = \\? AND version = \\?\\)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act: el género ya estaba cargado, falta el resto
+	game := models.Game{ID: 3, UserID: 1, Title: "Hades", Platform: "PC", Genre: "Roguelike", Status: "Playing", Version: 2}
+	result, err := NewMetadataService(conn, fake, time.Hour).EnrichGame(context.Background(), 1, game, "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"coverURL", "releaseDate", "developer"}, result.Filled)
+	assert.Equal(t, "Roguelike", result.Game.Genre)
+	assert.Equal(t, hadesMetadata.CoverURL, result.Game.CoverURL)
+	assert.Equal(t, uint(3), result.Game.Version)
+	assert.Equal(t, "Supergiant Games", result.Game.Developer)
+	assert.Equal(t, hadesRelease, *result.Game.ReleaseDate)
+	assert.Equal(t, 1, fake.SearchCalls)
+	assert.Equal(t, 1, fake.GetCalls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrichGame_UsesCache(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	fake := metadata.NewFakeProvider(hadesMetadata)
+
+	mock.ExpectQuery(metadataCacheQuery).
+		WithArgs("fake", "game", "274755", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload"}).
+			AddRow(1, `{"externalId":"274755","title":"Hades","genres":["Action"],"coverURL":"https://media.example.com/hades.jpg"}`))
+
+	// Act: el juego ya tiene todo, no se guarda nada
+	game := models.Game{ID: 3, Title: "Hades", Genre: "Action", CoverURL: "https://example.com/mine.jpg"}
+	result, err := NewMetadataService(conn, fake, time.Hour).EnrichGame(context.Background(), 1, game, "274755")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, result.Filled)
+	assert.Equal(t, "https://example.com/mine.jpg", result.Game.CoverURL)
+	assert.Equal(t, 0, fake.GetCalls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnrichGame_Errors(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	game := models.Game{ID: 3, Title: "Desconocido"}
+
+	_, err := NewMetadataService(conn, nil, time.Hour).EnrichGame(context.Background(), 1, game, "")
+	assert.ErrorIs(t, err, ErrMetadataDisabled)
+
+	fake := metadata.NewFakeProvider(hadesMetadata)
+	enricher := NewMetadataService(conn, fake, time.Hour)
+	mock.ExpectQuery(metadataCacheQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `metadata_caches`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	_, err = enricher.EnrichGame(context.Background(), 1, game, "")
+	assert.ErrorIs(t, err, ErrNoMetadataMatch)
+
+	fake.Err = errors.New("connection refused")
+	mock.ExpectQuery(metadataCacheQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = enricher.EnrichGame(context.Background(), 1, game, "999")
+	assert.ErrorIs(t, err, ErrMetadataUnavailable)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadMetadataConfig(t *testing.T) {
+	t.Setenv("METADATA_PROVIDER", "RAWG")
+	t.Setenv("METADATA_CACHE_TTL", "24h")
+
+	cfg, err := LoadMetadataConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "rawg", cfg.Provider)
+	assert.Equal(t, 24*time.Hour, cfg.CacheTTL)
+	assert.Equal(t, DefaultMetadataTimeout, cfg.Timeout)
+
+	t.Setenv("METADATA_PROVIDER", "igdb")
+	_, err = LoadMetadataConfig()
+	assert.Error(t, err)
+}
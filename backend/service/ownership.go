@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrOwnershipNotFound = errors.New("ownership record not found")
+
+// OwnershipService administra las copias (plataforma y tienda) de cada juego.
+// Como las sesiones, verifica primero que el juego sea del usuario y
+// recalcula Game.HoursPlayed en la misma transacción.
+type OwnershipService struct {
+	conn  *gorm.DB
+	games *GameService
+}
+
+func NewOwnershipService(conn *gorm.DB) *OwnershipService {
+	return &OwnershipService{conn: conn, games: NewGameService(NewGameRepository(conn))}
+}
+
+// ListOwnerships devuelve las copias del juego en el orden en que se cargaron.
+func (s *OwnershipService) ListOwnerships(userID uint, gameID string) ([]models.GameOwnership, error) {
+	ownerships := []models.GameOwnership{}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return ownerships, err
+	}
+	err = s.conn.Where("game_id = ?", game.ID).Order("id ASC").Find(&ownerships).Error
+	return ownerships, err
+}
+
+// CreateOwnership agrega una copia al juego. Si es la primera fuente de
+// horas del juego y no trae horas, hereda las que estaban cargadas a mano.
+func (s *OwnershipService) CreateOwnership(userID uint, gameID string, input models.OwnershipInput) (models.GameOwnership, error) {
+	var ownership models.GameOwnership
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return ownership, err
+	}
+	input.Apply(&ownership)
+	ownership.GameID = game.ID
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		if input.HoursPlayed == nil {
+			derived, err := hoursDerived(tx, game.ID)
+			if err != nil {
+				return err
+			}
+			if !derived {
+				ownership.HoursPlayed = game.HoursPlayed
+			}
+		}
+		if err := tx.Create(&ownership).Error; err != nil {
+			return err
+		}
+		return recalcHoursPlayed(tx, game.ID)
+	})
+	return ownership, err
+}
+
+// UpdateOwnership reemplaza los datos de la copia.
+func (s *OwnershipService) UpdateOwnership(userID uint, gameID, ownershipID string, input models.OwnershipInput) (models.GameOwnership, error) {
+	var ownership models.GameOwnership
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return ownership, err
+	}
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("game_id = ?", game.ID).First(&ownership, ownershipID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOwnershipNotFound
+			}
+			return err
+		}
+		input.Apply(&ownership)
+		err := tx.Model(&ownership).
+			Select("platform", "store", "edition", "purchased_at", "price_paid", "format", "hours_played").
+			Updates(&ownership).Error
+		if err != nil {
+			return err
+		}
+		return recalcHoursPlayed(tx, game.ID)
+	})
+	return ownership, err
+}
+
+func (s *OwnershipService) DeleteOwnership(userID uint, gameID, ownershipID string) error {
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return err
+	}
+	return s.conn.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("game_id = ?", game.ID).Delete(&models.GameOwnership{}, ownershipID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrOwnershipNotFound
+		}
+		return recalcHoursPlayed(tx, game.ID)
+	})
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateOwnership_InheritsManualHours(t *testing.T) {
+	// Arrange: juego con 30 horas cargadas a mano, sin sesiones ni copias
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\?").
+		WithArgs(uint(1), "5", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "hours_played"}).AddRow(5, 1, "Hades", 30.0))
+	mock.ExpectBegin()
+	expectHoursDerived(mock, 0)
+	mock.ExpectExec("INSERT INTO `game_ownerships`").
+		WithArgs(uint(5), "PC", "Steam", "", nil, nil, models.FormatDigital, 30.0, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	expectRecalcHours(mock)
+	mock.ExpectCommit()
+
+	// Act
+	ownership, err := NewOwnershipService(conn).CreateOwnership(1, "5", models.OwnershipInput{Platform: "PC", Store: "Steam", Format: models.FormatDigital})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), ownership.ID)
+	assert.Equal(t, 30.0, ownership.HoursPlayed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateOwnership_WithHours(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `game_ownerships`").WillReturnResult(sqlmock.NewResult(5, 1))
+	expectRecalcHours(mock)
+	mock.ExpectCommit()
+
+	hours := 12.5
+	ownership, err := NewOwnershipService(conn).CreateOwnership(1, "5", models.OwnershipInput{Platform: "Switch", HoursPlayed: &hours})
+
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, ownership.HoursPlayed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateOwnership_RecalculatesHours(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `game_ownerships` WHERE game_id = \\? AND `game_ownerships`.`id` = \\?").
+		WithArgs(uint(5), "4", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "game_id", "platform", "hours_played"}).AddRow(4, 5, "PC", 30.0))
+	mock.ExpectExec("^UPDATE `game_ownerships` SET `platform`=\\?,`store`=\\?,`edition`=\\?,`purchased_at`=\\?,`price_paid`=\\?,`format`=\\?,`hours_played`=\\?,`updated_at`=\\? WHERE `id` = \\?$").
+		WithArgs("PC", "GOG", "Deluxe", nil, nil, "", 30.0, sqlmock.AnyArg(), uint(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRecalcHours(mock)
+	mock.ExpectCommit()
+
+	// Sin hoursPlayed se conservan las horas que tenía
+	ownership, err := NewOwnershipService(conn).UpdateOwnership(1, "5", "4", models.OwnershipInput{Platform: "PC", Store: "GOG", Edition: "Deluxe"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "GOG", ownership.Store)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOwnership_NotFound(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM `game_ownerships` WHERE game_id = \\? AND `game_ownerships`.`id` = \\?$").
+		WithArgs(uint(5), "9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := NewOwnershipService(conn).DeleteOwnership(1, "5", "9")
+
+	assert.ErrorIs(t, err, ErrOwnershipNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"errors"
+
+	"gametracker/clock"
+	"gametracker/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSessionNotFound  = errors.New("play session not found")
+	ErrSessionRunning   = errors.New("a play session is already running for this game")
+	ErrNoRunningSession = errors.New("no play session is running for this game")
+)
+
+// SessionService administra las sesiones de juego y el cronómetro. Todos sus
+// métodos verifican primero que el juego sea del usuario (ErrNotFound si no)
+// y recalculan Game.HoursPlayed en la misma transacción que modifica las
+// sesiones. La primera sesión conserva las horas cargadas a mano (ver
+// keepManualHours).
+type SessionService struct {
+	conn  *gorm.DB
+	games *GameService
+}
+
+func NewSessionService(conn *gorm.DB) *SessionService {
+	return &SessionService{conn: conn, games: NewGameService(NewGameRepository(conn))}
+}
+
+// ListSessions devuelve las sesiones del juego, las más recientes primero.
+func (s *SessionService) ListSessions(userID uint, gameID string) ([]models.PlaySession, error) {
+	sessions := []models.PlaySession{}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return sessions, err
+	}
+	err = s.conn.Where("game_id = ?", game.ID).Order("started_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// CreateSession registra una sesión ya terminada.
+func (s *SessionService) CreateSession(userID uint, gameID string, session *models.PlaySession) error {
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return err
+	}
+	session.ID = 0
+	session.GameID = game.ID
+	return s.conn.Transaction(func(tx *gorm.DB) error {
+		if err := checkSessionOwnership(tx, game.ID, session.OwnershipID); err != nil {
+			return err
+		}
+		if err := keepManualHours(tx, game); err != nil {
+			return err
+		}
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return recalcHoursPlayed(tx, game.ID)
+	})
+}
+
+// UpdateSession reemplaza inicio, fin, duración, nota y copia de una sesión.
+func (s *SessionService) UpdateSession(userID uint, gameID, sessionID string, input models.PlaySessionInput) (models.PlaySession, error) {
+	var session models.PlaySession
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return session, err
+	}
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		if err := findSession(tx, game.ID, sessionID, &session); err != nil {
+			return err
+		}
+		input.Apply(&session)
+		if err := checkSessionOwnership(tx, game.ID, session.OwnershipID); err != nil {
+			return err
+		}
+		if err := tx.Model(&session).Select("started_at", "ended_at", "duration_minutes", "note", "ownership_id").Updates(&session).Error; err != nil {
+			return err
+		}
+		return recalcHoursPlayed(tx, game.ID)
+	})
+	return session, err
+}
+
+func (s *SessionService) DeleteSession(userID uint, gameID, sessionID string) error {
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return err
+	}
+	return s.conn.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("game_id = ?", game.ID).Delete(&models.PlaySession{}, sessionID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrSessionNotFound
+		}
+		return recalcHoursPlayed(tx, game.ID)
+	})
+}
+
+// StartSession arranca el cronómetro del juego, opcionalmente en la copia
+// ownershipID. Solo puede haber uno en curso por juego: la fila del juego se
+// bloquea (SELECT ... FOR UPDATE) para que dos pedidos simultáneos no pasen
+// los dos el conteo. SQLite no tiene bloqueo por fila, pero serializa las
+// escrituras.
+func (s *SessionService) StartSession(userID uint, gameID string, ownershipID *uint) (models.PlaySession, error) {
+	session := models.PlaySession{StartedAt: clock.Now(), OwnershipID: ownershipID}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return session, err
+	}
+	session.GameID = game.ID
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		var locked models.Game
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, game.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var running int64
+		if err := tx.Model(&models.PlaySession{}).
+			Where("game_id = ? AND ended_at IS NULL", game.ID).
+			Count(&running).Error; err != nil {
+			return err
+		}
+		if running > 0 {
+			return ErrSessionRunning
+		}
+		if err := checkSessionOwnership(tx, game.ID, ownershipID); err != nil {
+			return err
+		}
+		if err := keepManualHours(tx, game); err != nil {
+			return err
+		}
+		return tx.Create(&session).Error
+	})
+	return session, err
+}
+
+// StopSession detiene el cronómetro en curso y suma su duración al juego.
+func (s *SessionService) StopSession(userID uint, gameID string) (models.PlaySession, error) {
+	var session models.PlaySession
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return session, err
+	}
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("game_id = ? AND ended_at IS NULL", game.ID).First(&session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoRunningSession
+		}
+		if err != nil {
+			return err
+		}
+		endedAt := clock.Now()
+		session.EndedAt = &endedAt
+		session.DurationMinutes = models.SessionMinutes(session.StartedAt, endedAt)
+		if err := tx.Model(&session).Select("ended_at", "duration_minutes").Updates(&session).Error; err != nil {
+			return err
+		}
+		return recalcHoursPlayed(tx, game.ID)
+	})
+	return session, err
+}
+
+func findSession(tx *gorm.DB, gameID uint, sessionID string, session *models.PlaySession) error {
+	err := tx.Where("game_id = ?", gameID).First(session, sessionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSessionNotFound
+	}
+	return err
+}
+
+// checkSessionOwnership verifica que la copia de una sesión, si se indicó,
+// sea del mismo juego (ErrOwnershipNotFound si no).
+func checkSessionOwnership(tx *gorm.DB, gameID uint, ownershipID *uint) error {
+	if ownershipID == nil {
+		return nil
+	}
+	var count int64
+	err := tx.Model(&models.GameOwnership{}).Where("id = ? AND game_id = ?", *ownershipID, gameID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrOwnershipNotFound
+	}
+	return nil
+}
+
+// keepManualHours se llama antes de crear una sesión. Si el juego todavía no
+// tiene sesiones ni copias, las horas cargadas a mano pasan a una copia en su
+// plataforma para que recalcHoursPlayed las siga sumando, como hace
+// CreateOwnership con la primera copia.
+func keepManualHours(tx *gorm.DB, game models.Game) error {
+	if game.HoursPlayed == 0 {
+		return nil
+	}
+	derived, err := hoursDerived(tx, game.ID)
+	if err != nil || derived {
+		return err
+	}
+	return tx.Create(&models.GameOwnership{GameID: game.ID, Platform: game.Platform, HoursPlayed: game.HoursPlayed}).Error
+}
+
+// recalcHoursPlayed deriva HoursPlayed de las sesiones terminadas del juego
+// más las horas cargadas en sus copias (GameOwnership) y sube su versión,
+// así un PUT con datos viejos recibe 412. Se divide por 60.0 porque en
+// PostgreSQL y SQLite entero / entero trunca.
+func recalcHoursPlayed(tx *gorm.DB, gameID uint) error {
+	return tx.Model(&models.Game{}).Where("id = ?", gameID).Updates(map[string]interface{}{
+		"hours_played": gorm.Expr("(SELECT COALESCE(SUM(duration_minutes), 0) / 60.0 FROM play_sessions WHERE game_id = ? AND ended_at IS NOT NULL)"+
+			" + (SELECT COALESCE(SUM(hours_played), 0) FROM game_ownerships WHERE game_id = ?)", gameID, gameID),
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+// hoursDerived indica si las horas del juego se derivan de sus sesiones o
+// de sus copias, y por lo tanto no se editan a mano.
+func hoursDerived(tx *gorm.DB, gameID uint) (bool, error) {
+	var count int64
+	err := tx.Raw("SELECT (SELECT COUNT(*) FROM play_sessions WHERE game_id = ?) + "+
+		"(SELECT COUNT(*) FROM game_ownerships WHERE game_id = ?)", gameID, gameID).Scan(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectOwnedGame(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\?").
+		WithArgs(uint(1), "5", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title"}).AddRow(5, 1, "Hades"))
+}
+
+// expectLockGame espera el SELECT ... FOR UPDATE con el que StartSession
+// serializa los cronómetros del juego.
+func expectLockGame(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("^SELECT `id` FROM `games` WHERE `games`.`id` = \\? AND `games`.`deleted_at` IS NULL ORDER BY `games`.`id` LIMIT \\? FOR UPDATE$").
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+}
+
+func expectRecalcHours(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("^UPDATE `games` SET `hours_played`=\\(SELECT COALESCE\\(SUM\\(duration_minutes\\), 0\\) / 60\\.0 FROM play_sessions WHERE game_id = \\? AND ended_at IS NOT NULL\\) \\+ \\(SELECT COALESCE\\(SUM\\(hours_played\\), 0\\) FROM game_ownerships WHERE game_id = \\?\\),`version`=version \\+ 1,`updated_at`=\\? WHERE id = \\? AND `games`.`deleted_at` IS NULL$").
+		WithArgs(uint(5), uint(5), sqlmock.AnyArg(), uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// expectHoursDerived espera la consulta que decide si las horas del juego se
+// derivan de sesiones o copias (count > 0).
+func expectHoursDerived(mock sqlmock.Sqlmock, count int) {
+	mock.ExpectQuery("^SELECT \\(SELECT COUNT\\(\\*\\) FROM play_sessions WHERE game_id = \\?\\) \\+ \\(SELECT COUNT\\(\\*\\) FROM game_ownerships WHERE game_id = \\?\\)$").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func TestCreateSession_RecalculatesHours(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	session := &models.PlaySession{StartedAt: start, EndedAt: &end, DurationMinutes: 90}
+
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `play_sessions`").
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	expectRecalcHours(mock)
+	mock.ExpectCommit()
+
+	// Act
+	err := NewSessionService(conn).CreateSession(1, "5", session)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(11), session.ID)
+	assert.Equal(t, uint(5), session.GameID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateSession_OtherUsersGame(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectQuery("SELECT \\* FROM `games`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Act
+	err := NewSessionService(conn).CreateSession(1, "5", &models.PlaySession{})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStartSession_AlreadyRunning(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	expectLockGame(mock)
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `play_sessions` WHERE game_id = \\? AND ended_at IS NULL$").
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := NewSessionService(conn).StartSession(1, "5", nil)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSessionRunning)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStartSession_OwnershipOfAnotherGame(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	ownershipID := uint(9)
+
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	expectLockGame(mock)
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `play_sessions` WHERE game_id = \\? AND ended_at IS NULL$").
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `game_ownerships` WHERE id = \\? AND game_id = \\?$").
+		WithArgs(ownershipID, uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := NewSessionService(conn).StartSession(1, "5", &ownershipID)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOwnershipNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStopSession_ComputesDuration(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	fixNow(t, start.Add(75*time.Minute+20*time.Second))
+
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `play_sessions` WHERE game_id = \\? AND ended_at IS NULL").
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "game_id", "started_at"}).AddRow(3, 5, start))
+	mock.ExpectExec("^UPDATE `play_sessions` SET `ended_at`=\\?,`duration_minutes`=\\?,`updated_at`=\\? WHERE `id` = \\?$").
+		WithArgs(sqlmock.AnyArg(), 75, sqlmock.AnyArg(), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRecalcHours(mock)
+	mock.ExpectCommit()
+
+	// Act
+	session, err := NewSessionService(conn).StopSession(1, "5")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 75, session.DurationMinutes)
+	assert.False(t, session.Running())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStopSession_NoneRunning(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `play_sessions`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	// Act
+	_, err := NewSessionService(conn).StopSession(1, "5")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNoRunningSession)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSession_NotFound(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM `play_sessions` WHERE game_id = \\? AND `play_sessions`.`id` = \\?$").
+		WithArgs(uint(5), "9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Act
+	err := NewSessionService(conn).DeleteSession(1, "5", "9")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_KeepsDerivedHours(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	game := &models.Game{ID: 5, Title: "Hades", Platform: "PC", Status: "Playing", HoursPlayed: 999}
+
+	expectHoursDerived(mock, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := NewGameService(NewGameRepository(conn)).Update(1, models.Game{ID: 5, Status: "Playing", HoursPlayed: 12.5, Version: 1}, game)
+
+	// Assert: las horas vienen de las sesiones, no del body
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, game.HoursPlayed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
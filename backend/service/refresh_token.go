@@ -0,0 +1,182 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"gametracker/models"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidRefreshToken cubre tokens inexistentes, vencidos o revocados.
+	ErrInvalidRefreshToken = errors.New("refresh token inválido")
+	// ErrRefreshTokenReused indica que se presentó un token ya rotado: se
+	// asume robado y se revoca la familia completa.
+	ErrRefreshTokenReused = errors.New("refresh token reutilizado, sesión revocada")
+	// ErrSessionRevoked se usa cuando el sid del access token ya no es válido.
+	ErrSessionRevoked = errors.New("sesión revocada")
+)
+
+// Refresh rota el refresh token: lo marca como usado, emite uno nuevo en la
+// misma familia y un access token nuevo.
+func (s *AuthService) Refresh(refreshToken string) (*models.AuthResponse, error) {
+	var current models.RefreshToken
+	if err := s.conn.Where("token_hash = ?", hashRefreshToken(refreshToken)).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, errors.New("error al buscar refresh token")
+	}
+
+	if current.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		if err := s.revokeFamily(current.FamilyID); err != nil {
+			return nil, errors.New("error al revocar sesión")
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	var user models.User
+	var newRefreshToken string
+	err := s.conn.Transaction(func(tx *gorm.DB) error {
+		// El used_at IS NULL evita que dos requests concurrentes roten el mismo token.
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", current.ID).
+			Update("used_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		if err := tx.First(&user, current.UserID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		newRefreshToken, err = s.issueRefreshToken(tx, current.UserID, current.FamilyID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			_ = s.revokeFamily(current.FamilyID)
+			return nil, ErrRefreshTokenReused
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, errors.New("error al rotar refresh token")
+	}
+
+	token, err := s.generateToken(user.ID, user.Username, current.FamilyID)
+	if err != nil {
+		return nil, errors.New("error al generar token")
+	}
+
+	user.Password = ""
+	return &models.AuthResponse{
+		Token:        token,
+		RefreshToken: newRefreshToken,
+		User:         user,
+	}, nil
+}
+
+// Logout revoca la sesión (familia) a la que pertenece el refresh token.
+// Un token desconocido no es un error: la sesión ya no existe.
+func (s *AuthService) Logout(refreshToken string) error {
+	var current models.RefreshToken
+	if err := s.conn.Where("token_hash = ?", hashRefreshToken(refreshToken)).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return errors.New("error al buscar refresh token")
+	}
+	if err := s.revokeFamily(current.FamilyID); err != nil {
+		return errors.New("error al revocar sesión")
+	}
+	return nil
+}
+
+// IsSessionRevoked indica si la sesión (sid) de un access token fue revocada.
+func (s *AuthService) IsSessionRevoked(sessionID string) (bool, error) {
+	var count int64
+	err := s.conn.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NOT NULL", sessionID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetSessionFromToken extrae el sid (familia de refresh tokens) del access token.
+func (s *AuthService) GetSessionFromToken(token *jwt.Token) (string, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", errors.New("token inválido")
+	}
+	sessionID, ok := claims["sid"].(string)
+	if !ok || sessionID == "" {
+		return "", errors.New("sid no encontrado en token")
+	}
+	return sessionID, nil
+}
+
+// startSession abre una familia nueva de refresh tokens para un login.
+func (s *AuthService) startSession(userID uint) (sessionID string, refreshToken string, err error) {
+	sessionID, err = randomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err = s.issueRefreshToken(s.conn, userID, sessionID)
+	return sessionID, refreshToken, err
+}
+
+func (s *AuthService) issueRefreshToken(tx *gorm.DB, userID uint, familyID string) (string, error) {
+	plain, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	record := models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(plain),
+		ExpiresAt: time.Now().Add(s.jwtConfig.RefreshTTL),
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+func (s *AuthService) revokeFamily(familyID string) error {
+	return s.conn.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var refreshTokenColumns = []string{"id", "user_id", "family_id", "token_hash", "expires_at", "used_at", "revoked_at", "created_at"}
+
+// expectRefreshTokenInsert espera el INSERT del refresh token que crea Login.
+func expectRefreshTokenInsert(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO `refresh_tokens`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+}
+
+func expectRefreshTokenLookup(mock sqlmock.Sqlmock, plain string, rows *sqlmock.Rows) {
+	mock.ExpectQuery("^SELECT \\* FROM `refresh_tokens` WHERE token_hash = \\? ORDER BY `refresh_tokens`.`id` LIMIT \\?$").
+		WithArgs(hashRefreshToken(plain), 1).
+		WillReturnRows(rows)
+}
+
+func TestAuthService_Refresh_Success(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	service := NewAuthServiceWithConfig(testJWTConfig(), nil, conn)
+	now := time.Now()
+
+	expectRefreshTokenLookup(mock, "old-token", sqlmock.NewRows(refreshTokenColumns).
+		AddRow(10, 1, "family-1", hashRefreshToken("old-token"), now.Add(time.Hour), nil, nil, now))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE `refresh_tokens` SET `used_at`=\\? WHERE id = \\? AND used_at IS NULL$").
+		WithArgs(sqlmock.AnyArg(), 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("^SELECT \\* FROM `users` WHERE `users`.`id` = \\? ORDER BY `users`.`id` LIMIT \\?$").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "password"}).
+			AddRow(1, "testuser", "test@example.com", "hash"))
+	mock.ExpectExec("^INSERT INTO `refresh_tokens`").
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectCommit()
+
+	authResponse, err := service.Refresh("old-token")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, authResponse.Token)
+	assert.NotEmpty(t, authResponse.RefreshToken)
+	assert.NotEqual(t, "old-token", authResponse.RefreshToken)
+	assert.Empty(t, authResponse.User.Password)
+
+	// El access token nuevo conserva la sesión original
+	token, err := service.ValidateToken(authResponse.Token)
+	require.NoError(t, err)
+	sessionID, err := service.GetSessionFromToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "family-1", sessionID)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	service := NewAuthServiceWithConfig(testJWTConfig(), nil, conn)
+	now := time.Now()
+	usedAt := now.Add(-time.Minute)
+
+	expectRefreshTokenLookup(mock, "rotated-token", sqlmock.NewRows(refreshTokenColumns).
+		AddRow(10, 1, "family-1", hashRefreshToken("rotated-token"), now.Add(time.Hour), usedAt, nil, now))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE `refresh_tokens` SET `revoked_at`=\\? WHERE family_id = \\? AND revoked_at IS NULL$").
+		WithArgs(sqlmock.AnyArg(), "family-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	authResponse, err := service.Refresh("rotated-token")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	assert.Nil(t, authResponse)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthService_Refresh_InvalidToken(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	service := NewAuthServiceWithConfig(testJWTConfig(), nil, conn)
+	now := time.Now()
+
+	t.Run("unknown", func(t *testing.T) {
+		mock.ExpectQuery("^SELECT \\* FROM `refresh_tokens` WHERE token_hash = \\?").
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := service.Refresh("unknown")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expectRefreshTokenLookup(mock, "expired", sqlmock.NewRows(refreshTokenColumns).
+			AddRow(10, 1, "family-1", hashRefreshToken("expired"), now.Add(-time.Hour), nil, nil, now))
+
+		_, err := service.Refresh("expired")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		expectRefreshTokenLookup(mock, "revoked", sqlmock.NewRows(refreshTokenColumns).
+			AddRow(10, 1, "family-1", hashRefreshToken("revoked"), now.Add(time.Hour), nil, now, now))
+
+		_, err := service.Refresh("revoked")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	service := NewAuthServiceWithConfig(testJWTConfig(), nil, conn)
+	now := time.Now()
+
+	expectRefreshTokenLookup(mock, "token", sqlmock.NewRows(refreshTokenColumns).
+		AddRow(10, 1, "family-1", hashRefreshToken("token"), now.Add(time.Hour), nil, nil, now))
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE `refresh_tokens` SET `revoked_at`=\\? WHERE family_id = \\? AND revoked_at IS NULL$").
+		WithArgs(sqlmock.AnyArg(), "family-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, service.Logout("token"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthService_IsSessionRevoked(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	service := NewAuthServiceWithConfig(testJWTConfig(), nil, conn)
+
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `refresh_tokens` WHERE family_id = \\? AND revoked_at IS NOT NULL$").
+		WithArgs("family-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("^SELECT count\\(\\*\\) FROM `refresh_tokens` WHERE family_id = \\? AND revoked_at IS NOT NULL$").
+		WithArgs("family-2").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	revoked, err := service.IsSessionRevoked("family-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = service.IsSessionRevoked("family-2")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+
+	"gametracker/models"
+)
+
+// ErrUserNotFound lo devuelve UserRepository cuando no hay usuario que
+// coincida.
+var ErrUserNotFound = errors.New("user not found")
+
+// GameRepository es la persistencia de juegos que usa GameService. La
+// implementación de la app es NewGameRepository (gorm); los tests pueden
+// pasarle otra base o un doble en memoria.
+type GameRepository interface {
+	// FindByID devuelve ErrNotFound si el juego no existe o es de otro usuario.
+	FindByID(userID uint, id string) (models.Game, error)
+	// Find devuelve sin paginar los juegos del usuario que cumplen filter.
+	Find(userID uint, filter models.GameFilter) ([]models.Game, error)
+	List(userID uint, q models.GameListQuery) (models.GamePage, error)
+	Create(game *models.Game) error
+	// Update guarda game solo si su versión en la base sigue siendo
+	// version; si no, devuelve ErrVersionConflict.
+	Update(game *models.Game, version uint) error
+	// Delete devuelve ErrNotFound si el juego no existe o es de otro usuario.
+	Delete(userID uint, id string) error
+	// HoursDerived indica si las horas del juego salen de sus sesiones o
+	// copias.
+	HoursDerived(gameID uint) (bool, error)
+	// AchievementProgress es el porcentaje de logros desbloqueados.
+	AchievementProgress(gameID uint) (int, error)
+}
+
+// UserRepository es la persistencia de cuentas que usa AuthService.
+type UserRepository interface {
+	ExistsByUsernameOrEmail(username, email string) (bool, error)
+	// FindByLogin busca por username o email; ErrUserNotFound si no existe.
+	FindByLogin(login string) (models.User, error)
+	Create(user *models.User) error
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gametracker/db"
+	"gametracker/models"
+
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+
+	// Cantidad de runas de contexto alrededor del primer match en la nota.
+	snippetRadius = 80
+)
+
+// fulltextMatch usa el índice FULLTEXT idx_games_fulltext, que solo existe
+// en MySQL. La base se crea con utf8mb4_unicode_ci, así que MySQL ya compara
+// sin distinguir mayúsculas ni acentos ("pokemon" encuentra "Pokémon").
+const fulltextMatch = "MATCH(title, personal_note) AGAINST (? IN BOOLEAN MODE)"
+
+type searchRow struct {
+	models.Game
+	Relevance float64
+}
+
+// SearchService busca juegos por título y nota personal.
+type SearchService struct {
+	conn *gorm.DB
+}
+
+func NewSearchService(conn *gorm.DB) *SearchService {
+	return &SearchService{conn: conn}
+}
+
+// SearchGames busca en título y nota personal de la biblioteca del usuario,
+// ordenando por relevancia y devolviendo fragmentos resaltados.
+func (s *SearchService) SearchGames(userID uint, query string, limit int) ([]models.GameSearchResult, error) {
+	results := []models.GameSearchResult{}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return results, nil
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	var rows []searchRow
+	var err error
+	if s.conn.Dialector.Name() == db.DriverMySQL {
+		rows, err = s.fulltextSearch(userID, terms, limit)
+	} else {
+		rows, err = s.scanSearch(userID, terms, limit)
+	}
+	if err != nil {
+		return results, err
+	}
+
+	for _, row := range rows {
+		results = append(results, models.GameSearchResult{
+			Game:      row.Game,
+			Relevance: row.Relevance,
+			Highlights: models.GameHighlights{
+				Title:        highlight(row.Title, terms),
+				PersonalNote: snippet(row.PersonalNote, terms, snippetRadius),
+			},
+		})
+	}
+	return results, nil
+}
+
+func (s *SearchService) fulltextSearch(userID uint, terms []string, limit int) ([]searchRow, error) {
+	booleanQuery := booleanModeQuery(terms)
+	var rows []searchRow
+	err := s.conn.Model(&models.Game{}).
+		Select("*, "+fulltextMatch+" AS relevance", booleanQuery).
+		Where("user_id = ?", userID).
+		Where(fulltextMatch, booleanQuery).
+		Order("relevance DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// scanSearch es la búsqueda de los motores sin FULLTEXT (PostgreSQL,
+// SQLite): prefiltra en SQL con LIKE los juegos que contienen todas las
+// palabras y aplica en Go las reglas del modo booleano (al comienzo de una
+// palabra, sin mayúsculas ni acentos) y la relevancia.
+func (s *SearchService) scanSearch(userID uint, terms []string, limit int) ([]searchRow, error) {
+	// LIKE de SQLite ya ignora mayúsculas en ASCII; en PostgreSQL la nota
+	// no es citext.
+	like := "LIKE"
+	if s.conn.Dialector.Name() == db.DriverPostgres {
+		like = "ILIKE"
+	}
+	query := s.conn.Where("user_id = ?", userID)
+	for _, term := range terms {
+		pattern := likePattern(term)
+		query = query.Where("title "+like+" ? OR personal_note "+like+" ?", pattern, pattern)
+	}
+	var games []models.Game
+	if err := query.Order("id ASC").Find(&games).Error; err != nil {
+		return nil, err
+	}
+	var rows []searchRow
+	for _, game := range games {
+		if relevance := scanRelevance(game, terms); relevance > 0 {
+			rows = append(rows, searchRow{Game: game, Relevance: relevance})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Relevance > rows[j].Relevance })
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// accentBases son las letras ASCII que suelen llevar acentos o tildes.
+const accentBases = "aceinosuyz"
+
+// likePattern arma el patrón LIKE con el que scanSearch prefiltra un
+// término. LIKE no ignora acentos, así que esas letras (y cualquier runa que
+// no sea ASCII) pasan a ser "_": el patrón deja pasar de más y el match real
+// lo decide scanRelevance. Los términos no traen "%" ni "_" (ver searchTerms).
+func likePattern(term string) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, r := range term {
+		r = foldRune(r)
+		if r > unicode.MaxASCII || strings.ContainsRune(accentBases, r) {
+			b.WriteByte('_')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('%')
+	return b.String()
+}
+
+// scanRelevance cuenta las coincidencias de cada término, las del título
+// doble. Si falta algún término el juego no matchea y devuelve 0.
+func scanRelevance(game models.Game, terms []string) float64 {
+	title, note := []rune(game.Title), []rune(game.PersonalNote)
+	var relevance float64
+	for _, term := range terms {
+		matches := 2*len(matchRanges(title, []string{term})) + len(matchRanges(note, []string{term}))
+		if matches == 0 {
+			return 0
+		}
+		relevance += float64(matches)
+	}
+	return relevance
+}
+
+// searchTerms separa la búsqueda en palabras, descartando los operadores del
+// modo booleano de MySQL para que el usuario no pueda romper la consulta.
+func searchTerms(query string) []string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		terms = append(terms, strings.ToLower(field))
+	}
+	return terms
+}
+
+// booleanModeQuery exige todas las palabras y acepta prefijos: "zel bre" -> "+zel* +bre*".
+func booleanModeQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = "+" + term + "*"
+	}
+	return strings.Join(parts, " ")
+}
+
+// foldRune lleva una runa a minúscula y sin diacríticos ("É" -> "e").
+func foldRune(r rune) rune {
+	decomposed := norm.NFD.String(string(r))
+	for _, base := range decomposed {
+		return unicode.ToLower(base)
+	}
+	return unicode.ToLower(r)
+}
+
+func foldRunes(text []rune) []rune {
+	folded := make([]rune, len(text))
+	for i, r := range text {
+		folded[i] = foldRune(r)
+	}
+	return folded
+}
+
+// matchRanges devuelve los rangos [inicio, fin) de runas de text en los que
+// empieza alguno de los términos, comparando sin mayúsculas ni acentos.
+func matchRanges(text []rune, terms []string) [][2]int {
+	folded := foldRunes(text)
+	var ranges [][2]int
+	for i := 0; i < len(folded); {
+		matched := 0
+		for _, term := range terms {
+			t := foldRunes([]rune(term))
+			if len(t) > matched && hasRunePrefix(folded[i:], t) && isWordStart(folded, i) {
+				matched = len(t)
+			}
+		}
+		if matched > 0 {
+			ranges = append(ranges, [2]int{i, i + matched})
+			i += matched
+			continue
+		}
+		i++
+	}
+	return ranges
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordStart replica el comportamiento del índice FULLTEXT, que solo
+// encuentra términos al comienzo de una palabra.
+func isWordStart(text []rune, i int) bool {
+	return i == 0 || !(unicode.IsLetter(text[i-1]) || unicode.IsDigit(text[i-1]))
+}
+
+// highlight escapa el texto como HTML y envuelve los matches en <mark>.
+func highlight(text string, terms []string) string {
+	runes := []rune(text)
+	return markRanges(runes, matchRanges(runes, terms), 0, len(runes))
+}
+
+// snippet devuelve un fragmento de la nota alrededor del primer match, o
+// vacío si la nota no contiene ninguno de los términos.
+func snippet(text string, terms []string, radius int) string {
+	runes := []rune(text)
+	ranges := matchRanges(runes, terms)
+	if len(ranges) == 0 {
+		return ""
+	}
+
+	start := ranges[0][0] - radius
+	if start < 0 {
+		start = 0
+	}
+	end := ranges[0][1] + radius
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	out := markRanges(runes, ranges, start, end)
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+func markRanges(runes []rune, ranges [][2]int, start, end int) string {
+	var b strings.Builder
+	pos := start
+	for _, r := range ranges {
+		if r[1] <= start || r[0] >= end {
+			continue
+		}
+		from, to := max(r[0], start), min(r[1], end)
+		b.WriteString(html.EscapeString(string(runes[pos:from])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[from:to])))
+		b.WriteString("</mark>")
+		pos = to
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	return b.String()
+}
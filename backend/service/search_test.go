@@ -0,0 +1,70 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTerms_StripsBooleanOperators(t *testing.T) {
+	terms := searchTerms(`Zelda +"breath" -wild* (Pokémon)`)
+	assert.Equal(t, []string{"zelda", "breath", "wild", "pokémon"}, terms)
+	assert.Equal(t, "+zelda* +breath* +wild* +pokémon*", booleanModeQuery(terms))
+}
+
+func TestHighlight_CaseAndAccentInsensitive(t *testing.T) {
+	assert.Equal(t, "<mark>Poké</mark>mon &amp; <mark>Éxito</mark>", highlight("Pokémon & Éxito", []string{"poke", "exito"}))
+	// Solo matchea al inicio de palabra, como el índice FULLTEXT
+	assert.Equal(t, "Replaying", highlight("Replaying", []string{"play"}))
+}
+
+func TestLikePattern_WildcardsAccentedLetters(t *testing.T) {
+	assert.Equal(t, "%p_k_m__%", likePattern("pokémon"))
+	assert.Equal(t, "%h_l_%", likePattern("halo"))
+	assert.Equal(t, "%__3%", likePattern("ñu3"))
+}
+
+func TestSnippet(t *testing.T) {
+	note := strings.Repeat("a ", 100) + "el jefe final es brutal " + strings.Repeat("b ", 100)
+
+	s := snippet(note, []string{"jefe"}, 10)
+
+	assert.True(t, strings.HasPrefix(s, "…"))
+	assert.True(t, strings.HasSuffix(s, "…"))
+	assert.Contains(t, s, "<mark>jefe</mark>")
+	assert.Empty(t, snippet("sin coincidencias", []string{"jefe"}, 10))
+}
+
+func TestSearchGames_Success(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	mock.ExpectQuery("^SELECT \\*, MATCH\\(title, personal_note\\) AGAINST \\(\\? IN BOOLEAN MODE\\) AS relevance FROM `games` WHERE user_id = \\? AND MATCH\\(title, personal_note\\) AGAINST \\(\\? IN BOOLEAN MODE\\) AND `games`.`deleted_at` IS NULL ORDER BY relevance DESC LIMIT \\?$").
+		WithArgs("+zelda*", uint(1), "+zelda*", DefaultSearchLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "personal_note", "relevance"}).
+			AddRow(3, 1, "The Legend of Zelda", "Mi Zelda favorito", 2.5))
+
+	results, err := NewSearchService(conn).SearchGames(1, "zelda", 0)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, uint(3), results[0].Game.ID)
+	assert.Equal(t, 2.5, results[0].Relevance)
+	assert.Equal(t, "The Legend of <mark>Zelda</mark>", results[0].Highlights.Title)
+	assert.Equal(t, "Mi <mark>Zelda</mark> favorito", results[0].Highlights.PersonalNote)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchGames_EmptyQuery(t *testing.T) {
+	conn, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+
+	results, err := NewSearchService(conn).SearchGames(1, "  +-* ", 10)
+
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,8 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
 
-	"gametracker/db"
 	"gametracker/models"
 
 	"gorm.io/gorm"
@@ -13,115 +13,107 @@ import (
 // Úsalo en controllers/tests con errors.Is(err, service.ErrNotFound)
 var ErrNotFound = errors.New("game not found")
 
-func GetAllGames() ([]models.Game, error) {
-	var games []models.Game
-	result := db.DB.Find(&games)
-	return games, result.Error
-}
+// ErrVersionConflict indica que el juego cambió desde que el cliente lo leyó.
+var ErrVersionConflict = errors.New("game was modified by another request")
 
-func GetGameByID(id string) (models.Game, error) {
-	var game models.Game
-	result := db.DB.First(&game, id)
-	if result.Error != nil {
-		// No logeamos record not found: es un flujo esperado.
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return game, ErrNotFound
-		}
-		return game, result.Error
-	}
-	return game, nil
+// GameService tiene las reglas de la biblioteca (ciclo de vida del estado,
+// versión, horas y progreso derivados) y guarda a través de games.
+type GameService struct {
+	games GameRepository
 }
 
-func CreateGame(game *models.Game) error {
-	return db.DB.Create(game).Error
+func NewGameService(games GameRepository) *GameService {
+	return &GameService{games: games}
 }
 
-func UpdateGame(game *models.Game) error {
-	// Save funciona, pero si querés evitar upsert accidental:
-	// return db.DB.Model(&models.Game{}).Where("id = ?", game.ID).Updates(game).Error
-	return db.DB.Save(game).Error
+// GetAll devuelve la biblioteca del usuario indicado.
+func (s *GameService) GetAll(userID uint) ([]models.Game, error) {
+	return s.games.Find(userID, models.GameFilter{})
 }
 
-func DeleteGame(id string) error {
-	// Si querés tratar "no existe" como ErrNotFound:
-	// res := db.DB.Delete(&models.Game{}, id)
-	// if res.Error != nil {
-	//     return res.Error
-	// }
-	// if res.RowsAffected == 0 {
-	//     return ErrNotFound
-	// }
-	// return nil
-	return db.DB.Delete(&models.Game{}, id).Error
+// Get busca un juego dentro de la biblioteca del usuario. Un juego de otro
+// usuario se reporta como ErrNotFound para no filtrar su existencia.
+func (s *GameService) Get(userID uint, id string) (models.Game, error) {
+	return s.games.FindByID(userID, id)
 }
 
-func GetByTitle(title string) ([]models.Game, error) {
-	var games []models.Game
-	query := "%" + title + "%"
-	result := db.DB.Where("title LIKE ?", query).Find(&games)
-	return games, result.Error
+// List lista la biblioteca con filtros, orden y paginación (ver listGames).
+func (s *GameService) List(userID uint, q models.GameListQuery) (models.GamePage, error) {
+	return s.games.List(userID, q)
 }
 
-func GetByStatus(status string) ([]models.Game, error) {
-	var games []models.Game
-	query := "%" + status + "%"
-	result := db.DB.Where("status LIKE ?", query).Find(&games)
-	return games, result.Error
-}
-
-func GetByGenre(genre string) ([]models.Game, error) {
-	var games []models.Game
-	query := "%" + genre + "%"
-	result := db.DB.Where("genre LIKE ?", query).Find(&games)
-	return games, result.Error
+func (s *GameService) Create(game *models.Game) error {
+	if err := applyStatusLifecycle(game, ""); err != nil {
+		return err
+	}
+	if game.ProgressFromAchievements {
+		// Un juego nuevo todavía no tiene logros.
+		game.Progress = derivedProgress(game.Status, 0)
+	}
+	game.Version = 1
+	return s.games.Create(game)
 }
 
-func GetStats() (models.GameStats, error) {
-	var stats models.GameStats
-	var games []models.Game
-
-	result := db.DB.Find(&games)
-	if result.Error != nil {
-		return stats, result.Error
+// Update guarda game validando el cambio de estado respecto de previous
+// (el juego tal como estaba en la base). El UPDATE solo aplica si la versión
+// sigue siendo previous.Version; si otro request la cambió devuelve
+// ErrVersionConflict.
+func (s *GameService) Update(userID uint, previous models.Game, game *models.Game) error {
+	if err := applyStatusLifecycle(game, previous.Status); err != nil {
+		return err
 	}
-
-	statusCount := make(map[string]int)
-	genreCount := make(map[string]int)
-	var pendingCount int
-	var totalHours float64
-
-	for _, game := range games {
-		statusCount[game.Status]++
-		genreCount[game.Genre]++
-		totalHours += game.HoursPlayed
-
-		if game.Status != "Completed" && game.Progress < 100 {
-			pendingCount++
-		}
+	// Con sesiones o copias cargadas las horas se derivan de ellas y no se
+	// editan a mano.
+	derived, err := s.games.HoursDerived(previous.ID)
+	if err != nil {
+		return err
 	}
-
-	mostPlayedGenre := ""
-	maxGenreCount := 0
-	for genre, count := range genreCount {
-		if count > maxGenreCount {
-			maxGenreCount = count
-			mostPlayedGenre = genre
+	if derived {
+		game.HoursPlayed = previous.HoursPlayed
+	}
+	if game.ProgressFromAchievements {
+		achievements, err := s.games.AchievementProgress(previous.ID)
+		if err != nil {
+			return err
 		}
+		game.Progress = derivedProgress(game.Status, achievements)
 	}
-
-	totalGames := len(games)
-	averageHours := 0.0
-	if totalGames > 0 {
-		averageHours = totalHours / float64(totalGames)
+	game.UserID = userID
+	game.Version = previous.Version + 1
+	if err := s.games.Update(game, previous.Version); err != nil {
+		game.Version = previous.Version
+		return err
 	}
+	return nil
+}
+
+func (s *GameService) Delete(userID uint, id string) error {
+	return s.games.Delete(userID, id)
+}
+
+// GetByTitle, GetByStatus y GetByGenre son atajos sin paginar de List con
+// un único filtro; se mantienen por compatibilidad con las rutas viejas.
+func (s *GameService) GetByTitle(userID uint, title string) ([]models.Game, error) {
+	return s.games.Find(userID, models.GameFilter{Title: title})
+}
 
-	stats = models.GameStats{
-		TotalGames:      totalGames,
-		ByStatus:        statusCount,
-		AverageHours:    averageHours,
-		MostPlayedGenre: mostPlayedGenre,
-		PendingGames:    pendingCount,
+func (s *GameService) GetByStatus(userID uint, status string) ([]models.Game, error) {
+	if !models.IsValidGameStatus(status) {
+		return nil, fmt.Errorf("%w: %q (valid: %v)", ErrInvalidStatus, status, models.GameStatuses)
 	}
+	return s.games.Find(userID, models.GameFilter{Status: status})
+}
+
+func (s *GameService) GetByGenre(userID uint, genre string) ([]models.Game, error) {
+	return s.games.Find(userID, models.GameFilter{Genre: genre})
+}
+
+// createGame y updateGame corren las reglas de GameService dentro de una
+// transacción en curso.
+func createGame(tx *gorm.DB, game *models.Game) error {
+	return NewGameService(NewGameRepository(tx)).Create(game)
+}
 
-	return stats, nil
+func updateGame(tx *gorm.DB, userID uint, previous models.Game, game *models.Game) error {
+	return NewGameService(NewGameRepository(tx)).Update(userID, previous, game)
 }
@@ -2,7 +2,6 @@ package service
 
 import (
 	"database/sql"
-	"gametracker/db"
 	"gametracker/models"
 	"testing"
 	"time"
@@ -14,8 +13,9 @@ import (
 	"gorm.io/gorm"
 )
 
-// setupTestDB crea una base de datos mock para testing
-func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
+// newMockDB crea una base de datos mock para testing. Los services la
+// reciben por constructor, así que los tests pueden correr en paralelo.
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
 	sqlDB, mock, err := sqlmock.New()
 	require.NoError(t, err)
 
@@ -25,16 +25,15 @@ func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, *sql.DB) {
 	}), &gorm.Config{})
 	require.NoError(t, err)
 
-	// Configurar la variable global db.DB para que las funciones de servicio la usen
-	db.DB = gormDB
-
 	return gormDB, mock, sqlDB
 }
 
 func TestGetAllGames(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	// Mock de datos de prueba
 	games := []models.Game{
@@ -73,11 +72,12 @@ func TestGetAllGames(t *testing.T) {
 		AddRow(games[0].ID, games[0].Title, games[0].Platform, games[0].Genre, games[0].Status, games[0].Progress, games[0].HoursPlayed, games[0].PersonalNote, games[0].Score, games[0].StartedAt, games[0].FinishedAt, games[0].CoverURL, games[0].CreatedAt, games[0].UpdatedAt).
 		AddRow(games[1].ID, games[1].Title, games[1].Platform, games[1].Genre, games[1].Status, games[1].Progress, games[1].HoursPlayed, games[1].PersonalNote, games[1].Score, games[1].StartedAt, games[1].FinishedAt, games[1].CoverURL, games[1].CreatedAt, games[1].UpdatedAt)
 
-	mock.ExpectQuery("SELECT \\* FROM `games`").
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\?").
+		WithArgs(uint(1)).
 		WillReturnRows(rows)
 
 	// Act
-	result, err := GetAllGames()
+	result, err := gameService.GetAll(1)
 
 	// Assert
 	require.NoError(t, err)
@@ -88,8 +88,10 @@ func TestGetAllGames(t *testing.T) {
 
 func TestGetGameByID_Success(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	game := models.Game{
 		ID:           1,
@@ -109,12 +111,12 @@ func TestGetGameByID_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "title", "platform", "genre", "status", "progress", "hours_played", "personal_note", "score", "started_at", "finished_at", "cover_url", "created_at", "updated_at"}).
 		AddRow(game.ID, game.Title, game.Platform, game.Genre, game.Status, game.Progress, game.HoursPlayed, game.PersonalNote, game.Score, game.StartedAt, game.FinishedAt, game.CoverURL, game.CreatedAt, game.UpdatedAt)
 
-	mock.ExpectQuery("SELECT \\* FROM `games` WHERE `games`.`id` = \\? ORDER BY `games`.`id` LIMIT \\?").
-		WithArgs("1", 1).
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\? AND `games`.`deleted_at` IS NULL ORDER BY `games`.`id` LIMIT \\?").
+		WithArgs(uint(1), "1", 1).
 		WillReturnRows(rows)
 
 	// Act
-	result, err := GetGameByID("1")
+	result, err := gameService.Get(1, "1")
 
 	// Assert
 	require.NoError(t, err)
@@ -124,15 +126,17 @@ func TestGetGameByID_Success(t *testing.T) {
 
 func TestGetGameByID_NotFound(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
-	mock.ExpectQuery("SELECT \\* FROM `games` WHERE `games`.`id` = \\? ORDER BY `games`.`id` LIMIT \\?").
-		WithArgs("999", 1).
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\? AND `games`.`deleted_at` IS NULL ORDER BY `games`.`id` LIMIT \\?").
+		WithArgs(uint(1), "999", 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	// Act
-	result, err := GetGameByID("999")
+	result, err := gameService.Get(1, "999")
 
 	// Assert
 	assert.Error(t, err)
@@ -142,14 +146,16 @@ func TestGetGameByID_NotFound(t *testing.T) {
 
 func TestCreateGame_Success(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	game := &models.Game{
 		Title:        "New Game",
 		Platform:     "PC",
 		Genre:        "RPG",
-		Status:       "Not Started",
+		Status:       "Backlog",
 		Progress:     0,
 		HoursPlayed:  0,
 		PersonalNote: "New game to play",
@@ -165,80 +171,18 @@ func TestCreateGame_Success(t *testing.T) {
 	mock.ExpectCommit()
 
 	// Act
-	err := CreateGame(game)
-
-	// Assert
-	require.NoError(t, err)
-}
-
-func TestGetStats_Success(t *testing.T) {
-	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
-	defer sqlDB.Close()
-
-	// Mock de datos para estadísticas
-	games := []models.Game{
-		{
-			ID:          1,
-			Title:       "Game 1",
-			Platform:    "PC",
-			Genre:       "RPG",
-			Status:      "Completed",
-			Progress:    100,
-			HoursPlayed: 25.5,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          2,
-			Title:       "Game 2",
-			Platform:    "PS5",
-			Genre:       "RPG",
-			Status:      "Playing",
-			Progress:    50,
-			HoursPlayed: 15.0,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          3,
-			Title:       "Game 3",
-			Platform:    "Xbox",
-			Genre:       "Action",
-			Status:      "Not Started",
-			Progress:    0,
-			HoursPlayed: 0,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-	}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "platform", "genre", "status", "progress", "hours_played", "personal_note", "score", "started_at", "finished_at", "cover_url", "created_at", "updated_at"})
-	for _, game := range games {
-		rows.AddRow(game.ID, game.Title, game.Platform, game.Genre, game.Status, game.Progress, game.HoursPlayed, game.PersonalNote, game.Score, game.StartedAt, game.FinishedAt, game.CoverURL, game.CreatedAt, game.UpdatedAt)
-	}
-
-	mock.ExpectQuery("SELECT \\* FROM `games`").
-		WillReturnRows(rows)
-
-	// Act
-	stats, err := GetStats()
+	err := gameService.Create(game)
 
 	// Assert
 	require.NoError(t, err)
-	assert.Equal(t, 3, stats.TotalGames)
-	assert.Equal(t, 2, stats.PendingGames) // Game 2 (Playing, Progress 50) y Game 3 (Not Started, Progress 0) están pendientes
-	assert.Equal(t, "RPG", stats.MostPlayedGenre)
-	assert.Equal(t, 13.5, stats.AverageHours) // (25.5 + 15.0 + 0) / 3
-	assert.Equal(t, 1, stats.ByStatus["Completed"])
-	assert.Equal(t, 1, stats.ByStatus["Playing"])
-	assert.Equal(t, 1, stats.ByStatus["Not Started"])
 }
 
 func TestGetByTitle_Success(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	game := models.Game{
 		ID:           1,
@@ -258,12 +202,12 @@ func TestGetByTitle_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "title", "platform", "genre", "status", "progress", "hours_played", "personal_note", "score", "started_at", "finished_at", "cover_url", "created_at", "updated_at"}).
 		AddRow(game.ID, game.Title, game.Platform, game.Genre, game.Status, game.Progress, game.HoursPlayed, game.PersonalNote, game.Score, game.StartedAt, game.FinishedAt, game.CoverURL, game.CreatedAt, game.UpdatedAt)
 
-	mock.ExpectQuery("SELECT \\* FROM `games` WHERE title LIKE \\?").
-		WithArgs("%Test%").
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND title LIKE \\?").
+		WithArgs(uint(1), "%Test%").
 		WillReturnRows(rows)
 
 	// Act
-	result, err := GetByTitle("Test")
+	result, err := gameService.GetByTitle(1, "Test")
 
 	// Assert
 	require.NoError(t, err)
@@ -273,8 +217,10 @@ func TestGetByTitle_Success(t *testing.T) {
 
 func TestGetByStatus_Success(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	game := models.Game{
 		ID:           1,
@@ -294,12 +240,12 @@ func TestGetByStatus_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "title", "platform", "genre", "status", "progress", "hours_played", "personal_note", "score", "started_at", "finished_at", "cover_url", "created_at", "updated_at"}).
 		AddRow(game.ID, game.Title, game.Platform, game.Genre, game.Status, game.Progress, game.HoursPlayed, game.PersonalNote, game.Score, game.StartedAt, game.FinishedAt, game.CoverURL, game.CreatedAt, game.UpdatedAt)
 
-	mock.ExpectQuery("SELECT \\* FROM `games` WHERE status LIKE \\?").
-		WithArgs("%Completed%").
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND status = \\?").
+		WithArgs(uint(1), "Completed").
 		WillReturnRows(rows)
 
 	// Act
-	result, err := GetByStatus("Completed")
+	result, err := gameService.GetByStatus(1, "Completed")
 
 	// Assert
 	require.NoError(t, err)
@@ -309,8 +255,10 @@ func TestGetByStatus_Success(t *testing.T) {
 
 func TestGetByGenre_Success(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	game := models.Game{
 		ID:           1,
@@ -330,12 +278,12 @@ func TestGetByGenre_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "title", "platform", "genre", "status", "progress", "hours_played", "personal_note", "score", "started_at", "finished_at", "cover_url", "created_at", "updated_at"}).
 		AddRow(game.ID, game.Title, game.Platform, game.Genre, game.Status, game.Progress, game.HoursPlayed, game.PersonalNote, game.Score, game.StartedAt, game.FinishedAt, game.CoverURL, game.CreatedAt, game.UpdatedAt)
 
-	mock.ExpectQuery("SELECT \\* FROM `games` WHERE genre LIKE \\?").
-		WithArgs("%RPG%").
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND genre LIKE \\?").
+		WithArgs(uint(1), "%RPG%").
 		WillReturnRows(rows)
 
 	// Act
-	result, err := GetByGenre("RPG")
+	result, err := gameService.GetByGenre(1, "RPG")
 
 	// Assert
 	require.NoError(t, err)
@@ -345,8 +293,10 @@ func TestGetByGenre_Success(t *testing.T) {
 
 func TestUpdateGame_Success(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	game := &models.Game{
 		ID:           1,
@@ -363,32 +313,87 @@ func TestUpdateGame_Success(t *testing.T) {
 		UpdatedAt:    time.Now(),
 	}
 
+	expectHoursDerived(mock, 0)
 	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `games` SET").
+	mock.ExpectExec("UPDATE `games` SET .* WHERE \\(user_id = \\? AND version = \\?\\) AND `games`.`deleted_at` IS NULL AND `id` = \\?").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
 	// Act
-	err := UpdateGame(game)
+	previous := models.Game{ID: 1, Status: "Playing", Version: 3}
+	err := gameService.Update(1, previous, game)
 
 	// Assert
 	require.NoError(t, err)
+	assert.Equal(t, uint(1), game.UserID)
+	assert.Equal(t, uint(4), game.Version)
+	assert.NotNil(t, game.FinishedAt) // Playing -> Completed estampa la fecha de fin
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateGame_VersionConflict(t *testing.T) {
+	// Arrange
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
+
+	game := &models.Game{ID: 1, Title: "Game", Platform: "PC", Status: "Playing"}
+
+	// Otro request ya incrementó la versión: el UPDATE no afecta filas
+	expectHoursDerived(mock, 0)
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET .* WHERE \\(user_id = \\? AND version = \\?\\) AND `games`.`deleted_at` IS NULL AND `id` = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	err := gameService.Update(1, models.Game{ID: 1, Status: "Playing", Version: 2}, game)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Equal(t, uint(2), game.Version)
+	require.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestDeleteGame_Success(t *testing.T) {
 	// Arrange
-	_, mock, sqlDB := setupTestDB(t)
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
 	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
 
 	mock.ExpectBegin()
-	mock.ExpectExec("DELETE FROM `games` WHERE `games`.`id` = \\?").
-		WithArgs("1").
+	mock.ExpectExec("UPDATE `games` SET `deleted_at`=\\? WHERE user_id = \\? AND `games`.`id` = \\? AND `games`.`deleted_at` IS NULL").
+		WithArgs(sqlmock.AnyArg(), uint(1), "1").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
 	// Act
-	err := DeleteGame("1")
+	err := gameService.Delete(1, "1")
 
 	// Assert
 	require.NoError(t, err)
 }
+
+func TestDeleteGame_OtherUsersGame(t *testing.T) {
+	// Arrange
+	t.Parallel()
+	gormDB, mock, sqlDB := newMockDB(t)
+	defer sqlDB.Close()
+	gameService := NewGameService(NewGameRepository(gormDB))
+
+	// El juego existe pero pertenece a otro usuario: el DELETE no afecta filas
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET `deleted_at`=\\? WHERE user_id = \\? AND `games`.`id` = \\? AND `games`.`deleted_at` IS NULL").
+		WithArgs(sqlmock.AnyArg(), uint(2), "1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	err := gameService.Delete(2, "1")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
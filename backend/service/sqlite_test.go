@@ -0,0 +1,348 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gametracker/db"
+	"gametracker/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Estos tests corren contra una base SQLite en memoria con las migraciones
+// reales, sin Docker ni MySQL: cubren el SQL que los mocks no ejecutan.
+
+// setupSQLiteDB abre una base en memoria migrada y crea el usuario 1.
+func setupSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn, err := db.Open(db.Config{Driver: db.DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	migrator, err := db.NewMigrator(conn)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	require.NoError(t, conn.Create(&models.User{ID: 1, Username: "ana", Email: "ana@example.com", Password: "x"}).Error)
+	return conn
+}
+
+func createSQLiteGame(t *testing.T, conn *gorm.DB, game models.Game) models.Game {
+	t.Helper()
+	game.UserID = 1
+	require.NoError(t, NewGameService(NewGameRepository(conn)).Create(&game))
+	return game
+}
+
+func TestSQLite_ListGamesFiltersIgnoreCase(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Genre: "Roguelike", Status: models.StatusPlaying, Score: 9})
+	createSQLiteGame(t, conn, models.Game{Title: "Hollow Knight", Platform: "Switch", Genre: "Metroidvania", Status: models.StatusBacklog})
+	createSQLiteGame(t, conn, models.Game{Title: "Celeste", Platform: "PC", Genre: "Platformer", Status: models.StatusBacklog})
+
+	page, err := NewGameService(NewGameRepository(conn)).List(1, models.GameListQuery{
+		Filter: models.GameFilter{Title: "ho", Platform: "switch"},
+		Sort:   "-title",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Hollow Knight", page.Items[0].Title)
+}
+
+func TestSQLite_ListGamesCursor(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		at := started.AddDate(0, 0, i)
+		createSQLiteGame(t, conn, models.Game{Title: fmt.Sprintf("Game %d", i), Platform: "PC", Status: models.StatusPlaying, StartedAt: &at})
+	}
+	createSQLiteGame(t, conn, models.Game{Title: "Sin empezar", Platform: "PC", Status: models.StatusBacklog})
+	games := NewGameService(NewGameRepository(conn))
+
+	var titles []string
+	q := models.GameListQuery{Sort: "-startedAt", PageSize: 2}
+	for {
+		page, err := games.List(1, q)
+		require.NoError(t, err)
+		for _, g := range page.Items {
+			titles = append(titles, g.Title)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, []string{"Game 4", "Game 3", "Game 2", "Game 1", "Game 0", "Sin empezar"}, titles)
+}
+
+func TestSQLite_ScoreCheckConstraint(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	game := createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Status: models.StatusPlaying})
+
+	err := conn.Model(&models.Game{}).Where("id = ?", game.ID).Update("score", 11).Error
+
+	assert.ErrorContains(t, err, "score_between_0_10")
+}
+
+func TestSQLite_SessionsAndOwnershipsDeriveHours(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	game := createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Status: models.StatusPlaying})
+	id := fmt.Sprint(game.ID)
+	ended := time.Date(2026, 3, 1, 11, 30, 0, 0, time.UTC)
+
+	require.NoError(t, NewSessionService(conn).CreateSession(1, id, &models.PlaySession{StartedAt: ended.Add(-90 * time.Minute), EndedAt: &ended, DurationMinutes: 90}))
+	hours := 2.25
+	_, err := NewOwnershipService(conn).CreateOwnership(1, id, models.OwnershipInput{Platform: "Switch", HoursPlayed: &hours})
+	require.NoError(t, err)
+
+	updated, err := NewGameService(NewGameRepository(conn)).Get(1, id)
+	require.NoError(t, err)
+	// 90 minutos son 1.5 horas: la división no trunca a entero.
+	assert.InDelta(t, 3.75, updated.HoursPlayed, 0.001)
+	assert.Equal(t, game.Version+2, updated.Version)
+}
+
+func TestSQLite_FirstSessionKeepsManualHours(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	games := NewGameService(NewGameRepository(conn))
+	sessions := NewSessionService(conn)
+	ended := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	logged := createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Status: models.StatusPlaying, HoursPlayed: 40})
+	require.NoError(t, sessions.CreateSession(1, fmt.Sprint(logged.ID), &models.PlaySession{StartedAt: ended.Add(-time.Hour), EndedAt: &ended, DurationMinutes: 60}))
+
+	timed := createSQLiteGame(t, conn, models.Game{Title: "Celeste", Platform: "Switch", Status: models.StatusPlaying, HoursPlayed: 10})
+	fixNow(t, ended.Add(-30*time.Minute))
+	_, err := sessions.StartSession(1, fmt.Sprint(timed.ID), nil)
+	require.NoError(t, err)
+	fixNow(t, ended)
+	_, err = sessions.StopSession(1, fmt.Sprint(timed.ID))
+	require.NoError(t, err)
+
+	updated, err := games.Get(1, fmt.Sprint(logged.ID))
+	require.NoError(t, err)
+	assert.InDelta(t, 41, updated.HoursPlayed, 0.001)
+	updated, err = games.Get(1, fmt.Sprint(timed.ID))
+	require.NoError(t, err)
+	assert.InDelta(t, 10.5, updated.HoursPlayed, 0.001)
+
+	ownerships, err := NewOwnershipService(conn).ListOwnerships(1, fmt.Sprint(logged.ID))
+	require.NoError(t, err)
+	require.Len(t, ownerships, 1)
+	assert.Equal(t, "PC", ownerships[0].Platform)
+	assert.InDelta(t, 40, ownerships[0].HoursPlayed, 0.001)
+}
+
+func TestSQLite_AchievementProgress(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	game := createSQLiteGame(t, conn, models.Game{Title: "Celeste", Platform: "PC", Status: models.StatusPlaying, ProgressFromAchievements: true})
+	id := fmt.Sprint(game.ID)
+
+	achievements, err := NewAchievementService(conn).ImportAchievements(1, id, models.AchievementImportInput{Achievements: []models.AchievementInput{
+		{Name: "Prologue", Unlocked: true},
+		{Name: "Forsaken City"},
+		{Name: "Old Site"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, achievements, 3)
+	_, err = NewAchievementService(conn).CreateAchievement(1, id, models.AchievementInput{Name: "PROLOGUE"})
+	assert.ErrorIs(t, err, ErrAchievementExists)
+
+	_, err = NewAchievementService(conn).ToggleAchievement(1, id, fmt.Sprint(achievements[1].ID))
+	require.NoError(t, err)
+
+	updated, err := NewGameService(NewGameRepository(conn)).Get(1, id)
+	require.NoError(t, err)
+	assert.Equal(t, 67, updated.Progress)
+}
+
+func TestSQLite_SearchGames(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	createSQLiteGame(t, conn, models.Game{Title: "Pokémon Legends", Platform: "Switch", Status: models.StatusPlaying, PersonalNote: "Capturar todo"})
+	createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Status: models.StatusPlaying, PersonalNote: "Mejor que pokemon"})
+	createSQLiteGame(t, conn, models.Game{Title: "Celeste", Platform: "PC", Status: models.StatusPlaying, PersonalNote: "Superpokemon no cuenta"})
+	createSQLiteGame(t, conn, models.Game{Title: "Hollow Knight", Platform: "PC", Status: models.StatusPlaying, PersonalNote: "Nada que ver"})
+
+	results, err := NewSearchService(conn).SearchGames(1, "POKEMON", 10)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	// El match en el título pesa más que el de la nota.
+	assert.Equal(t, "<mark>Pokémon</mark> Legends", results[0].Highlights.Title)
+	assert.Equal(t, "Hades", results[1].Game.Title)
+	assert.Equal(t, "Mejor que <mark>pokemon</mark>", results[1].Highlights.PersonalNote)
+}
+
+func TestSQLite_StatsByTag(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	hades := createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Genre: "Roguelike", Status: models.StatusCompleted, HoursPlayed: 40, Score: 9})
+	createSQLiteGame(t, conn, models.Game{Title: "Celeste", Platform: "PC", Genre: "Platformer", Status: models.StatusBacklog, HoursPlayed: 5})
+	_, err := NewTagService(conn).SetGameTags(1, fmt.Sprint(hades.ID), []string{"Indie", "Co-op"})
+	require.NoError(t, err)
+
+	stats, err := NewStatsService(conn).GetStats(1, models.GameFilter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.TotalGames)
+	assert.Equal(t, 45.0, stats.TotalHours)
+	assert.Equal(t, "Roguelike", stats.MostPlayedGenre)
+	require.Len(t, stats.HoursByTag, 2)
+	assert.Equal(t, 40.0, stats.HoursByTag[0].Hours)
+	assert.Equal(t, 1, stats.HoursByTag[0].Completed)
+	assert.Equal(t, models.PlaytimeStats{Games: 2, P25: 13.75, Median: 22.5, P75: 31.25, P90: 36.5, Max: 40}, stats.Playtime)
+	assert.Equal(t, 1, stats.Backlog.Games)
+	assert.Equal(t, "Celeste", stats.Backlog.OldestTitle)
+	assert.InDelta(t, 0, stats.Backlog.MedianDays, 0.01)
+}
+
+func TestSQLite_BacklogAgeInDays(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	old := createSQLiteGame(t, conn, models.Game{Title: "Viejo", Platform: "PC", Status: models.StatusBacklog})
+	createSQLiteGame(t, conn, models.Game{Title: "Nuevo", Platform: "PC", Status: models.StatusBacklog})
+	require.NoError(t, conn.Model(&models.Game{}).Where("id = ?", old.ID).Update("created_at", time.Now().AddDate(0, 0, -30)).Error)
+	fixNow(t, time.Now().AddDate(0, 0, 10))
+
+	stats, err := NewStatsService(conn).GetStats(1, models.GameFilter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Backlog.Games)
+	assert.Equal(t, "Viejo", stats.Backlog.OldestTitle)
+	assert.InDelta(t, 40, stats.Backlog.OldestDays, 0.01)
+	assert.InDelta(t, 25, stats.Backlog.AverageDays, 0.01)
+	assert.InDelta(t, 25, stats.Backlog.MedianDays, 0.01)
+}
+
+func TestSQLite_PlatformsFromOwnerships(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	hades := createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Status: models.StatusPlaying})
+	createSQLiteGame(t, conn, models.Game{Title: "Hollow Knight", Platform: "Switch", Status: models.StatusPlaying, HoursPlayed: 1})
+	createSQLiteGame(t, conn, models.Game{Title: "Celeste", Platform: "PS5", Status: models.StatusCompleted, HoursPlayed: 5})
+	id := fmt.Sprint(hades.ID)
+	ownerships := NewOwnershipService(conn)
+	hours := 10.0
+	_, err := ownerships.CreateOwnership(1, id, models.OwnershipInput{Platform: "PC", HoursPlayed: &hours})
+	require.NoError(t, err)
+	switchCopy, err := ownerships.CreateOwnership(1, id, models.OwnershipInput{Platform: "Switch"})
+	require.NoError(t, err)
+	sessions := NewSessionService(conn)
+	ended := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, sessions.CreateSession(1, id, &models.PlaySession{StartedAt: ended.Add(-2 * time.Hour), EndedAt: &ended, DurationMinutes: 120, OwnershipID: &switchCopy.ID}))
+	require.NoError(t, sessions.CreateSession(1, id, &models.PlaySession{StartedAt: ended.Add(-time.Hour), EndedAt: &ended, DurationMinutes: 60}))
+
+	stats, err := NewStatsService(conn).GetStats(1, models.GameFilter{})
+	require.NoError(t, err)
+	// La sesión sin copia cuenta en la primera copia (PC).
+	assert.Equal(t, []models.PlatformHours{
+		{Platform: "PC", Games: 1, Hours: 11},
+		{Platform: "PS5", Games: 1, Hours: 5},
+		{Platform: "Switch", Games: 2, Hours: 3},
+	}, stats.HoursByPlatform)
+
+	page, err := NewGameService(NewGameRepository(conn)).List(1, models.GameListQuery{Filter: models.GameFilter{Platform: "switch"}, Sort: "title"})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "Hades", page.Items[0].Title)
+
+	other := createSQLiteGame(t, conn, models.Game{Title: "Celeste 2", Platform: "PC", Status: models.StatusBacklog})
+	_, err = sessions.StartSession(1, fmt.Sprint(other.ID), &switchCopy.ID)
+	assert.ErrorIs(t, err, ErrOwnershipNotFound)
+
+	report, err := NewTransferService(conn).ImportGames(1, []models.ImportRecord{
+		{Line: 2, Input: models.GameInput{Title: "Hades", Platform: "Switch", Status: models.StatusPlaying}},
+	}, models.ImportOptions{})
+	require.NoError(t, err)
+	require.Len(t, report.Rows, 1)
+	assert.Equal(t, models.ImportSkipped, report.Rows[0].Action)
+	assert.Equal(t, hades.ID, report.Rows[0].GameID)
+}
+
+func TestSQLite_PromoteWishlistItemAlreadyOwned(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	hades := createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Status: models.StatusPlaying})
+	_, err := NewOwnershipService(conn).CreateOwnership(1, fmt.Sprint(hades.ID), models.OwnershipInput{Platform: "Switch"})
+	require.NoError(t, err)
+	wishlist := NewWishlistService(conn)
+	item := models.WishlistItem{UserID: 1, Title: "Hades", Platform: "switch", Priority: models.DefaultWishlistPriority}
+	require.NoError(t, wishlist.CreateWishlistItem(&item))
+
+	game, err := wishlist.PromoteWishlistItem(1, fmt.Sprint(item.ID), models.PromoteInput{})
+	assert.ErrorIs(t, err, ErrGameInLibrary)
+	assert.Equal(t, hades.ID, game.ID)
+	_, err = wishlist.GetWishlistItem(1, fmt.Sprint(item.ID))
+	require.NoError(t, err)
+
+	game, err = wishlist.PromoteWishlistItem(1, fmt.Sprint(item.ID), models.PromoteInput{Platform: "PS5"})
+	require.NoError(t, err)
+	assert.NotEqual(t, hades.ID, game.ID)
+}
+
+func TestSQLite_ImportWishlistRows(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Status: models.StatusPlaying})
+
+	report, err := NewTransferService(conn).ImportGames(1, []models.ImportRecord{
+		{Line: 2, Input: models.GameInput{Title: "Silksong", Platform: "Nintendo Switch", Status: models.StatusWishlist}, Wishlist: true},
+		{Line: 3, Input: models.GameInput{Title: "Hades", Platform: "PC", Status: models.StatusWishlist}, Wishlist: true},
+	}, models.ImportOptions{UpdateExisting: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Wishlisted)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, models.ImportWishlisted, report.Rows[0].Action)
+	assert.Equal(t, "game already in library", report.Rows[1].Message)
+	items, err := NewWishlistService(conn).ListWishlist(1)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, report.Rows[0].WishlistItemID, items[0].ID)
+	var games int64
+	require.NoError(t, conn.Model(&models.Game{}).Count(&games).Error)
+	assert.Equal(t, int64(1), games)
+}
+
+func TestSQLite_TimelineAndYearReview(t *testing.T) {
+	conn := setupSQLiteDB(t)
+	date := func(year int, month time.Month, d int) *time.Time {
+		at := time.Date(year, month, d, 12, 0, 0, 0, time.Local)
+		return &at
+	}
+	day := func(month time.Month, d int) *time.Time { return date(2025, month, d) }
+	hades := createSQLiteGame(t, conn, models.Game{Title: "Hades", Platform: "PC", Genre: "Roguelike", Status: models.StatusCompleted,
+		StartedAt: day(2, 3), FinishedAt: day(3, 20)})
+	createSQLiteGame(t, conn, models.Game{Title: "Celeste", Platform: "PC", Genre: "Platformer", Status: models.StatusCompleted,
+		HoursPlayed: 8, StartedAt: day(3, 1), FinishedAt: day(3, 5)})
+	createSQLiteGame(t, conn, models.Game{Title: "Viejo", Platform: "PC", Genre: "Roguelike", Status: models.StatusCompleted,
+		HoursPlayed: 30, StartedAt: date(2022, 1, 1), FinishedAt: date(2022, 1, 2)})
+	require.NoError(t, NewSessionService(conn).CreateSession(1, fmt.Sprint(hades.ID),
+		&models.PlaySession{StartedAt: *day(2, 10), EndedAt: day(2, 10), DurationMinutes: 90}))
+
+	from, to := *day(1, 1), *day(3, 31)
+	buckets, err := NewStatsService(conn).GetTimeline(1, models.GameFilter{}, TimelineMonth, &from, &to)
+	require.NoError(t, err)
+	require.Len(t, buckets, 3)
+	assert.Equal(t, 1, buckets[1].Started)
+	assert.Equal(t, 1.5, buckets[1].SessionHours)
+	assert.Equal(t, 1, buckets[2].Started)
+	assert.Equal(t, 2, buckets[2].Finished)
+	assert.Equal(t, 9.5, buckets[2].Hours)
+
+	review, err := NewStatsService(conn).GetYearReview(1, 2025)
+	require.NoError(t, err)
+	assert.Equal(t, 2, review.GamesFinished)
+	assert.Equal(t, 9.5, review.HoursPlayed)
+	require.Len(t, review.TopGames, 2)
+	assert.Equal(t, "Celeste", review.TopGames[0].Title)
+	// Roguelike ya se había jugado antes.
+	assert.Equal(t, []string{"Platformer"}, review.GenresDiscovered)
+	assert.Equal(t, models.CompletionStreak{Months: 1, From: "2025-03", To: "2025-03"}, review.CompletionStreak)
+}
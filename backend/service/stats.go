@@ -0,0 +1,326 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/db"
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+type statsTotals struct {
+	TotalGames   int
+	TotalHours   float64
+	AverageHours float64
+	Completed    int
+	Wishlist     int
+	Pending      int
+}
+
+type statusCount struct {
+	Status string
+	Games  int
+}
+
+type genreRow struct {
+	Genre       string
+	Games       int
+	Hours       float64
+	ScoredGames int
+	ScoreSum    float64
+}
+
+type playtimeRow struct {
+	Games    int
+	MaxHours float64
+}
+
+type backlogRow struct {
+	Games       int
+	AverageDays float64
+	OldestDays  float64
+}
+
+// StatsService calcula las estadísticas, la línea de tiempo y el resumen
+// anual de la biblioteca.
+type StatsService struct {
+	conn *gorm.DB
+}
+
+func NewStatsService(conn *gorm.DB) *StatsService {
+	return &StatsService{conn: conn}
+}
+
+// GetStats calcula las estadísticas de la biblioteca del usuario con
+// agregaciones SQL, aplicando los mismos filtros que el listado. Los
+// percentiles se interpolan en Go, pero solo con las filas vecinas de cada
+// uno (ver sortedColumn).
+func (s *StatsService) GetStats(userID uint, filter models.GameFilter) (models.GameStats, error) {
+	stats := models.GameStats{
+		ByStatus:            map[string]int{},
+		HoursByGenre:        []models.GenreHours{},
+		HoursByPlatform:     []models.PlatformHours{},
+		AverageScoreByGenre: []models.GenreScore{},
+		HoursByTag:          []models.TagHours{},
+	}
+	if err := validateGameFilter(filter); err != nil {
+		return stats, err
+	}
+	base := applyGameFilters(s.conn.Model(&models.Game{}).Where("user_id = ?", userID), filter)
+	query := func() *gorm.DB { return base.Session(&gorm.Session{}) }
+
+	var totals statsTotals
+	err := query().Select(
+		"COUNT(*) AS total_games, "+
+			"COALESCE(SUM(hours_played), 0) AS total_hours, "+
+			"COALESCE(AVG(hours_played), 0) AS average_hours, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS completed, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS wishlist, "+
+			"COALESCE(SUM(CASE WHEN status <> ? AND progress < 100 THEN 1 ELSE 0 END), 0) AS pending",
+		models.StatusCompleted, models.StatusWishlist, models.StatusCompleted,
+	).Scan(&totals).Error
+	if err != nil {
+		return stats, err
+	}
+	stats.TotalGames = totals.TotalGames
+	stats.TotalHours = models.RoundHours(totals.TotalHours)
+	stats.AverageHours = models.RoundHours(totals.AverageHours)
+	stats.PendingGames = totals.Pending
+	if owned := totals.TotalGames - totals.Wishlist; owned > 0 {
+		stats.CompletionRate = math.Round(float64(totals.Completed)/float64(owned)*1000) / 1000
+	}
+
+	var statuses []statusCount
+	if err := query().Select("status, COUNT(*) AS games").Group("status").Scan(&statuses).Error; err != nil {
+		return stats, err
+	}
+	for _, s := range statuses {
+		stats.ByStatus[s.Status] = s.Games
+	}
+
+	var genres []genreRow
+	err = query().Select(
+		"genre, COUNT(*) AS games, COALESCE(SUM(hours_played), 0) AS hours, " +
+			"COALESCE(SUM(CASE WHEN score > 0 THEN 1 ELSE 0 END), 0) AS scored_games, " +
+			"COALESCE(SUM(CASE WHEN score > 0 THEN score ELSE 0 END), 0) AS score_sum",
+	).Where("genre <> ''").Group("genre").Order("hours DESC, genre ASC").Scan(&genres).Error
+	if err != nil {
+		return stats, err
+	}
+	for i, g := range genres {
+		stats.HoursByGenre = append(stats.HoursByGenre, models.GenreHours{
+			Rank: i + 1, Genre: g.Genre, Games: g.Games, Hours: models.RoundHours(g.Hours),
+		})
+		if g.ScoredGames > 0 {
+			stats.AverageScoreByGenre = append(stats.AverageScoreByGenre, models.GenreScore{
+				Genre:        g.Genre,
+				ScoredGames:  g.ScoredGames,
+				AverageScore: models.RoundHours(g.ScoreSum / float64(g.ScoredGames)),
+			})
+		}
+	}
+	if len(genres) > 0 && genres[0].Hours > 0 {
+		stats.MostPlayedGenre = genres[0].Genre
+	}
+
+	if stats.HoursByPlatform, err = s.hoursByPlatform(query); err != nil {
+		return stats, err
+	}
+
+	if stats.Playtime, err = playtimeStats(query); err != nil {
+		return stats, err
+	}
+	if stats.Backlog, err = s.backlogAge(query, clock.Now()); err != nil {
+		return stats, err
+	}
+
+	// Los filtros se aplican en la subconsulta para que sus columnas no
+	// choquen con las de tags.
+	err = s.conn.Table("(?) AS g", query().Select("id, hours_played, status")).
+		Select("tags.name AS tag, COUNT(*) AS games, COALESCE(SUM(g.hours_played), 0) AS hours, "+
+			"COALESCE(SUM(CASE WHEN g.status = ? THEN 1 ELSE 0 END), 0) AS completed", models.StatusCompleted).
+		Joins("JOIN game_tags ON game_tags.game_id = g.id").
+		Joins("JOIN tags ON tags.id = game_tags.tag_id").
+		Group("tags.id, tags.name").Order("hours DESC, tag ASC").
+		Scan(&stats.HoursByTag).Error
+	if err != nil {
+		return stats, err
+	}
+	for i := range stats.HoursByTag {
+		stats.HoursByTag[i].Hours = models.RoundHours(stats.HoursByTag[i].Hours)
+	}
+
+	return stats, nil
+}
+
+// ownershipHours son las horas de cada copia: las cargadas en la copia más
+// las de sus sesiones terminadas. Las sesiones sin copia de un juego que sí
+// tiene copias se cuentan en la primera.
+const ownershipHours = "o.hours_played + (SELECT COALESCE(SUM(ps.duration_minutes), 0) FROM play_sessions ps " +
+	"WHERE ps.game_id = o.game_id AND ps.ended_at IS NOT NULL AND (ps.ownership_id = o.id OR " +
+	"(ps.ownership_id IS NULL AND o.id = (SELECT MIN(fo.id) FROM game_ownerships fo WHERE fo.game_id = o.game_id)))) / 60.0"
+
+// hoursByPlatform reparte las horas de los juegos de query entre las
+// plataformas de sus copias (GameOwnership); los juegos sin copias cuentan
+// en Game.Platform. Un juego con copias en dos plataformas cuenta en las
+// dos. Las plataformas se agrupan sin distinguir mayúsculas.
+func (s *StatsService) hoursByPlatform(query func() *gorm.DB) ([]models.PlatformHours, error) {
+	var owned, unowned []models.PlatformHours
+	copies := s.conn.Table("game_ownerships AS o").
+		Select("o.game_id, o.platform, "+ownershipHours+" AS hours").
+		Where("o.game_id IN (?)", query().Select("id"))
+	err := s.conn.Table("(?) AS p", copies).
+		Select("platform, COUNT(DISTINCT game_id) AS games, COALESCE(SUM(hours), 0) AS hours").
+		Group("platform").Scan(&owned).Error
+	if err != nil {
+		return nil, err
+	}
+	err = query().Select("platform, COUNT(*) AS games, COALESCE(SUM(hours_played), 0) AS hours").
+		Where("NOT EXISTS (SELECT 1 FROM game_ownerships WHERE game_ownerships.game_id = games.id)").
+		Group("platform").Scan(&unowned).Error
+	if err != nil {
+		return nil, err
+	}
+
+	platforms := []models.PlatformHours{}
+	index := map[string]int{}
+	for _, row := range append(owned, unowned...) {
+		key := strings.ToLower(row.Platform)
+		i, ok := index[key]
+		if !ok {
+			i = len(platforms)
+			index[key] = i
+			platforms = append(platforms, models.PlatformHours{Platform: row.Platform})
+		}
+		platforms[i].Games += row.Games
+		platforms[i].Hours += row.Hours
+	}
+	for i := range platforms {
+		platforms[i].Hours = models.RoundHours(platforms[i].Hours)
+	}
+	sort.Slice(platforms, func(i, j int) bool {
+		if platforms[i].Hours != platforms[j].Hours {
+			return platforms[i].Hours > platforms[j].Hours
+		}
+		return platforms[i].Platform < platforms[j].Platform
+	})
+	return platforms, nil
+}
+
+// playtimeStats calcula los percentiles de horas de los juegos jugados.
+func playtimeStats(query func() *gorm.DB) (models.PlaytimeStats, error) {
+	played := func() *gorm.DB { return query().Where("hours_played > 0") }
+	var row playtimeRow
+	if err := played().Select("COUNT(*) AS games, COALESCE(MAX(hours_played), 0) AS max_hours").Scan(&row).Error; err != nil {
+		return models.PlaytimeStats{}, err
+	}
+	stats := models.PlaytimeStats{Games: row.Games, Max: row.MaxHours}
+	if row.Games == 0 {
+		return stats, nil
+	}
+	hours := newSortedColumn(row.Games, func() *gorm.DB {
+		return played().Select("hours_played").Order("hours_played ASC")
+	})
+	for _, pc := range []struct {
+		p    float64
+		dest *float64
+	}{{25, &stats.P25}, {50, &stats.Median}, {75, &stats.P75}, {90, &stats.P90}} {
+		value, err := hours.percentile(pc.p)
+		if err != nil {
+			return stats, err
+		}
+		*pc.dest = models.RoundHours(value)
+	}
+	return stats, nil
+}
+
+// ageDaysExpr es la expresión SQL con los días que pasaron desde created_at
+// hasta el parámetro; cada motor resta fechas a su manera.
+func ageDaysExpr(conn *gorm.DB) string {
+	switch conn.Dialector.Name() {
+	case db.DriverPostgres:
+		return "EXTRACT(EPOCH FROM (CAST(? AS timestamptz) - created_at)) / 86400"
+	case db.DriverSQLite:
+		return "(julianday(?) - julianday(created_at))"
+	}
+	return "TIMESTAMPDIFF(SECOND, created_at, ?) / 86400"
+}
+
+// backlogAge mide hace cuántos días, a la fecha at, esperan los juegos en
+// Backlog.
+func (s *StatsService) backlogAge(query func() *gorm.DB, at time.Time) (models.BacklogAge, error) {
+	backlog := func() *gorm.DB { return query().Where("status = ?", models.StatusBacklog) }
+	ageDays := ageDaysExpr(s.conn)
+	var row backlogRow
+	err := backlog().Select("COUNT(*) AS games, COALESCE(AVG("+ageDays+"), 0) AS average_days, "+
+		"COALESCE(MAX("+ageDays+"), 0) AS oldest_days", at, at).Scan(&row).Error
+	if err != nil || row.Games == 0 {
+		return models.BacklogAge{}, err
+	}
+	age := models.BacklogAge{
+		Games:       row.Games,
+		AverageDays: models.RoundHours(row.AverageDays),
+		OldestDays:  models.RoundHours(row.OldestDays),
+	}
+	var oldest []string
+	if err := backlog().Order("created_at ASC, id ASC").Limit(1).Pluck("title", &oldest).Error; err != nil {
+		return age, err
+	}
+	if len(oldest) > 0 {
+		age.OldestTitle = oldest[0]
+	}
+	// De la más nueva a la más vieja, las edades quedan ascendentes.
+	ages := newSortedColumn(row.Games, func() *gorm.DB {
+		return backlog().Select(ageDays+" AS age", at).Order("created_at DESC, id DESC")
+	})
+	median, err := ages.percentile(50)
+	age.MedianDays = models.RoundHours(median)
+	return age, err
+}
+
+// sortedColumn lee por posición los n valores de una consulta de una sola
+// columna ya ordenada de menor a mayor. Un percentil solo necesita las dos
+// filas vecinas, así que no se trae la tabla entera; las filas leídas se
+// guardan para los percentiles que caen en la misma posición.
+type sortedColumn struct {
+	n     int
+	query func() *gorm.DB
+	rows  map[int][]float64
+}
+
+func newSortedColumn(n int, query func() *gorm.DB) *sortedColumn {
+	return &sortedColumn{n: n, query: query, rows: map[int][]float64{}}
+}
+
+// percentile interpola linealmente entre las posiciones vecinas, igual que
+// PERCENTILE_CONT.
+func (c *sortedColumn) percentile(p float64) (float64, error) {
+	lower, frac := percentileRank(c.n, p)
+	pair, ok := c.rows[lower]
+	if !ok {
+		if err := c.query().Offset(lower).Limit(2).Scan(&pair).Error; err != nil {
+			return 0, err
+		}
+		c.rows[lower] = pair
+	}
+	switch {
+	case len(pair) == 0:
+		return 0, nil
+	case frac == 0 || len(pair) == 1:
+		return pair[0], nil
+	}
+	return pair[0] + (pair[1]-pair[0])*frac, nil
+}
+
+// percentileRank ubica el percentil p de n valores ordenados: la posición
+// (desde 0) anterior y cuánto se avanza hacia la siguiente.
+func percentileRank(n int, p float64) (int, float64) {
+	rank := p / 100 * float64(n-1)
+	lower := math.Floor(rank)
+	return int(lower), rank - lower
+}
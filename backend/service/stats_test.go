@@ -0,0 +1,149 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStats_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fixNow(t, at)
+
+	mock.ExpectQuery("^SELECT COUNT\\(\\*\\) AS total_games, .* AS pending FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL$").
+		WithArgs("Completed", "Wishlist", "Completed", uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"total_games", "total_hours", "average_hours", "completed", "wishlist", "pending"}).
+			AddRow(5, 52.5, 10.5, 1, 1, 3))
+	mock.ExpectQuery("^SELECT status, COUNT\\(\\*\\) AS games FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL GROUP BY `status`$").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "games"}).
+			AddRow("Completed", 1).AddRow("Playing", 2).AddRow("Backlog", 1).AddRow("Wishlist", 1))
+	// Action es el más frecuente, pero RPG el de más horas
+	mock.ExpectQuery("^SELECT genre, .* FROM `games` WHERE user_id = \\? AND genre <> '' AND `games`.`deleted_at` IS NULL GROUP BY `genre` ORDER BY hours DESC, genre ASC$").
+		WillReturnRows(sqlmock.NewRows([]string{"genre", "games", "hours", "scored_games", "score_sum"}).
+			AddRow("RPG", 1, 40.0, 1, 9).AddRow("Action", 3, 12.5, 2, 13))
+	// Las horas por plataforma salen de las copias y de los juegos sin copias
+	mock.ExpectQuery("^SELECT platform, COUNT\\(DISTINCT game_id\\) AS games, .* FROM \\(SELECT o.game_id, o.platform, .* FROM game_ownerships AS o " +
+		"WHERE o.game_id IN \\(SELECT `id` FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL\\)\\) AS p GROUP BY `platform`$").
+		WillReturnRows(sqlmock.NewRows([]string{"platform", "games", "hours"}).
+			AddRow("PC", 2, 40.0).AddRow("Switch", 1, 3.0))
+	mock.ExpectQuery("^SELECT platform, COUNT\\(\\*\\) AS games, .* FROM `games` WHERE user_id = \\? AND NOT EXISTS .* GROUP BY `platform`$").
+		WillReturnRows(sqlmock.NewRows([]string{"platform", "games", "hours"}).
+			AddRow("pc", 1, 7.5).AddRow("PS5", 2, 5.0))
+	// Los percentiles solo leen las filas vecinas: con 3 juegos, P75 y P90
+	// caen entre las posiciones 1 y 2, que ya trajo la mediana.
+	mock.ExpectQuery("^SELECT COUNT\\(\\*\\) AS games, COALESCE\\(MAX\\(hours_played\\), 0\\) AS max_hours FROM `games` WHERE user_id = \\? AND hours_played > 0 AND `games`.`deleted_at` IS NULL$").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "max_hours"}).AddRow(3, 40.0))
+	mock.ExpectQuery("^SELECT `hours_played` FROM `games` WHERE user_id = \\? AND hours_played > 0 AND `games`.`deleted_at` IS NULL ORDER BY hours_played ASC LIMIT \\?$").
+		WithArgs(uint(1), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"hours_played"}).AddRow(2.5).AddRow(10.0))
+	mock.ExpectQuery("^SELECT `hours_played` FROM .* ORDER BY hours_played ASC LIMIT \\? OFFSET \\?$").
+		WithArgs(uint(1), 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hours_played"}).AddRow(10.0).AddRow(40.0))
+	mock.ExpectQuery("^SELECT COUNT\\(\\*\\) AS games, COALESCE\\(AVG\\(TIMESTAMPDIFF\\(SECOND, created_at, \\?\\) / 86400\\), 0\\) AS average_days, .* FROM `games` WHERE user_id = \\? AND status = \\? AND `games`.`deleted_at` IS NULL$").
+		WithArgs(at, at, uint(1), "Backlog").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "average_days", "oldest_days"}).AddRow(2, 20.0, 30.0))
+	mock.ExpectQuery("^SELECT `title` FROM `games` WHERE .* ORDER BY created_at ASC, id ASC LIMIT \\?$").
+		WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("Viejo"))
+	mock.ExpectQuery("^SELECT TIMESTAMPDIFF\\(SECOND, created_at, \\?\\) / 86400 AS age FROM `games` WHERE .* ORDER BY created_at DESC, id DESC LIMIT \\?$").
+		WithArgs(at, uint(1), "Backlog", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(10.0).AddRow(30.0))
+	mock.ExpectQuery("^SELECT tags.name AS tag, .* FROM \\(SELECT id, hours_played, status FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL\\) AS g "+
+		"JOIN game_tags ON game_tags.game_id = g.id JOIN tags ON tags.id = game_tags.tag_id GROUP BY tags.id, tags.name ORDER BY hours DESC, tag ASC$").
+		WithArgs("Completed", uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"tag", "games", "hours", "completed"}).
+			AddRow("Co-op", 2, 42.504, 1).AddRow("Indie", 3, 12.5, 0))
+
+	// Act
+	stats, err := NewStatsService(conn).GetStats(1, models.GameFilter{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 5, stats.TotalGames)
+	assert.Equal(t, 52.5, stats.TotalHours)
+	assert.Equal(t, 10.5, stats.AverageHours)
+	assert.Equal(t, 3, stats.PendingGames)
+	assert.Equal(t, 0.25, stats.CompletionRate) // 1 completado de 4 que no son wishlist
+	assert.Equal(t, 2, stats.ByStatus["Playing"])
+	assert.Equal(t, "RPG", stats.MostPlayedGenre)
+
+	require.Len(t, stats.HoursByGenre, 2)
+	assert.Equal(t, models.GenreHours{Rank: 1, Genre: "RPG", Games: 1, Hours: 40}, stats.HoursByGenre[0])
+	assert.Equal(t, 2, stats.HoursByGenre[1].Rank)
+	assert.Equal(t, models.GenreScore{Genre: "Action", ScoredGames: 2, AverageScore: 6.5}, stats.AverageScoreByGenre[1])
+	assert.Equal(t, []models.PlatformHours{
+		{Platform: "PC", Games: 3, Hours: 47.5},
+		{Platform: "PS5", Games: 2, Hours: 5},
+		{Platform: "Switch", Games: 1, Hours: 3},
+	}, stats.HoursByPlatform)
+
+	assert.Equal(t, models.PlaytimeStats{Games: 3, P25: 6.25, Median: 10, P75: 25, P90: 34, Max: 40}, stats.Playtime)
+	assert.Equal(t, models.BacklogAge{Games: 2, AverageDays: 20, MedianDays: 20, OldestDays: 30, OldestTitle: "Viejo"}, stats.Backlog)
+	assert.Equal(t, []models.TagHours{
+		{Tag: "Co-op", Games: 2, Hours: 42.5, Completed: 1},
+		{Tag: "Indie", Games: 3, Hours: 12.5},
+	}, stats.HoursByTag)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStats_Filtered(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	// Todas las consultas llevan el filtro
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS total_games, .* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"total_games"}).AddRow(0))
+	mock.ExpectQuery("SELECT status, .* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "games"}))
+	mock.ExpectQuery("SELECT genre, .* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"genre"}))
+	mock.ExpectQuery("SELECT platform, .* FROM game_ownerships AS o .* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"platform"}))
+	mock.ExpectQuery("SELECT platform, .* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"platform"}))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS games, COALESCE\\(MAX.* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "max_hours"}))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) AS games, COALESCE\\(AVG.* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"games", "average_days", "oldest_days"}))
+	mock.ExpectQuery("SELECT tags.name AS tag, .* WHERE user_id = \\? AND \\(platform = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}))
+
+	// Act
+	stats, err := NewStatsService(conn).GetStats(1, models.GameFilter{Platform: "Switch"})
+
+	// Assert: biblioteca vacía, sin divisiones por cero
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.TotalGames)
+	assert.Equal(t, 0.0, stats.CompletionRate)
+	assert.Empty(t, stats.MostPlayedGenre)
+	assert.NotNil(t, stats.HoursByGenre)
+	assert.NotNil(t, stats.HoursByTag)
+	assert.Equal(t, models.PlaytimeStats{}, stats.Playtime)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStats_InvalidStatus(t *testing.T) {
+	conn, _, _ := newMockDB(t)
+
+	_, err := NewStatsService(conn).GetStats(1, models.GameFilter{Status: "done"})
+
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestPercentileRank(t *testing.T) {
+	lower, frac := percentileRank(4, 50)
+	assert.Equal(t, 1, lower)
+	assert.Equal(t, 0.5, frac)
+	lower, frac = percentileRank(4, 100)
+	assert.Equal(t, 3, lower)
+	assert.Equal(t, 0.0, frac)
+	lower, frac = percentileRank(1, 90)
+	assert.Equal(t, 0, lower)
+	assert.Equal(t, 0.0, frac)
+}
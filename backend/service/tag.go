@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("a tag with that name already exists")
+)
+
+// tagGamesCount cuenta los juegos de cada etiqueta sin los de la papelera.
+const tagGamesCount = "(SELECT COUNT(*) FROM game_tags JOIN games ON games.id = game_tags.game_id " +
+	"WHERE game_tags.tag_id = tags.id AND games.deleted_at IS NULL) AS games"
+
+// TagService administra las etiquetas del usuario y las de cada juego.
+type TagService struct {
+	conn  *gorm.DB
+	games *GameService
+}
+
+func NewTagService(conn *gorm.DB) *TagService {
+	return &TagService{conn: conn, games: NewGameService(NewGameRepository(conn))}
+}
+
+// ListTags devuelve las etiquetas del usuario por nombre, con la cantidad
+// de juegos de cada una.
+func (s *TagService) ListTags(userID uint) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	err := s.conn.Model(&models.Tag{}).Select("tags.*, "+tagGamesCount).
+		Where("user_id = ?", userID).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (s *TagService) CreateTag(tag *models.Tag) error {
+	tag.ID = 0
+	tag.Name = strings.TrimSpace(tag.Name)
+	if err := checkTagName(s.conn, tag.UserID, 0, tag.Name); err != nil {
+		return err
+	}
+	return s.conn.Create(tag).Error
+}
+
+// RenameTag cambia el nombre de la etiqueta; los juegos la conservan.
+func (s *TagService) RenameTag(userID uint, id string, input models.TagInput) (models.Tag, error) {
+	var tag models.Tag
+	if err := findTag(s.conn, userID, id, &tag); err != nil {
+		return tag, err
+	}
+	tag.Name = strings.TrimSpace(input.Name)
+	if err := checkTagName(s.conn, userID, tag.ID, tag.Name); err != nil {
+		return tag, err
+	}
+	err := s.conn.Model(&tag).Select("name").Updates(&tag).Error
+	return tag, err
+}
+
+// DeleteTag borra la etiqueta y la quita de todos los juegos.
+func (s *TagService) DeleteTag(userID uint, id string) error {
+	var tag models.Tag
+	return s.conn.Transaction(func(tx *gorm.DB) error {
+		if err := findTag(tx, userID, id, &tag); err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", tag.ID).Delete(&models.GameTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+}
+
+// GetGameTags devuelve las etiquetas del juego por nombre.
+func (s *TagService) GetGameTags(userID uint, gameID string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return tags, err
+	}
+	err = gameTags(s.conn, game.ID, &tags)
+	return tags, err
+}
+
+// SetGameTags reemplaza las etiquetas del juego por las de names (sin
+// distinguir mayúsculas) y crea las que el usuario todavía no tiene.
+func (s *TagService) SetGameTags(userID uint, gameID string, names []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	game, err := s.games.Get(userID, gameID)
+	if err != nil {
+		return tags, err
+	}
+	names = uniqueTagNames(names)
+	err = s.conn.Transaction(func(tx *gorm.DB) error {
+		existing := []models.Tag{}
+		if len(names) > 0 {
+			if err := tx.Where("user_id = ? AND name IN ?", userID, names).Find(&existing).Error; err != nil {
+				return err
+			}
+		}
+		found := make(map[string]bool, len(existing))
+		for _, tag := range existing {
+			found[strings.ToLower(tag.Name)] = true
+		}
+		for _, name := range names {
+			if found[strings.ToLower(name)] {
+				continue
+			}
+			tag := models.Tag{UserID: userID, Name: name}
+			if err := tx.Create(&tag).Error; err != nil {
+				return err
+			}
+			existing = append(existing, tag)
+		}
+
+		tags = existing
+		if err := tx.Where("game_id = ?", game.ID).Delete(&models.GameTag{}).Error; err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			return nil
+		}
+		links := make([]models.GameTag, len(existing))
+		for i, tag := range existing {
+			links[i] = models.GameTag{GameID: game.ID, TagID: tag.ID}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+	if err != nil {
+		return []models.Tag{}, err
+	}
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name) })
+	return tags, nil
+}
+
+func findTag(tx *gorm.DB, userID uint, id string, tag *models.Tag) error {
+	err := tx.Where("user_id = ?", userID).First(tag, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTagNotFound
+	}
+	return err
+}
+
+// checkTagName rechaza nombres repetidos del mismo usuario (exceptID es la
+// etiqueta que se está renombrando).
+func checkTagName(tx *gorm.DB, userID, exceptID uint, name string) error {
+	var count int64
+	err := tx.Model(&models.Tag{}).Where("user_id = ? AND name = ? AND id <> ?", userID, name, exceptID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTagExists
+	}
+	return nil
+}
+
+func gameTags(tx *gorm.DB, gameID uint, tags *[]models.Tag) error {
+	return tx.Select("tags.*").Joins("JOIN game_tags ON game_tags.tag_id = tags.id").
+		Where("game_tags.game_id = ?", gameID).Order("tags.name ASC").Find(tags).Error
+}
+
+// uniqueTagNames recorta los nombres y descarta vacíos y repetidos,
+// conservando la primera forma en que aparece cada uno.
+func uniqueTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetGameTags_CreatesMissingAndReplaces(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `tags` WHERE user_id = \\? AND name IN \\(\\?,\\?\\)").
+		WithArgs(uint(1), "RPG", "Co-op").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "rpg"))
+	mock.ExpectExec("INSERT INTO `tags`").
+		WithArgs(uint(1), "Co-op", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec("DELETE FROM `game_tags` WHERE game_id = \\?").
+		WithArgs(uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO `game_tags` \\(`game_id`,`tag_id`,`created_at`\\) VALUES \\(\\?,\\?,\\?\\),\\(\\?,\\?,\\?\\)").
+		WithArgs(uint(5), uint(3), sqlmock.AnyArg(), uint(5), uint(9), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	// Act: los repetidos y vacíos se ignoran; "RPG" reusa la etiqueta "rpg"
+	tags, err := NewTagService(conn).SetGameTags(1, "5", []string{" RPG ", "Co-op", "co-op", ""})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "Co-op", tags[0].Name)
+	assert.Equal(t, uint(9), tags[0].ID)
+	assert.Equal(t, "rpg", tags[1].Name)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetGameTags_EmptyClears(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `game_tags` WHERE game_id = \\?").
+		WithArgs(uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tags, err := NewTagService(conn).SetGameTags(1, "5", nil)
+
+	require.NoError(t, err)
+	assert.NotNil(t, tags)
+	assert.Empty(t, tags)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTag_Duplicate(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `tags` WHERE user_id = \\? AND name = \\? AND id <> \\?").
+		WithArgs(uint(1), "Co-op", uint(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	err := NewTagService(conn).CreateTag(&models.Tag{UserID: 1, Name: "  Co-op "})
+
+	assert.ErrorIs(t, err, ErrTagExists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTag_RemovesLinks(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT \\* FROM `tags` WHERE user_id = \\? AND `tags`.`id` = \\?").
+		WithArgs(uint(1), "3", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(3, 1, "RPG"))
+	mock.ExpectExec("DELETE FROM `game_tags` WHERE tag_id = \\?").
+		WithArgs(uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("DELETE FROM `tags` WHERE `tags`.`id` = \\?").
+		WithArgs(uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, NewTagService(conn).DeleteTag(1, "3"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListGames_TagFilter(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	tagFilter := "\\(EXISTS \\(SELECT 1 FROM game_tags JOIN tags ON tags.id = game_tags.tag_id WHERE game_tags.game_id = games.id AND tags.name = \\?\\)\\)"
+
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM `games` WHERE user_id = \\? AND "+tagFilter+" AND "+tagFilter).
+		WithArgs(uint(1), "RPG", "Co-op").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND " + tagFilter + " AND " + tagFilter).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(5, "Divinity"))
+
+	// Act
+	page, err := NewGameService(NewGameRepository(conn)).List(1, models.GameListQuery{Filter: models.GameFilter{Tags: []string{"RPG", "Co-op"}}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	require.Len(t, page.Items, 1)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddGameToCollection_UnknownCollection(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectQuery("SELECT \\* FROM `collections` WHERE user_id = \\? AND `collections`.`id` = \\?").
+		WithArgs(uint(1), "8", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := NewCollectionService(conn).AddGameToCollection(1, "5", "8")
+
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddGameToCollection_Idempotent(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	expectOwnedGame(mock)
+	mock.ExpectQuery("SELECT \\* FROM `collections`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name"}).AddRow(8, 1, "Verano 2026"))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `collection_games` .* ON DUPLICATE KEY UPDATE").
+		WithArgs(uint(8), uint(5), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	require.NoError(t, NewCollectionService(conn).AddGameToCollection(1, "5", "8"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
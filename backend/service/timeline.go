@@ -0,0 +1,349 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TimelineMonth = "month"
+	TimelineWeek  = "week"
+
+	// Cinco años de semanas; evita respuestas gigantes por un rango mal armado.
+	MaxTimelineBuckets = 260
+	// Períodos que se muestran si no se indica rango.
+	defaultTimelineBuckets = 12
+	yearReviewTopGames     = 5
+)
+
+// yearGame son las columnas que necesita el resumen anual de cada juego
+// jugado en el año.
+type yearGame struct {
+	ID          uint
+	Title       string
+	Genre       string
+	HoursPlayed float64
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
+
+// bucketTotal es una fila de timeline.totals.
+type bucketTotal struct {
+	Bucket int
+	Games  int
+	Hours  float64
+}
+
+type gameYearMinutes struct {
+	GameID      uint
+	YearMinutes int
+}
+
+// timeline son los buckets de [start, end). Los límites se calculan en Go,
+// en la hora local, y el SQL solo compara fechas: así no depende de las
+// funciones de fecha de cada motor.
+type timeline struct {
+	interval string
+	buckets  []models.TimelineBucket
+	end      time.Time
+}
+
+// GetTimeline cuenta por mes o semana los juegos agregados, empezados y
+// terminados entre from (inclusive) y to (exclusivo), con los filtros del
+// listado. Sin rango devuelve los últimos 12 períodos.
+func (s *StatsService) GetTimeline(userID uint, filter models.GameFilter, interval string, from, to *time.Time) ([]models.TimelineBucket, error) {
+	if err := validateGameFilter(filter); err != nil {
+		return nil, err
+	}
+	if interval == "" {
+		interval = TimelineMonth
+	}
+	if interval != TimelineMonth && interval != TimelineWeek {
+		return nil, fmt.Errorf("%w: interval must be %q or %q", ErrInvalidQuery, TimelineMonth, TimelineWeek)
+	}
+
+	end := nextBucket(interval, bucketStart(interval, clock.Now()))
+	if to != nil {
+		// El último período se cuenta completo.
+		end = bucketStart(interval, *to)
+		if !end.Equal(*to) {
+			end = nextBucket(interval, end)
+		}
+	}
+	var start time.Time
+	if from != nil {
+		start = bucketStart(interval, *from)
+	} else {
+		start = end
+		for i := 0; i < defaultTimelineBuckets; i++ {
+			start = previousBucket(interval, start)
+		}
+		start = bucketStart(interval, start)
+	}
+
+	tl, err := newTimeline(interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadTimeline(tl, func() *gorm.DB { return s.userGames(userID, filter) }); err != nil {
+		return nil, err
+	}
+	return tl.buckets, nil
+}
+
+// GetYearReview arma el resumen anual: juegos con más horas en el año, el
+// juego terminado más largo, géneros jugados por primera vez y la racha de
+// meses con juegos terminados.
+func (s *StatsService) GetYearReview(userID uint, year int) (models.YearReview, error) {
+	review := models.YearReview{
+		Year:             year,
+		TopGames:         []models.YearGame{},
+		GenresDiscovered: []string{},
+	}
+	if year < 1970 || year > 9999 {
+		return review, fmt.Errorf("%w: invalid year %d", ErrInvalidQuery, year)
+	}
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+	yearEnd := yearStart.AddDate(1, 0, 0)
+
+	tl, err := newTimeline(TimelineMonth, yearStart, yearEnd)
+	if err != nil {
+		return review, err
+	}
+	games := func() *gorm.DB { return s.userGames(userID, models.GameFilter{}) }
+	if err := s.loadTimeline(tl, games); err != nil {
+		return review, err
+	}
+	review.Months = tl.buckets
+	for _, m := range review.Months {
+		review.GamesAdded += m.Added
+		review.GamesStarted += m.Started
+		review.GamesFinished += m.Finished
+	}
+
+	// Los juegos del año: empezados o terminados en el año, o con sesiones
+	// que empezaron en el año.
+	yearGames := func() *gorm.DB {
+		sessions := s.conn.Model(&models.PlaySession{}).Select("game_id").
+			Where("ended_at IS NOT NULL AND started_at >= ? AND started_at < ?", yearStart, yearEnd)
+		return games().Where("(started_at >= ? AND started_at < ?) OR (finished_at >= ? AND finished_at < ?) OR id IN (?)",
+			yearStart, yearEnd, yearStart, yearEnd, sessions)
+	}
+	var played []yearGame
+	err = yearGames().Select("id, title, genre, hours_played, started_at, finished_at").Scan(&played).Error
+	if err != nil {
+		return review, err
+	}
+
+	// Horas del año por juego: las de sus sesiones en el año si tiene
+	// sesiones; si no, HoursPlayed para los juegos empezados o terminados en el año.
+	var perGame []gameYearMinutes
+	err = s.conn.Model(&models.PlaySession{}).
+		Select("game_id, COALESCE(SUM(CASE WHEN started_at >= ? AND started_at < ? THEN duration_minutes ELSE 0 END), 0) AS year_minutes", yearStart, yearEnd).
+		Where("ended_at IS NOT NULL AND game_id IN (?)", yearGames().Select("id")).
+		Group("game_id").
+		Scan(&perGame).Error
+	if err != nil {
+		return review, err
+	}
+	sessionMinutes := make(map[uint]int, len(perGame))
+	for _, g := range perGame {
+		sessionMinutes[g.GameID] = g.YearMinutes
+	}
+
+	inYear := func(t *time.Time) bool {
+		return t != nil && !t.Before(yearStart) && t.Before(yearEnd)
+	}
+	var top []models.YearGame
+	for _, g := range played {
+		finished := inYear(g.FinishedAt)
+		hours := 0.0
+		if minutes, ok := sessionMinutes[g.ID]; ok {
+			hours = float64(minutes) / 60
+		} else if finished || inYear(g.StartedAt) {
+			hours = g.HoursPlayed
+		}
+		if hours <= 0 {
+			continue
+		}
+		entry := models.YearGame{ID: g.ID, Title: g.Title, Genre: g.Genre, Hours: models.RoundHours(hours)}
+		top = append(top, entry)
+		review.HoursPlayed += hours
+
+		if finished && (review.LongestGame == nil || hours > review.LongestGame.Hours) {
+			longest := entry
+			if g.StartedAt != nil {
+				longest.Days = models.RoundHours(g.FinishedAt.Sub(*g.StartedAt).Hours() / 24)
+			}
+			review.LongestGame = &longest
+		}
+	}
+	review.HoursPlayed = models.RoundHours(review.HoursPlayed)
+
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Hours > top[j].Hours })
+	if len(top) > yearReviewTopGames {
+		top = top[:yearReviewTopGames]
+	}
+	review.TopGames = append(review.TopGames, top...)
+
+	// Un género se descubre el año en que se jugó (o, sin fecha de inicio,
+	// se agregó) su primer juego.
+	const firstPlayed = "MIN(COALESCE(started_at, created_at))"
+	err = games().Where("genre <> ''").Group("genre").
+		Having(firstPlayed+" >= ? AND "+firstPlayed+" < ?", yearStart, yearEnd).
+		Order(firstPlayed+" ASC, genre ASC").
+		Pluck("genre", &review.GenresDiscovered).Error
+	if err != nil {
+		return review, err
+	}
+
+	review.CompletionStreak = longestCompletionStreak(review.Months)
+	return review, nil
+}
+
+func newTimeline(interval string, start, end time.Time) (*timeline, error) {
+	tl := &timeline{interval: interval, end: end}
+	for b := bucketStart(interval, start); b.Before(end); b = nextBucket(interval, b) {
+		if len(tl.buckets) == MaxTimelineBuckets {
+			return nil, fmt.Errorf("%w: range too large (max %d %ss)", ErrInvalidQuery, MaxTimelineBuckets, interval)
+		}
+		tl.buckets = append(tl.buckets, models.TimelineBucket{Period: periodLabel(interval, b), Start: b})
+	}
+	return tl, nil
+}
+
+// bucketExpr numera con un CASE el bucket en el que cae column; solo vale
+// para las filas dentro del rango.
+func (tl *timeline) bucketExpr(column string) (string, []interface{}) {
+	var b strings.Builder
+	args := make([]interface{}, 0, len(tl.buckets))
+	b.WriteString("CASE")
+	for i := range tl.buckets {
+		fmt.Fprintf(&b, " WHEN %s < ? THEN %d", column, i)
+		if i+1 < len(tl.buckets) {
+			args = append(args, tl.buckets[i+1].Start)
+		} else {
+			args = append(args, tl.end)
+		}
+	}
+	b.WriteString(" END")
+	return b.String(), args
+}
+
+// totals cuenta por bucket las filas de query cuya column cae en el rango y
+// suma la expresión sum.
+func (tl *timeline) totals(query *gorm.DB, column, sum string) ([]bucketTotal, error) {
+	var rows []bucketTotal
+	if len(tl.buckets) == 0 {
+		return rows, nil
+	}
+	expr, args := tl.bucketExpr(column)
+	err := query.Select(expr+" AS bucket, COUNT(*) AS games, COALESCE(SUM("+sum+"), 0) AS hours", args...).
+		Where(column+" >= ? AND "+column+" < ?", tl.buckets[0].Start, tl.end).
+		Group("bucket").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// loadTimeline completa los buckets con los juegos de games (agregados,
+// empezados y terminados con sus horas) y las horas de sus sesiones
+// terminadas, agrupando en SQL.
+func (s *StatsService) loadTimeline(tl *timeline, games func() *gorm.DB) error {
+	added, err := tl.totals(games(), "created_at", "0")
+	if err != nil {
+		return err
+	}
+	started, err := tl.totals(games(), "started_at", "0")
+	if err != nil {
+		return err
+	}
+	finished, err := tl.totals(games(), "finished_at", "hours_played")
+	if err != nil {
+		return err
+	}
+	sessions, err := tl.totals(s.conn.Model(&models.PlaySession{}).
+		Where("ended_at IS NOT NULL AND game_id IN (?)", games().Select("id")), "started_at", "duration_minutes")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range added {
+		tl.buckets[row.Bucket].Added = row.Games
+	}
+	for _, row := range started {
+		tl.buckets[row.Bucket].Started = row.Games
+	}
+	for _, row := range finished {
+		tl.buckets[row.Bucket].Finished = row.Games
+		tl.buckets[row.Bucket].Hours = models.RoundHours(row.Hours)
+	}
+	for _, row := range sessions {
+		tl.buckets[row.Bucket].SessionHours = models.RoundHours(row.Hours / 60)
+	}
+	return nil
+}
+
+// userGames es la consulta de juegos del usuario (sin los de la papelera)
+// que cumplen el filtro.
+func (s *StatsService) userGames(userID uint, filter models.GameFilter) *gorm.DB {
+	return applyGameFilters(s.conn.Model(&models.Game{}).Where("user_id = ?", userID), filter)
+}
+
+// bucketStart lleva t al inicio de su mes o de su semana ISO (lunes).
+func bucketStart(interval string, t time.Time) time.Time {
+	t = t.In(time.Local)
+	if interval == TimelineWeek {
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		offset := (int(day.Weekday()) + 6) % 7 // lunes = 0
+		return day.AddDate(0, 0, -offset)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+func nextBucket(interval string, start time.Time) time.Time {
+	if interval == TimelineWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+func previousBucket(interval string, start time.Time) time.Time {
+	if interval == TimelineWeek {
+		return start.AddDate(0, 0, -7)
+	}
+	return start.AddDate(0, -1, 0)
+}
+
+func periodLabel(interval string, start time.Time) string {
+	if interval == TimelineWeek {
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return start.Format("2006-01")
+}
+
+func longestCompletionStreak(months []models.TimelineBucket) models.CompletionStreak {
+	var best, current models.CompletionStreak
+	for _, m := range months {
+		if m.Finished == 0 {
+			current = models.CompletionStreak{}
+			continue
+		}
+		if current.Months == 0 {
+			current.From = m.Period
+		}
+		current.Months++
+		current.To = m.Period
+		if current.Months > best.Months {
+			best = current
+		}
+	}
+	return best
+}
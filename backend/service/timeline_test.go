@@ -0,0 +1,163 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func localDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.Local)
+}
+
+var bucketColumns = []string{"bucket", "games", "hours"}
+
+// bucketQuery es la consulta agrupada por bucket de column en table.
+func bucketQuery(column, table string) string {
+	return "^SELECT CASE WHEN " + column + " < \\? THEN 0 .* END AS bucket, COUNT\\(\\*\\) AS games, .* FROM `" + table + "` WHERE .*" +
+		column + " >= \\? AND " + column + " < \\?.* GROUP BY `bucket`$"
+}
+
+func TestGetTimeline_DefaultMonths(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	fixNow(t, localDate(2024, 6, 20))
+
+	// Todo se agrupa en SQL: solo vuelven los buckets con datos
+	mock.ExpectQuery("^SELECT CASE WHEN created_at < \\? THEN 0 WHEN created_at < \\? THEN 1 .* WHEN created_at < \\? THEN 11 END AS bucket, " +
+		"COUNT\\(\\*\\) AS games, COALESCE\\(SUM\\(0\\), 0\\) AS hours FROM `games` " +
+		"WHERE user_id = \\? AND \\(created_at >= \\? AND created_at < \\?\\) AND `games`.`deleted_at` IS NULL GROUP BY `bucket`$").
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(10, 1, 0))
+	mock.ExpectQuery(bucketQuery("started_at", "games")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(10, 1, 0))
+	mock.ExpectQuery(bucketQuery("finished_at", "games")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(11, 1, 30.0))
+	mock.ExpectQuery("^SELECT CASE WHEN started_at < \\? .* COALESCE\\(SUM\\(duration_minutes\\), 0\\) AS hours FROM `play_sessions` " +
+		"WHERE \\(ended_at IS NOT NULL AND game_id IN \\(SELECT `id` FROM `games` WHERE user_id = \\? AND `games`.`deleted_at` IS NULL\\)\\) " +
+		"AND \\(started_at >= \\? AND started_at < \\?\\) GROUP BY `bucket`$").
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(10, 2, 120))
+
+	// Act
+	buckets, err := NewStatsService(conn).GetTimeline(1, models.GameFilter{}, "", nil, nil)
+
+	// Assert: 12 meses terminando en el actual
+	require.NoError(t, err)
+	require.Len(t, buckets, 12)
+	assert.Equal(t, "2023-07", buckets[0].Period)
+	may, june := buckets[10], buckets[11]
+	assert.Equal(t, "2024-05", may.Period)
+	assert.Equal(t, 1, may.Added)
+	assert.Equal(t, 1, may.Started)
+	assert.Equal(t, 2.0, may.SessionHours)
+	assert.Equal(t, 1, june.Finished)
+	assert.Equal(t, 30.0, june.Hours)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTimeline_Weeks(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	from := localDate(2024, 1, 3) // miércoles de la semana 1
+	to := localDate(2024, 1, 15)
+	monday := func(day int) time.Time { return time.Date(2024, 1, day, 0, 0, 0, 0, time.Local) }
+
+	// Los límites de cada semana son los lunes, calculados en Go
+	mock.ExpectQuery(bucketQuery("created_at", "games")).
+		WithArgs(monday(8), monday(15), monday(22), uint(1), monday(1), monday(22)).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(1, 1, 0))
+	mock.ExpectQuery(bucketQuery("started_at", "games")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(0, 1, 0))
+	mock.ExpectQuery(bucketQuery("finished_at", "games")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns))
+	mock.ExpectQuery(bucketQuery("started_at", "play_sessions")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns))
+
+	// Act
+	buckets, err := NewStatsService(conn).GetTimeline(1, models.GameFilter{}, TimelineWeek, &from, &to)
+
+	// Assert: del lunes 1/1 a la semana que contiene el 15 inclusive
+	require.NoError(t, err)
+	require.Len(t, buckets, 3)
+	assert.Equal(t, []string{"2024-W01", "2024-W02", "2024-W03"},
+		[]string{buckets[0].Period, buckets[1].Period, buckets[2].Period})
+	assert.Equal(t, time.Monday, buckets[0].Start.Weekday())
+	assert.Equal(t, 1, buckets[0].Started)
+	assert.Equal(t, 1, buckets[1].Added)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTimeline_InvalidParams(t *testing.T) {
+	conn, _, _ := newMockDB(t)
+	from := localDate(1990, 1, 1)
+	to := localDate(2024, 1, 1)
+
+	_, err := NewStatsService(conn).GetTimeline(1, models.GameFilter{}, "day", nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+
+	_, err = NewStatsService(conn).GetTimeline(1, models.GameFilter{}, TimelineWeek, &from, &to)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestGetYearReview(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	mock.ExpectQuery(bucketQuery("created_at", "games")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(0, 1, 0).AddRow(5, 1, 0).AddRow(7, 1, 0))
+	mock.ExpectQuery(bucketQuery("started_at", "games")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(1, 1, 0).AddRow(5, 1, 0).AddRow(8, 1, 0))
+	mock.ExpectQuery(bucketQuery("finished_at", "games")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(3, 1, 120.0).AddRow(4, 1, 50.0).AddRow(5, 1, 8.0))
+	mock.ExpectQuery(bucketQuery("started_at", "play_sessions")).
+		WillReturnRows(sqlmock.NewRows(bucketColumns).AddRow(0, 1, 600))
+	mock.ExpectQuery("^SELECT id, title, genre, hours_played, started_at, finished_at FROM `games` WHERE user_id = \\? AND " +
+		"\\(\\(started_at >= \\? AND started_at < \\?\\) OR \\(finished_at >= \\? AND finished_at < \\?\\) OR id IN \\(SELECT `game_id` FROM `play_sessions` .*\\)\\) " +
+		"AND `games`.`deleted_at` IS NULL$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "genre", "hours_played", "started_at", "finished_at"}).
+			// Terminado en 2023 sin sesiones: cuentan sus HoursPlayed
+			AddRow(1, "Elden Ring", "Soulslike", 120.0, localDate(2023, 2, 1), localDate(2023, 4, 10)).
+			// Con sesiones: solo cuentan las del año
+			AddRow(2, "Hades", "Roguelike", 50.0, localDate(2022, 11, 1), localDate(2023, 5, 2)).
+			AddRow(3, "Celeste", "Platformer", 8.0, localDate(2023, 6, 1), localDate(2023, 6, 20)).
+			AddRow(4, "Persona 5", "RPG", 0.0, localDate(2023, 9, 1), nil))
+	mock.ExpectQuery("^SELECT game_id, COALESCE\\(SUM\\(CASE WHEN started_at >= \\? AND started_at < \\? THEN duration_minutes ELSE 0 END\\), 0\\) AS year_minutes FROM `play_sessions` WHERE ended_at IS NOT NULL AND game_id IN \\(SELECT `id` FROM `games` .*\\) GROUP BY `game_id`$").
+		WillReturnRows(sqlmock.NewRows([]string{"game_id", "year_minutes"}).AddRow(2, 600))
+	// RPG ya se había jugado antes de 2023
+	mock.ExpectQuery("^SELECT `genre` FROM `games` WHERE user_id = \\? AND genre <> '' AND `games`.`deleted_at` IS NULL GROUP BY `genre` " +
+		"HAVING MIN\\(COALESCE\\(started_at, created_at\\)\\) >= \\? AND MIN\\(COALESCE\\(started_at, created_at\\)\\) < \\? " +
+		"ORDER BY MIN\\(COALESCE\\(started_at, created_at\\)\\) ASC, genre ASC$").
+		WillReturnRows(sqlmock.NewRows([]string{"genre"}).AddRow("Soulslike").AddRow("Platformer"))
+
+	// Act
+	review, err := NewStatsService(conn).GetYearReview(1, 2023)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, review.GamesAdded)
+	assert.Equal(t, 3, review.GamesStarted)
+	assert.Equal(t, 3, review.GamesFinished)
+	assert.Equal(t, 138.0, review.HoursPlayed) // 120 + 10 + 8
+
+	require.Len(t, review.TopGames, 3)
+	assert.Equal(t, "Elden Ring", review.TopGames[0].Title)
+	assert.Equal(t, 10.0, review.TopGames[1].Hours)
+	require.NotNil(t, review.LongestGame)
+	assert.Equal(t, "Elden Ring", review.LongestGame.Title)
+	assert.Equal(t, 68.0, review.LongestGame.Days)
+
+	assert.Equal(t, []string{"Soulslike", "Platformer"}, review.GenresDiscovered)
+	assert.Equal(t, models.CompletionStreak{Months: 3, From: "2023-04", To: "2023-06"}, review.CompletionStreak)
+	require.Len(t, review.Months, 12)
+	assert.Equal(t, 10.0, review.Months[0].SessionHours)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetYearReview_InvalidYear(t *testing.T) {
+	_, err := NewStatsService(nil).GetYearReview(1, 12)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
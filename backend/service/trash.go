@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/models"
+	"gametracker/storage"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultTrashRetention     = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval = time.Hour
+)
+
+// TrashConfig controla cuánto tiempo quedan los juegos en la papelera y cada
+// cuánto se purgan.
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// LoadTrashConfig lee TRASH_RETENTION y TRASH_PURGE_INTERVAL (duraciones de
+// Go, ej. "720h").
+func LoadTrashConfig() (TrashConfig, error) {
+	var cfg TrashConfig
+	var err error
+	if cfg.Retention, err = durationFromEnv("TRASH_RETENTION", DefaultTrashRetention); err != nil {
+		return cfg, err
+	}
+	if cfg.PurgeInterval, err = durationFromEnv("TRASH_PURGE_INTERVAL", DefaultTrashPurgeInterval); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// TrashService lista, restaura y purga los juegos borrados. Al purgar borra
+// también los archivos de sus portadas de covers (puede ser nil).
+type TrashService struct {
+	conn   *gorm.DB
+	games  *GameService
+	covers storage.Storage
+}
+
+func NewTrashService(conn *gorm.DB, covers storage.Storage) *TrashService {
+	return &TrashService{conn: conn, games: NewGameService(NewGameRepository(conn)), covers: covers}
+}
+
+// ListTrash devuelve los juegos borrados del usuario, los más recientes primero.
+func (s *TrashService) ListTrash(userID uint) ([]models.Game, error) {
+	games := []models.Game{}
+	err := s.conn.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&games).Error
+	return games, err
+}
+
+// RestoreGame saca un juego de la papelera. Sube la versión para que los
+// ETags obtenidos antes del borrado ya no sirvan.
+func (s *TrashService) RestoreGame(userID uint, id string) (models.Game, error) {
+	res := s.conn.Unscoped().Model(&models.Game{}).
+		Where("user_id = ? AND id = ? AND deleted_at IS NOT NULL", userID, id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if res.Error != nil {
+		return models.Game{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return models.Game{}, ErrNotFound
+	}
+	return s.games.Get(userID, id)
+}
+
+// PurgeTrash elimina definitivamente los juegos borrados antes de before.
+// Las filas de game_covers se van en cascada; los archivos se borran después
+// del commit, y si alguno falla solo se registra en el log.
+func (s *TrashService) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	var covers []models.GameCover
+	err := s.conn.Transaction(func(tx *gorm.DB) error {
+		if s.covers != nil {
+			expired := tx.Unscoped().Model(&models.Game{}).Select("id").
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+			if err := tx.Where("game_id IN (?)", expired).Find(&covers).Error; err != nil {
+				return err
+			}
+		}
+		res := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Delete(&models.Game{})
+		purged = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, cover := range covers {
+		if err := s.covers.Delete(ctx, coverPrefix(cover)); err != nil {
+			log.Printf("delete cover %s: %v", coverPrefix(cover), err)
+		}
+	}
+	return purged, nil
+}
+
+// StartTrashPurger purga la papelera cada cfg.PurgeInterval hasta que ctx se
+// cancele. La primera purga se hace al arrancar.
+func (s *TrashService) StartTrashPurger(ctx context.Context, cfg TrashConfig) {
+	go func() {
+		ticker := time.NewTicker(cfg.PurgeInterval)
+		defer ticker.Stop()
+		for {
+			purged, err := s.PurgeTrash(ctx, clock.Now().Add(-cfg.Retention))
+			if err != nil {
+				log.Printf("Error purgando la papelera: %v", err)
+			} else if purged > 0 {
+				log.Printf("Papelera: %d juegos eliminados definitivamente", purged)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
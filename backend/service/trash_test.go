@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gametracker/storage"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTrash(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	deletedAt := time.Now()
+
+	mock.ExpectQuery("^SELECT \\* FROM `games` WHERE user_id = \\? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC$").
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "deleted_at"}).
+			AddRow(3, 1, "Borrado", deletedAt))
+
+	// Act
+	games, err := NewTrashService(conn, nil).ListTrash(1)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, games, 1)
+	assert.True(t, games[0].DeletedAt.Valid)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreGame_Success(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^UPDATE `games` SET `deleted_at`=\\?,`version`=version \\+ 1,`updated_at`=\\? WHERE user_id = \\? AND id = \\? AND deleted_at IS NOT NULL$").
+		WithArgs(nil, sqlmock.AnyArg(), uint(1), "3").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE user_id = \\? AND `games`.`id` = \\? AND `games`.`deleted_at` IS NULL").
+		WithArgs(uint(1), "3", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "title", "version"}).AddRow(3, 1, "Borrado", 2))
+
+	// Act
+	game, err := NewTrashService(conn, nil).RestoreGame(1, "3")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), game.ID)
+	assert.False(t, game.DeletedAt.Valid)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreGame_NotInTrash(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+
+	// No existe, es de otro usuario o no está borrado
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `games` SET").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	_, err := NewTrashService(conn, nil).RestoreGame(1, "3")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeTrash(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM `games` WHERE deleted_at IS NOT NULL AND deleted_at < \\?$").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	// Act
+	purged, err := NewTrashService(conn, nil).PurgeTrash(context.Background(), before)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeTrash_DeletesCoverFiles(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	for _, key := range []string{"covers/3/abc/original.png", "covers/3/abc/small.jpg", "covers/4/def/original.png"} {
+		require.NoError(t, local.Put(ctx, key, strings.NewReader("img"), "image/png"))
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT \\* FROM `game_covers` WHERE game_id IN \\(SELECT `id` FROM `games` WHERE deleted_at IS NOT NULL AND deleted_at < \\?\\)$").
+		WithArgs(before).
+		WillReturnRows(sqlmock.NewRows([]string{"game_id", "hash"}).AddRow(3, "abc"))
+	mock.ExpectExec("^DELETE FROM `games` WHERE deleted_at IS NOT NULL AND deleted_at < \\?$").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	purged, err := NewTrashService(conn, local).PurgeTrash(ctx, before)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	_, _, err = local.Get(ctx, "covers/3/abc/original.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, _, err = local.Get(ctx, "covers/3/abc/small.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	body, _, err := local.Get(ctx, "covers/4/def/original.png")
+	require.NoError(t, err)
+	body.Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoadTrashConfig(t *testing.T) {
+	t.Setenv("TRASH_RETENTION", "")
+	t.Setenv("TRASH_PURGE_INTERVAL", "")
+	cfg, err := LoadTrashConfig()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTrashRetention, cfg.Retention)
+	assert.Equal(t, DefaultTrashPurgeInterval, cfg.PurgeInterval)
+
+	t.Setenv("TRASH_RETENTION", "168h")
+	t.Setenv("TRASH_PURGE_INTERVAL", "10m")
+	cfg, err = LoadTrashConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, cfg.Retention)
+	assert.Equal(t, 10*time.Minute, cfg.PurgeInterval)
+
+	t.Setenv("TRASH_RETENTION", "forever")
+	_, err = LoadTrashConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	conn *gorm.DB
+}
+
+// NewUserRepository implementa UserRepository sobre conn.
+func NewUserRepository(conn *gorm.DB) UserRepository {
+	return &userRepository{conn: conn}
+}
+
+func (r *userRepository) ExistsByUsernameOrEmail(username, email string) (bool, error) {
+	// Solo verificar existencia, no cargar datos
+	var count int64
+	err := r.conn.Model(&models.User{}).Where("username = ? OR email = ?", username, email).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepository) FindByLogin(login string) (models.User, error) {
+	var user models.User
+	err := r.conn.Where("username = ? OR email = ?", login, login).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (r *userRepository) Create(user *models.User) error {
+	return r.conn.Create(user).Error
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"gametracker/clock"
+	"gametracker/models"
+
+	"gorm.io/gorm"
+)
+
+// MaxUpcomingDays limita la ventana de UpcomingReleases.
+const MaxUpcomingDays = 366
+
+var (
+	ErrWishlistItemNotFound = errors.New("wishlist item not found")
+	ErrPlatformRequired     = errors.New("platform is required to add the game to the library")
+	ErrGameInLibrary        = errors.New("game already in library")
+)
+
+// wishlistOrder muestra primero lo más deseado y, a igual prioridad, lo que
+// sale antes (los deseos sin fecha van al final).
+const wishlistOrder = "priority DESC, release_date IS NULL, release_date ASC, id ASC"
+
+// WishlistService administra la lista de deseos y la promoción de sus
+// entradas a la biblioteca.
+type WishlistService struct {
+	conn *gorm.DB
+}
+
+func NewWishlistService(conn *gorm.DB) *WishlistService {
+	return &WishlistService{conn: conn}
+}
+
+// ListWishlist devuelve todos los deseos del usuario.
+func (s *WishlistService) ListWishlist(userID uint) ([]models.WishlistItem, error) {
+	items := []models.WishlistItem{}
+	err := s.conn.Where("user_id = ?", userID).Order(wishlistOrder).Find(&items).Error
+	return items, err
+}
+
+// UpcomingReleases devuelve los deseos que salen desde hoy hasta dentro de
+// days días inclusive, por fecha de salida.
+func (s *WishlistService) UpcomingReleases(userID uint, days int) ([]models.WishlistItem, error) {
+	items := []models.WishlistItem{}
+	current := clock.Now()
+	from := time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, current.Location())
+	to := from.AddDate(0, 0, days+1)
+	err := s.conn.Where("user_id = ? AND release_date >= ? AND release_date < ?", userID, from, to).
+		Order("release_date ASC, priority DESC, id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+func (s *WishlistService) GetWishlistItem(userID uint, id string) (models.WishlistItem, error) {
+	var item models.WishlistItem
+	err := findWishlistItem(s.conn, userID, id, &item)
+	return item, err
+}
+
+func (s *WishlistService) CreateWishlistItem(item *models.WishlistItem) error {
+	item.ID = 0
+	return s.conn.Create(item).Error
+}
+
+// UpdateWishlistItem reemplaza los campos editables del deseo.
+func (s *WishlistService) UpdateWishlistItem(userID uint, id string, input models.WishlistInput) (models.WishlistItem, error) {
+	var item models.WishlistItem
+	if err := findWishlistItem(s.conn, userID, id, &item); err != nil {
+		return item, err
+	}
+	input.Apply(&item)
+	err := s.conn.Model(&item).
+		Select("title", "platform", "target_price", "release_date", "priority", "note").
+		Updates(&item).Error
+	return item, err
+}
+
+func (s *WishlistService) DeleteWishlistItem(userID uint, id string) error {
+	res := s.conn.Where("user_id = ?", userID).Delete(&models.WishlistItem{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrWishlistItemNotFound
+	}
+	return nil
+}
+
+// PromoteWishlistItem crea un juego en la biblioteca a partir del deseo y
+// borra el deseo, todo en una transacción. Si la biblioteca ya tiene el juego
+// en esa plataforma (como al importar) no crea nada, conserva el deseo y
+// devuelve el juego existente con ErrGameInLibrary.
+func (s *WishlistService) PromoteWishlistItem(userID uint, id string, input models.PromoteInput) (models.Game, error) {
+	var game models.Game
+	err := s.conn.Transaction(func(tx *gorm.DB) error {
+		var item models.WishlistItem
+		if err := findWishlistItem(tx, userID, id, &item); err != nil {
+			return err
+		}
+		platform := input.Platform
+		if platform == "" {
+			platform = item.Platform
+		}
+		if platform == "" {
+			return ErrPlatformRequired
+		}
+		err := findLibraryGame(tx, userID, item.Title, platform, &game)
+		switch {
+		case err == nil:
+			return ErrGameInLibrary
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		game = models.Game{
+			UserID:       userID,
+			Title:        item.Title,
+			Platform:     platform,
+			Status:       input.Status,
+			PersonalNote: item.Note,
+		}
+		if err := createGame(tx, &game); err != nil {
+			return err
+		}
+		return tx.Delete(&item).Error
+	})
+	return game, err
+}
+
+func findWishlistItem(tx *gorm.DB, userID uint, id string, item *models.WishlistItem) error {
+	err := tx.Where("user_id = ?", userID).First(item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWishlistItemNotFound
+	}
+	return err
+}
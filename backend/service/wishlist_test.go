@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gametracker/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wishlistLookupQuery = "SELECT \\* FROM `wishlist_items` WHERE user_id = \\? AND `wishlist_items`.`id` = \\?"
+
+func wishlistRow(platform string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "user_id", "title", "platform", "priority", "note"}).
+		AddRow(7, 1, "Silksong", platform, 5, "day one")
+}
+
+func TestUpcomingReleases_Window(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	fixNow(t, time.Date(2024, 6, 10, 15, 30, 0, 0, time.UTC))
+	from := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 18, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT \\* FROM `wishlist_items` WHERE user_id = \\? AND release_date >= \\? AND release_date < \\? ORDER BY release_date ASC, priority DESC, id ASC").
+		WithArgs(uint(1), from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "release_date"}).
+			AddRow(2, "Silksong", time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC)))
+
+	// Act: hoy más 7 días, el último inclusive
+	items, err := NewWishlistService(conn).UpcomingReleases(1, 7)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Silksong", items[0].Title)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListWishlist_Empty(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectQuery("SELECT \\* FROM `wishlist_items` WHERE user_id = \\? ORDER BY priority DESC").
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	items, err := NewWishlistService(conn).ListWishlist(1)
+
+	require.NoError(t, err)
+	assert.NotNil(t, items)
+	assert.Empty(t, items)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteWishlistItem_CreatesGameAndDeletesItem(t *testing.T) {
+	// Arrange
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(wishlistLookupQuery).
+		WithArgs(uint(1), "7", 1).
+		WillReturnRows(wishlistRow("Switch"))
+	expectLibraryLookup(mock, "Switch", sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("INSERT INTO `games`").WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec("DELETE FROM `wishlist_items` WHERE `wishlist_items`.`id` = \\?").
+		WithArgs(uint(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	game, err := NewWishlistService(conn).PromoteWishlistItem(1, "7", models.PromoteInput{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(12), game.ID)
+	assert.Equal(t, "Silksong", game.Title)
+	assert.Equal(t, "Switch", game.Platform)
+	assert.Equal(t, models.DefaultGameStatus, game.Status)
+	assert.Equal(t, "day one", game.PersonalNote)
+	assert.Equal(t, uint(1), game.Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectLibraryLookup espera la búsqueda del juego del deseo en la
+// biblioteca, por título y plataforma del juego o de sus copias.
+func expectLibraryLookup(mock sqlmock.Sqlmock, platform string, rows *sqlmock.Rows) {
+	mock.ExpectQuery("SELECT \\* FROM `games` WHERE \\(user_id = \\? AND title = \\? AND \\(platform = \\? OR EXISTS").
+		WithArgs(uint(1), "Silksong", platform, platform, 1).
+		WillReturnRows(rows)
+}
+
+func TestPromoteWishlistItem_AlreadyInLibrary(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(wishlistLookupQuery).WillReturnRows(wishlistRow("Switch"))
+	expectLibraryLookup(mock, "PC", sqlmock.NewRows([]string{"id", "user_id", "title", "platform"}).AddRow(4, 1, "Silksong", "Switch"))
+	mock.ExpectRollback()
+
+	game, err := NewWishlistService(conn).PromoteWishlistItem(1, "7", models.PromoteInput{Platform: "PC"})
+
+	assert.ErrorIs(t, err, ErrGameInLibrary)
+	assert.Equal(t, uint(4), game.ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteWishlistItem_PlatformRequired(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(wishlistLookupQuery).WillReturnRows(wishlistRow(""))
+	mock.ExpectRollback()
+
+	_, err := NewWishlistService(conn).PromoteWishlistItem(1, "7", models.PromoteInput{Status: models.StatusPlaying})
+
+	assert.ErrorIs(t, err, ErrPlatformRequired)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromoteWishlistItem_NotFound(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(wishlistLookupQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err := NewWishlistService(conn).PromoteWishlistItem(1, "7", models.PromoteInput{Platform: "PC"})
+
+	assert.ErrorIs(t, err, ErrWishlistItemNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateWishlistItem_DefaultsPriority(t *testing.T) {
+	conn, mock, _ := newMockDB(t)
+	mock.ExpectQuery(wishlistLookupQuery).WillReturnRows(wishlistRow("PC"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `wishlist_items` SET `title`=\\?,`platform`=\\?,`target_price`=\\?,`release_date`=\\?,`priority`=\\?,`note`=\\?,`updated_at`=\\? WHERE `id` = \\?").
+		WithArgs("Silksong", "PC", 19.99, nil, models.DefaultWishlistPriority, "", sqlmock.AnyArg(), uint(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	price := 19.99
+	item, err := NewWishlistService(conn).UpdateWishlistItem(1, "7", models.WishlistInput{Title: "Silksong", Platform: "PC", TargetPrice: &price})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultWishlistPriority, item.Priority)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal_PutGetDelete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	s, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	// Act
+	require.NoError(t, s.Put(ctx, "covers/1/abc/small.jpg", strings.NewReader("jpeg"), "image/jpeg"))
+	require.NoError(t, s.Put(ctx, "covers/1/abc/original.png", strings.NewReader("png!"), "image/png"))
+	body, obj, err := s.Get(ctx, "covers/1/abc/small.jpg")
+
+	// Assert
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "jpeg", string(data))
+	assert.Equal(t, "image/jpeg", obj.ContentType)
+	assert.Equal(t, int64(4), obj.Size)
+
+	// Delete borra el prefijo completo
+	require.NoError(t, s.Delete(ctx, "covers/1/abc"))
+	_, _, err = s.Get(ctx, "covers/1/abc/original.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, s.Delete(ctx, "covers/1/abc"))
+}
+
+func TestLocal_PutOverwrites(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "a/b.txt", strings.NewReader("first"), ""))
+	require.NoError(t, s.Put(ctx, "a/b.txt", strings.NewReader("second"), ""))
+
+	body, _, err := s.Get(ctx, "a/b.txt")
+	require.NoError(t, err)
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	assert.Equal(t, "second", string(data))
+}
+
+func TestLocal_RejectsInvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../x", "a/../../x", "a//b", "a\\b", "a/./b"} {
+		t.Run(key, func(t *testing.T) {
+			assert.ErrorIs(t, s.Put(ctx, key, strings.NewReader("x"), ""), ErrInvalidKey)
+			_, _, err := s.Get(ctx, key)
+			assert.ErrorIs(t, err, ErrInvalidKey)
+			assert.ErrorIs(t, s.Delete(ctx, key), ErrInvalidKey)
+		})
+	}
+}
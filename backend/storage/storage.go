@@ -0,0 +1,51 @@
+// Package storage guarda archivos subidos por los usuarios (por ahora las
+// portadas de los juegos) detrás de una interfaz, para poder cambiar el
+// disco local por un bucket compatible con S3 sin tocar el resto.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound se devuelve cuando la clave no existe.
+	ErrNotFound = errors.New("object not found")
+	// ErrInvalidKey se devuelve para claves vacías, absolutas o con "..".
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Object describe un archivo guardado.
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// Storage guarda archivos por clave ("covers/12/abc/160.jpg"). Las claves
+// usan "/" como separador en todos los backends.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get devuelve el contenido, que el llamador tiene que cerrar.
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	// Delete borra todas las claves que empiezan con prefix. No es error
+	// que no haya ninguna.
+	Delete(ctx context.Context, prefix string) error
+}
+
+// validKey rechaza claves que podrían salir de la raíz del backend.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -39,8 +39,8 @@ function App() {
 
         const fetchGames = async () => {
             try {
-                const res = await getGames()
-                setGames(res.data)
+                const res = await getGames({ pageSize: 100 })
+                setGames(res.data.items)
             } catch (err) {
                 console.error("Error cargando juegos:", err)
             }
@@ -48,6 +48,8 @@ export default function GameForm({ onGameAdded, gameToEdit, onEditComplete }: Ga
             startedAt: gameToEdit ? gameToEdit.startedAt : new Date().toISOString(),
             finishedAt: gameToEdit ? gameToEdit.finishedAt : new Date().toISOString(),
             coverURL: gameToEdit ? gameToEdit.coverURL : "",
+            // Si otro cliente lo modificó mientras se editaba, el backend responde 412
+            ...(gameToEdit ? { version: gameToEdit.version } : {}),
         }
 
         try {
@@ -93,8 +95,10 @@ export default function GameForm({ onGameAdded, gameToEdit, onEditComplete }: Ga
                     onChange={e => setStatus(e.target.value)}
                     className="w-full p-2 border rounded text-sm"
                 >
+                    <option value="Wishlist">Wishlist</option>
                     <option value="Backlog">Backlog</option>
                     <option value="Playing">Playing</option>
+                    <option value="Paused">Paused</option>
                     <option value="Completed">Completed</option>
                     <option value="Dropped">Dropped</option>
                 </select>
@@ -1,5 +1,5 @@
 import React, { createContext, useContext, useState, useEffect, type ReactNode } from 'react'
-import { login as loginAPI, register as registerAPI, type User, type LoginRequest, type RegisterRequest } from '@/services/api'
+import { login as loginAPI, register as registerAPI, logout as logoutAPI, type User, type LoginRequest, type RegisterRequest } from '@/services/api'
 
 export interface AuthContextType {
     user: User | null
@@ -55,13 +55,14 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
         try {
             setIsLoading(true)
             const response = await loginAPI(data)
-            const { token: newToken, user: newUser } = response.data
+            const { token: newToken, refreshToken, user: newUser } = response.data
             
             setToken(newToken)
             setUser(newUser)
             
             // Guardar en localStorage
             localStorage.setItem('token', newToken)
+            localStorage.setItem('refreshToken', refreshToken)
             localStorage.setItem('user', JSON.stringify(newUser))
         } catch (error) {
             console.error('Login error:', error)
@@ -94,9 +95,15 @@ export const AuthProvider: React.FC<AuthProviderProps> = ({ children }) => {
     }
 
     const logout = () => {
+        // Revocar la sesión en el backend; si falla igual limpiamos el estado local
+        const refreshToken = localStorage.getItem('refreshToken')
+        if (refreshToken) {
+            logoutAPI(refreshToken).catch((error) => console.error('Logout error:', error))
+        }
         setUser(null)
         setToken(null)
         localStorage.removeItem('token')
+        localStorage.removeItem('refreshToken')
         localStorage.removeItem('user')
     }
 
@@ -5,6 +5,7 @@ import type { AxiosInstance, AxiosRequestConfig, AxiosResponse } from 'axios'
 // ⬇️ Importá SOLO TIPOS del módulo (no ejecuta código en runtime)
 import type {
   Game,
+  GamePage,
   GameStats,
   LoginRequest,
   RegisterRequest,
@@ -70,6 +71,7 @@ describe('API Service', () => {
   describe('Game endpoints', () => {
     const mockGame: Game = {
       id: 1,
+      userId: 1,
       title: 'Test Game',
       platform: 'PC',
       genre: 'RPG',
@@ -81,16 +83,19 @@ describe('API Service', () => {
       startedAt: '2024-01-01T00:00:00Z',
       finishedAt: '2024-01-15T00:00:00Z',
       coverURL: 'http://example.com/cover.jpg',
+      version: 1,
       createdAt: '2024-01-01T00:00:00Z',
       updatedAt: '2024-01-15T00:00:00Z',
     }
 
     it('should get all games', async () => {
-      const mockResponse: AxiosResponse<Game[]> = { data: [mockGame] } as AxiosResponse<Game[]>
+      const mockPage: GamePage = { items: [mockGame], total: 1, page: 1, pageSize: 20 }
+      const mockResponse: AxiosResponse<GamePage> = { data: mockPage } as AxiosResponse<GamePage>
       instance.get.mockResolvedValue(mockResponse)
 
       const result = await api.getGames()
-      expect(result.data).toEqual([mockGame])
+      expect(result.data.items).toEqual([mockGame])
+      expect(result.data.total).toBe(1)
       expect(instance.get).toHaveBeenCalled()
     })
 
@@ -153,6 +158,13 @@ describe('API Service', () => {
           Playing: 2,
           'Not Started': 3,
         },
+        total_hours_played: 155,
+        completion_rate: 0.5,
+        hours_by_genre: [{ rank: 1, genre: 'RPG', games: 4, hours: 90 }],
+        hours_by_platform: [{ platform: 'PC', games: 10, hours: 155 }],
+        average_score_by_genre: [{ genre: 'RPG', scored_games: 4, average_score: 8.5 }],
+        playtime: { games: 8, p25: 5, median: 12, p75: 20, p90: 40, max: 60 },
+        backlog: { games: 3, average_days: 40, median_days: 30, oldest_days: 90, oldest_title: 'Old' },
       }
       const mockResponse: AxiosResponse<GameStats> = { data: mockStats } as AxiosResponse<GameStats>
       instance.get.mockResolvedValue(mockResponse)
@@ -166,6 +178,7 @@ describe('API Service', () => {
   describe('Auth endpoints', () => {
     const mockAuthResponse: AuthResponse = {
       token: 'mock-jwt-token',
+      refreshToken: 'mock-refresh-token',
       user: {
         id: 1,
         username: 'testuser',
@@ -1,28 +1,114 @@
-import axios from "axios"
+import axios, { type InternalAxiosRequestConfig } from "axios"
 
 // Definimos el tipo de juego
 export interface Game {
     id: number
+    userId: number
     title: string
     platform: string
     genre: string
     status: string
     progress: number
+    // con true el backend calcula progress a partir de los logros
+    progressFromAchievements?: boolean
     hoursPlayed: number
     personalNote: string
     score: number
     startedAt: string
     finishedAt: string
+    // URL externa o, si se subió una portada, `/games/:id/cover?v=<hash>`
     coverURL: string
+    releaseDate: string | null
+    developer: string
+    version: number
     createdAt: string
     updatedAt: string
+    deletedAt?: string | null
 }
+export interface GamePage {
+    items: Game[]
+    total: number
+    page?: number
+    pageSize: number
+    nextCursor?: string
+}
+
+// highlights trae HTML escapado con los términos envueltos en <mark>
+export interface GameSearchResult {
+    game: Game
+    relevance: number
+    highlights: {
+        title: string
+        personalNote?: string
+    }
+}
+
+export interface GameListParams {
+    page?: number
+    pageSize?: number
+    cursor?: string
+    sort?: string
+    title?: string
+    status?: string
+    genre?: string
+    platform?: string
+    minScore?: number
+    maxScore?: number
+    startedFrom?: string
+    startedTo?: string
+    finishedFrom?: string
+    finishedTo?: string
+    tag?: string[] // el juego tiene que tener todas
+}
+
 export interface GameStats {
     total_games: number
     average_hours_played: number
     most_played_genre: string
     pending_games: number
     by_status: Record<string, number>
+    total_hours_played: number
+    completion_rate: number
+    hours_by_genre: { rank: number; genre: string; games: number; hours: number }[]
+    hours_by_platform: { platform: string; games: number; hours: number }[]
+    average_score_by_genre: { genre: string; scored_games: number; average_score: number }[]
+    playtime: { games: number; p25: number; median: number; p75: number; p90: number; max: number }
+    backlog: { games: number; average_days: number; median_days: number; oldest_days: number; oldest_title?: string }
+    hours_by_tag: { tag: string; games: number; hours: number; completed: number }[]
+}
+
+// Filtros de estadísticas: los mismos que el listado, sin paginación ni orden
+export type GameStatsParams = Omit<GameListParams, 'page' | 'pageSize' | 'cursor' | 'sort'>
+
+export interface TimelineBucket {
+    period: string
+    start: string
+    added: number
+    started: number
+    finished: number
+    hours: number
+    session_hours: number
+}
+export type TimelineParams = GameStatsParams & { interval?: 'month' | 'week'; from?: string; to?: string }
+
+export interface YearGame {
+    id: number
+    title: string
+    genre: string
+    hours: number
+    days?: number
+}
+export interface YearReview {
+    year: number
+    games_added: number
+    games_started: number
+    games_finished: number
+    hours_played: number
+    top_games: YearGame[]
+    longest_game: YearGame | null
+    genres_discovered: string[]
+    completion_streak: { months: number; from?: string; to?: string }
+    months: TimelineBucket[]
 }
 
 const API = axios.create({
@@ -30,6 +116,8 @@ const API = axios.create({
     headers: {
         "Content-Type": "application/json",
     },
+    // ?tag=a&tag=b en lugar de ?tag[]=a&tag[]=b, que es lo que lee gin
+    paramsSerializer: { indexes: null },
 })
 
 // Interceptor para agregar token a las peticiones
@@ -49,10 +137,27 @@ API.interceptors.request.use(
 // Interceptor para manejar respuestas de error
 API.interceptors.response.use(
     (response) => response,
-    (error) => {
+    async (error) => {
+        const original = error.config as (InternalAxiosRequestConfig & { _retry?: boolean }) | undefined
+        const refreshToken = localStorage.getItem('refreshToken')
+
+        // Access token vencido: intentamos rotar el refresh token una sola vez
+        if (error.response?.status === 401 && refreshToken && original && !original._retry && !original.url?.startsWith('/auth/')) {
+            original._retry = true
+            try {
+                const { data } = await API.post<AuthResponse>("/auth/refresh", { refreshToken })
+                localStorage.setItem('token', data.token)
+                localStorage.setItem('refreshToken', data.refreshToken)
+                return API(original)
+            } catch {
+                // Sesión revocada o refresh vencido: cerramos sesión abajo
+            }
+        }
+
         if (error.response?.status === 401) {
             // Token expirado o inválido
             localStorage.removeItem('token')
+            localStorage.removeItem('refreshToken')
             localStorage.removeItem('user')
             window.location.reload()
         }
@@ -61,13 +166,265 @@ API.interceptors.response.use(
 )
 
 // Game endpoints
-export const getGames = () => API.get<Game[]>("/games/")
+export const getGames = (params?: GameListParams) => API.get<GamePage>("/games/", { params })
 export const getGameById = (id: number) => API.get<Game>(`/games/${id}`)
-export const searchGameByTitle = (title: string) => API.get<Game[]>(`/games/search?title=${title}`)
+export const searchGames = (q: string, limit?: number) => API.get<GameSearchResult[]>("/games/search", { params: { q, limit } })
 export const createGame = (data: Partial<Game>) => API.post("/games/", data)
-export const getStats = () => API.get("/stats")
+export const getStats = (params?: GameStatsParams) => API.get<GameStats>("/games/stats", { params })
+export const getStatsTimeline = (params?: TimelineParams) => API.get<TimelineBucket[]>("/games/stats/timeline", { params })
+export const getYearReview = (year: number) => API.get<YearReview>(`/games/stats/year/${year}`)
 export const updateGame = (id: number, data: Partial<Game>) => API.put<Game>(`/games/${id}`, data)
+// JSON Merge Patch: solo los campos enviados cambian, null los vacía
+export const patchGame = (id: number, data: Partial<Game>, version?: number) =>
+    API.patch<Game>(`/games/${id}`, data, {
+        headers: {
+            'Content-Type': 'application/merge-patch+json',
+            ...(version !== undefined ? { 'If-Match': `"${version}"` } : {}),
+        },
+    })
 export const deleteGame = (id: number) => API.delete(`/games/${id}`)
+export const getTrash = () => API.get<Game[]>('/games/trash')
+export const restoreGame = (id: number) => API.post<Game>(`/games/${id}/restore`)
+
+// Completa género, portada, fecha de salida y desarrollador con el proveedor
+// de metadatos configurado
+export interface GameMetadata {
+    externalId: string
+    title: string
+    genres: string[]
+    releaseDate: string | null
+    developer: string
+    coverURL: string
+}
+export interface GameEnrichment {
+    game: Game
+    metadata: GameMetadata
+    filled: string[]
+}
+export const enrichGame = (id: number, externalId?: string) =>
+    API.post<GameEnrichment>(`/games/${id}/enrich`, externalId ? { externalId } : undefined)
+
+// Portada subida por el usuario y sus miniaturas
+export type CoverSize = 'original' | 'small' | 'medium' | 'large'
+export interface GameCover {
+    gameId: number
+    hash: string
+    contentType: string
+    width: number
+    height: number
+    size: number
+    createdAt: string
+    updatedAt: string
+    urls: Record<CoverSize, string>
+}
+export const uploadCover = (id: number, file: File) => {
+    const form = new FormData()
+    form.append('cover', file)
+    return API.post<GameCover>(`/games/${id}/cover`, form)
+}
+// La API requiere Authorization, así que la imagen se pide como blob
+export const getCover = (id: number, size: CoverSize = 'original') =>
+    API.get<Blob>(`/games/${id}/cover`, { params: { size }, responseType: 'blob' })
+export const deleteCover = (id: number) => API.delete(`/games/${id}/cover`)
+
+// Wishlist: juegos que todavía no están en la biblioteca
+export interface WishlistItem {
+    id: number
+    userId: number
+    title: string
+    platform: string
+    targetPrice?: number | null
+    releaseDate?: string | null
+    priority: number // 1 (baja) a 5 (alta)
+    note: string
+    createdAt: string
+    updatedAt: string
+}
+export type WishlistInput = Pick<WishlistItem, 'title'> & Partial<Pick<WishlistItem, 'platform' | 'targetPrice' | 'releaseDate' | 'priority' | 'note'>>
+export const getWishlist = () => API.get<WishlistItem[]>('/wishlist/')
+export const getUpcomingReleases = (days?: number) => API.get<WishlistItem[]>('/wishlist/upcoming', { params: { days } })
+export const getWishlistItem = (id: number) => API.get<WishlistItem>(`/wishlist/${id}`)
+export const createWishlistItem = (data: WishlistInput) => API.post<WishlistItem>('/wishlist/', data)
+export const updateWishlistItem = (id: number, data: WishlistInput) => API.put<WishlistItem>(`/wishlist/${id}`, data)
+export const deleteWishlistItem = (id: number) => API.delete(`/wishlist/${id}`)
+// 409 con { gameId } si el juego ya está en la biblioteca en esa plataforma
+export const promoteWishlistItem = (id: number, data?: { platform?: string; status?: Game['status'] }) =>
+    API.post<Game>(`/wishlist/${id}/promote`, data)
+
+// Etiquetas (varias por juego) y colecciones armadas por el usuario
+export interface Tag {
+    id: number
+    userId: number
+    name: string
+    games: number
+    createdAt: string
+    updatedAt: string
+}
+export interface Collection {
+    id: number
+    userId: number
+    name: string
+    description: string
+    games: number
+    createdAt: string
+    updatedAt: string
+}
+export const getTags = () => API.get<Tag[]>('/tags/')
+export const createTag = (name: string) => API.post<Tag>('/tags/', { name })
+export const renameTag = (id: number, name: string) => API.put<Tag>(`/tags/${id}`, { name })
+export const deleteTag = (id: number) => API.delete(`/tags/${id}`)
+export const getGameTags = (gameId: number) => API.get<Tag[]>(`/games/${gameId}/tags`)
+// Reemplaza todas las etiquetas del juego; las que no existen se crean
+export const setGameTags = (gameId: number, tags: string[]) => API.put<Tag[]>(`/games/${gameId}/tags`, { tags })
+
+export const getCollections = () => API.get<Collection[]>('/collections/')
+export const getCollection = (id: number) => API.get<Collection>(`/collections/${id}`)
+export const createCollection = (data: { name: string; description?: string }) => API.post<Collection>('/collections/', data)
+export const updateCollection = (id: number, data: { name: string; description?: string }) =>
+    API.put<Collection>(`/collections/${id}`, data)
+export const deleteCollection = (id: number) => API.delete(`/collections/${id}`)
+export const getCollectionGames = (id: number, params?: GameListParams) =>
+    API.get<GamePage>(`/collections/${id}/games`, { params })
+export const getGameCollections = (gameId: number) => API.get<Collection[]>(`/games/${gameId}/collections`)
+export const addGameToCollection = (gameId: number, collectionId: number) =>
+    API.post(`/games/${gameId}/collections/${collectionId}`)
+export const removeGameFromCollection = (gameId: number, collectionId: number) =>
+    API.delete(`/games/${gameId}/collections/${collectionId}`)
+
+// Export/import de la biblioteca (mismo formato en ambos sentidos)
+export type TransferFormat = 'csv' | 'json'
+export interface ImportRow {
+    line: number
+    title: string
+    platform: string
+    action: 'created' | 'updated' | 'wishlisted' | 'skipped' | 'error'
+    gameId?: number
+    wishlistItemId?: number
+    message?: string
+    errors?: { field: string; message: string }[]
+}
+export interface ImportReport {
+    dryRun: boolean
+    created: number
+    updated: number
+    wishlisted: number
+    skipped: number
+    errored: number
+    rows: ImportRow[]
+}
+export interface ImportOptions {
+    dryRun?: boolean
+    onDuplicate?: 'skip' | 'update'
+}
+export const exportGames = (format: TransferFormat = 'json') =>
+    API.get<Blob>('/games/export', { params: { format }, responseType: 'blob' })
+export const importGames = (file: File, options?: ImportOptions) => {
+    const form = new FormData()
+    form.append('file', file)
+    return API.post<ImportReport>('/games/import', form, {
+        params: options,
+        headers: { 'Content-Type': 'multipart/form-data' },
+    })
+}
+// Archivos exportados por otros servicios; el reporte es el mismo que importGames
+export type ImportSource = 'steam' | 'gog' | 'backloggd' | 'hltb'
+export const importFromSource = (source: ImportSource, file: File, options?: ImportOptions) => {
+    const form = new FormData()
+    form.append('file', file)
+    return API.post<ImportReport>(`/games/import/${source}`, form, {
+        params: options,
+        headers: { 'Content-Type': 'multipart/form-data' },
+    })
+}
+
+// Sesiones de juego: las horas del juego se calculan a partir de ellas
+export interface PlaySession {
+    id: number
+    gameId: number
+    startedAt: string
+    endedAt: string | null
+    durationMinutes: number
+    note: string
+    ownershipId: number | null
+    createdAt: string
+    updatedAt: string
+}
+export interface PlaySessionInput {
+    startedAt: string
+    endedAt?: string
+    durationMinutes?: number
+    note?: string
+    ownershipId?: number | null
+}
+export const getSessions = (gameId: number) => API.get<PlaySession[]>(`/games/${gameId}/sessions`)
+export const createSession = (gameId: number, data: PlaySessionInput) =>
+    API.post<PlaySession>(`/games/${gameId}/sessions`, data)
+export const updateSession = (gameId: number, sessionId: number, data: PlaySessionInput) =>
+    API.put<PlaySession>(`/games/${gameId}/sessions/${sessionId}`, data)
+export const deleteSession = (gameId: number, sessionId: number) =>
+    API.delete(`/games/${gameId}/sessions/${sessionId}`)
+export const startSession = (gameId: number, ownershipId?: number) =>
+    API.post<PlaySession>(`/games/${gameId}/sessions/start`, null, { params: { ownershipId } })
+export const stopSession = (gameId: number) => API.post<PlaySession>(`/games/${gameId}/sessions/stop`)
+
+// Copias del juego por plataforma: sus horas se suman a las del juego
+export type OwnershipFormat = 'physical' | 'digital'
+export interface GameOwnership {
+    id: number
+    gameId: number
+    platform: string
+    store: string
+    edition: string
+    purchasedAt: string | null
+    pricePaid: number | null
+    format: OwnershipFormat | ''
+    hoursPlayed: number
+    createdAt: string
+    updatedAt: string
+}
+export interface OwnershipInput {
+    platform: string
+    store?: string
+    edition?: string
+    purchasedAt?: string
+    pricePaid?: number
+    format?: OwnershipFormat
+    hoursPlayed?: number
+}
+export const getOwnerships = (gameId: number) => API.get<GameOwnership[]>(`/games/${gameId}/ownerships`)
+export const createOwnership = (gameId: number, data: OwnershipInput) =>
+    API.post<GameOwnership>(`/games/${gameId}/ownerships`, data)
+export const updateOwnership = (gameId: number, ownershipId: number, data: OwnershipInput) =>
+    API.put<GameOwnership>(`/games/${gameId}/ownerships/${ownershipId}`, data)
+export const deleteOwnership = (gameId: number, ownershipId: number) =>
+    API.delete(`/games/${gameId}/ownerships/${ownershipId}`)
+
+// Logros y checklist de completado de cada juego
+export interface Achievement {
+    id: number
+    gameId: number
+    name: string
+    description: string
+    unlocked: boolean
+    unlockedAt: string | null
+    createdAt: string
+    updatedAt: string
+}
+export interface AchievementInput {
+    name: string
+    description?: string
+    unlocked?: boolean
+    unlockedAt?: string
+}
+export const getAchievements = (gameId: number) => API.get<Achievement[]>(`/games/${gameId}/achievements`)
+export const createAchievement = (gameId: number, data: AchievementInput) =>
+    API.post<Achievement>(`/games/${gameId}/achievements`, data)
+export const importAchievements = (gameId: number, achievements: AchievementInput[], replace = false) =>
+    API.post<Achievement[]>(`/games/${gameId}/achievements/import`, { achievements, replace })
+export const toggleAchievement = (gameId: number, achievementId: number) =>
+    API.post<Achievement>(`/games/${gameId}/achievements/${achievementId}/toggle`)
+export const deleteAchievement = (gameId: number, achievementId: number) =>
+    API.delete(`/games/${gameId}/achievements/${achievementId}`)
 
 // Auth endpoints
 export interface LoginRequest {
@@ -95,11 +452,14 @@ export interface User {
 
 export interface AuthResponse {
     token: string
+    refreshToken: string
     user: User
 }
 
 export const login = (data: LoginRequest) => API.post<AuthResponse>("/auth/login", data)
 export const register = (data: RegisterRequest) => API.post<AuthResponse>("/auth/register", data)
+export const refreshSession = (refreshToken: string) => API.post<AuthResponse>("/auth/refresh", { refreshToken })
+export const logout = (refreshToken: string) => API.post("/auth/logout", { refreshToken })
 export const getProfile = () => API.get("/api/profile")
 
 export default API